@@ -0,0 +1,24 @@
+-- +goose Up
+-- Колонка VERSION для оптимистичной блокировки
+ALTER TABLE ENUMERATION ADD COLUMN VERSION INT UNSIGNED NOT NULL DEFAULT 1;
+ALTER TABLE ENUMERATIONVALUE ADD COLUMN VERSION INT UNSIGNED NOT NULL DEFAULT 1;
+ALTER TABLE PERSON ADD COLUMN VERSION INT UNSIGNED NOT NULL DEFAULT 1;
+ALTER TABLE CATEGORY ADD COLUMN VERSION INT UNSIGNED NOT NULL DEFAULT 1;
+ALTER TABLE PRODUCT ADD COLUMN VERSION INT UNSIGNED NOT NULL DEFAULT 1;
+ALTER TABLE PRODUCTMEDIA ADD COLUMN VERSION INT UNSIGNED NOT NULL DEFAULT 1;
+ALTER TABLE CART ADD COLUMN VERSION INT UNSIGNED NOT NULL DEFAULT 1;
+ALTER TABLE CARTITEM ADD COLUMN VERSION INT UNSIGNED NOT NULL DEFAULT 1;
+ALTER TABLE ORDERS ADD COLUMN VERSION INT UNSIGNED NOT NULL DEFAULT 1;
+ALTER TABLE ORDERITEM ADD COLUMN VERSION INT UNSIGNED NOT NULL DEFAULT 1;
+
+-- +goose Down
+ALTER TABLE ORDERITEM DROP COLUMN VERSION;
+ALTER TABLE ORDERS DROP COLUMN VERSION;
+ALTER TABLE CARTITEM DROP COLUMN VERSION;
+ALTER TABLE CART DROP COLUMN VERSION;
+ALTER TABLE PRODUCTMEDIA DROP COLUMN VERSION;
+ALTER TABLE PRODUCT DROP COLUMN VERSION;
+ALTER TABLE CATEGORY DROP COLUMN VERSION;
+ALTER TABLE PERSON DROP COLUMN VERSION;
+ALTER TABLE ENUMERATIONVALUE DROP COLUMN VERSION;
+ALTER TABLE ENUMERATION DROP COLUMN VERSION;
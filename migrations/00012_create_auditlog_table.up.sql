@@ -0,0 +1,21 @@
+-- +goose Up
+-- Создание таблицы AUDITLOG: журнал изменений сущностей
+CREATE TABLE IF NOT EXISTS AUDITLOG (
+    ID INT AUTO_INCREMENT PRIMARY KEY,
+    CREATEDAT TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UPDATEDAT TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    VERSION INT UNSIGNED NOT NULL DEFAULT 1,
+    ENTITY VARCHAR(64) NOT NULL,
+    ENTITYID INT NOT NULL,
+    OPERATION VARCHAR(16) NOT NULL,
+    ACTOR VARCHAR(255) NOT NULL,
+    REQUESTID VARCHAR(255) NOT NULL DEFAULT '',
+    CHANGES JSON NOT NULL
+);
+
+CREATE INDEX ix_auditlog_entity ON AUDITLOG(ENTITY, ENTITYID);
+CREATE INDEX ix_auditlog_actor ON AUDITLOG(ACTOR);
+CREATE INDEX ix_auditlog_createdat ON AUDITLOG(CREATEDAT);
+
+-- +goose Down
+DROP TABLE IF EXISTS AUDITLOG;
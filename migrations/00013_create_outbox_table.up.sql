@@ -0,0 +1,23 @@
+-- +goose Up
+-- Создание таблицы OUTBOX: доменные события, ожидающие доставки получателям
+CREATE TABLE IF NOT EXISTS OUTBOX (
+    ID INT AUTO_INCREMENT PRIMARY KEY,
+    CREATEDAT TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UPDATEDAT TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    VERSION INT UNSIGNED NOT NULL DEFAULT 1,
+    EVENTTYPE VARCHAR(128) NOT NULL,
+    AGGREGATETYPE VARCHAR(64) NOT NULL,
+    AGGREGATEID INT NOT NULL,
+    PAYLOAD JSON NOT NULL,
+    STATUS VARCHAR(16) NOT NULL DEFAULT 'pending',
+    ATTEMPTS INT UNSIGNED NOT NULL DEFAULT 0,
+    NEXTATTEMPTAT TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    SENTAT TIMESTAMP NULL,
+    LASTERROR TEXT NULL
+);
+
+CREATE INDEX ix_outbox_status_nextattemptat ON OUTBOX(STATUS, NEXTATTEMPTAT);
+CREATE INDEX ix_outbox_aggregate ON OUTBOX(AGGREGATETYPE, AGGREGATEID);
+
+-- +goose Down
+DROP TABLE IF EXISTS OUTBOX;
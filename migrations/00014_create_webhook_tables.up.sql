@@ -0,0 +1,38 @@
+-- +goose Up
+-- Создание таблицы WEBHOOKSUBSCRIPTION: подписки внешних систем на события
+CREATE TABLE IF NOT EXISTS WEBHOOKSUBSCRIPTION (
+    ID INT AUTO_INCREMENT PRIMARY KEY,
+    CREATEDAT TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UPDATEDAT TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    VERSION INT UNSIGNED NOT NULL DEFAULT 1,
+    URL VARCHAR(2048) NOT NULL,
+    SECRET VARCHAR(255) NOT NULL,
+    EVENTTYPES VARCHAR(1024) NOT NULL,
+    ACTIVE BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+-- Создание таблицы WEBHOOKDELIVERY: журнал доставок событий подпискам
+CREATE TABLE IF NOT EXISTS WEBHOOKDELIVERY (
+    ID INT AUTO_INCREMENT PRIMARY KEY,
+    CREATEDAT TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UPDATEDAT TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    VERSION INT UNSIGNED NOT NULL DEFAULT 1,
+    SUBSCRIPTIONID INT NOT NULL,
+    EVENTID INT NOT NULL,
+    EVENTTYPE VARCHAR(128) NOT NULL,
+    PAYLOAD JSON NOT NULL,
+    STATUS VARCHAR(16) NOT NULL DEFAULT 'pending',
+    ATTEMPTS INT UNSIGNED NOT NULL DEFAULT 0,
+    NEXTATTEMPTAT TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    RESPONSECODE INT NULL,
+    LASTERROR TEXT NULL,
+    DELIVEREDAT TIMESTAMP NULL,
+    CONSTRAINT uq_webhookdelivery_subscription_event UNIQUE (SUBSCRIPTIONID, EVENTID)
+);
+
+CREATE INDEX ix_webhookdelivery_status_nextattemptat ON WEBHOOKDELIVERY(STATUS, NEXTATTEMPTAT);
+CREATE INDEX ix_webhookdelivery_eventid ON WEBHOOKDELIVERY(EVENTID);
+
+-- +goose Down
+DROP TABLE IF EXISTS WEBHOOKDELIVERY;
+DROP TABLE IF EXISTS WEBHOOKSUBSCRIPTION;
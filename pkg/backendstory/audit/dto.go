@@ -0,0 +1,32 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditLogDTO represents audit log entry data transfer object
+// @Name AuditLogDTO
+type AuditLogDTO struct {
+	ID        uint            `json:"id"`
+	CreatedAt time.Time       `json:"created_at"`
+	Entity    string          `json:"entity"`
+	EntityID  uint            `json:"entity_id"`
+	Operation string          `json:"operation"`
+	Actor     string          `json:"actor"`
+	RequestID string          `json:"request_id"`
+	Changes   json.RawMessage `json:"changes" swaggertype:"object"`
+}
+
+func ToAuditLogDTO(auditLog AuditLog) AuditLogDTO {
+	return AuditLogDTO{
+		ID:        auditLog.ID,
+		CreatedAt: auditLog.CreatedAt,
+		Entity:    auditLog.Entity,
+		EntityID:  auditLog.EntityID,
+		Operation: auditLog.Operation,
+		Actor:     auditLog.Actor,
+		RequestID: auditLog.RequestID,
+		Changes:   json.RawMessage(auditLog.Changes),
+	}
+}
@@ -0,0 +1,43 @@
+package audit
+
+import "github.com/ActuallyHello/backendstory/pkg/core"
+
+const (
+	// SystemActor автор изменений, выполненных без пользователя (фоновые задачи, старт приложения)
+	SystemActor = "system"
+)
+
+type AuditLog struct {
+	core.Base
+
+	Entity    string `gorm:"column:ENTITY"`
+	EntityID  uint   `gorm:"column:ENTITYID"`
+	Operation string `gorm:"column:OPERATION"`
+	Actor     string `gorm:"column:ACTOR"`
+	RequestID string `gorm:"column:REQUESTID"`
+	// Changes измененные поля в формате {"поле": {"before": ..., "after": ...}}
+	Changes string `gorm:"column:CHANGES"`
+}
+
+func (AuditLog) TableName() string {
+	return "AUDITLOG"
+}
+
+func (AuditLog) LocalTableName() string {
+	return "Запись журнала аудита"
+}
+
+// Unaudited журнал аудита не записывает изменения самого себя
+func (AuditLog) Unaudited() {}
+
+var auditLogSearchFields = core.NewSearchFields(
+	core.SearchField{Name: "entity", Column: "ENTITY", Type: core.FieldString, Sortable: true},
+	core.SearchField{Name: "entity_id", Column: "ENTITYID", Type: core.FieldUint, Sortable: true},
+	core.SearchField{Name: "operation", Column: "OPERATION", Type: core.FieldString, Sortable: true},
+	core.SearchField{Name: "actor", Column: "ACTOR", Type: core.FieldString, Sortable: true},
+	core.SearchField{Name: "request_id", Column: "REQUESTID", Type: core.FieldString},
+)
+
+func (AuditLog) SearchFields() *core.SearchFields {
+	return auditLogSearchFields
+}
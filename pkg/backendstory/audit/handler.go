@@ -0,0 +1,139 @@
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ActuallyHello/backendstory/pkg/core"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	auditLogHandlerCode = "AUDITLOG_HANDLER"
+)
+
+type AuditLogHandler struct {
+	validate        *validator.Validate
+	auditLogService AuditLogService
+}
+
+func NewAuditLogHandler(
+	auditLogService AuditLogService,
+) *AuditLogHandler {
+	return &AuditLogHandler{
+		validate:        core.DefaultValidator(),
+		auditLogService: auditLogService,
+	}
+}
+
+// GetAll возвращает записи журнала аудита
+// @Summary Получить журнал аудита
+// @Description Возвращает записи журнала аудита постранично, с фильтрацией и сортировкой из строки запроса
+// @Tags Audit
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param filter query string false "Условия через ';', например entity==PRODUCT;entity_id==3;actor==admin"
+// @Param sort query string false "Поля сортировки через ',', префикс '-' - по убыванию, например -created_at"
+// @Param limit query int false "Размер страницы" default(20)
+// @Param offset query int false "Смещение"
+// @Param after query string false "Курсор следующей страницы"
+// @Param before query string false "Курсор предыдущей страницы"
+// @Success 200 {object} core.Page[AuditLogDTO] "Записи журнала аудита"
+// @Failure 400 {object} core.ErrorResponse "Некорректные параметры фильтра"
+// @Failure 401 {object} core.ErrorResponse "Не авторизован"
+// @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
+// @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /audit [get]
+// @Id getAuditLogAll
+func (h *AuditLogHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	criteria, err := core.ParseSearchQuery(r.URL.RawQuery)
+	if err != nil {
+		core.HandleError(w, r, err)
+		return
+	}
+
+	h.writePage(w, r, criteria)
+}
+
+// GetById возвращает запись журнала аудита по ID
+// @Summary Получить запись журнала аудита по ID
+// @Description Возвращает запись журнала аудита по указанному идентификатору
+// @Tags Audit
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID записи журнала"
+// @Success 200 {object} AuditLogDTO "Запись журнала аудита"
+// @Failure 400 {object} core.ErrorResponse "Неверный ID"
+// @Failure 401 {object} core.ErrorResponse "Не авторизован"
+// @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} core.ErrorResponse "Запись не найдена"
+// @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /audit/{id} [get]
+// @Id getAuditLogById
+func (h *AuditLogHandler) GetById(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := core.PathID(r, "id")
+	if err != nil {
+		core.HandleError(w, r, err)
+		return
+	}
+
+	auditLog, err := h.auditLogService.GetByID(ctx, id)
+	if err != nil {
+		core.HandleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(ToAuditLogDTO(auditLog))
+}
+
+// GetWithSearchCriteria возвращает записи журнала аудита по критериям поиска
+// @Summary Поиск по журналу аудита
+// @Description Возвращает записи журнала аудита по указанным критериям поиска с пагинацией
+// @Tags Audit
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body core.SearchCriteria true "Критерии поиска"
+// @Success 200 {object} core.Page[AuditLogDTO] "Записи журнала аудита"
+// @Failure 400 {object} core.ErrorResponse "Неверные критерии поиска"
+// @Failure 401 {object} core.ErrorResponse "Не авторизован"
+// @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
+// @Failure 422 {object} core.ValidationErrorResponse "Ошибка валидации"
+// @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /audit/search [post]
+// @Id searchAuditLog
+func (h *AuditLogHandler) GetWithSearchCriteria(w http.ResponseWriter, r *http.Request) {
+	var req core.SearchCriteria
+	if err := core.DecodeJSON(r, &req); err != nil {
+		core.HandleError(w, r, core.NewTechnicalError(err, auditLogHandlerCode, err.Error()))
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		details := core.CollectValidationDetails(r.Context(), err)
+		core.HandleValidationError(w, r, core.NewLogicalError(err, auditLogHandlerCode, err.Error()), details)
+		return
+	}
+
+	h.writePage(w, r, req)
+}
+
+func (h *AuditLogHandler) writePage(w http.ResponseWriter, r *http.Request, criteria core.SearchCriteria) {
+	page, err := h.auditLogService.GetPageWithSearchCriteria(r.Context(), criteria)
+	if err != nil {
+		core.HandleError(w, r, err)
+		return
+	}
+
+	dtos := make([]AuditLogDTO, 0, len(page.Items))
+	for _, auditLog := range page.Items {
+		dtos = append(dtos, ToAuditLogDTO(auditLog))
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(core.MapPage(page, dtos))
+}
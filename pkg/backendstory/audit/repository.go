@@ -0,0 +1,20 @@
+package audit
+
+import (
+	"github.com/ActuallyHello/backendstory/pkg/core"
+	"gorm.io/gorm"
+)
+
+type AuditLogRepository interface {
+	core.BaseRepository[AuditLog]
+}
+
+type auditLogRepository struct {
+	core.BaseRepositoryImpl[AuditLog]
+}
+
+func NewAuditLogRepository(db *gorm.DB) *auditLogRepository {
+	return &auditLogRepository{
+		BaseRepositoryImpl: *core.NewBaseRepositoryImpl[AuditLog](db),
+	}
+}
@@ -0,0 +1,58 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ActuallyHello/backendstory/pkg/backendstory/auth"
+	"github.com/ActuallyHello/backendstory/pkg/core"
+)
+
+const (
+	auditLogServiceCode = "AUDITLOG_SERVICE"
+)
+
+type AuditLogService interface {
+	core.BaseService[AuditLog]
+	core.AuditRecorder
+}
+
+type auditLogService struct {
+	core.BaseServiceImpl[AuditLog]
+	auditLogRepo AuditLogRepository
+}
+
+func NewAuditLogService(
+	auditLogRepo AuditLogRepository,
+) *auditLogService {
+	return &auditLogService{
+		BaseServiceImpl: *core.NewBaseServiceImpl(auditLogRepo),
+		auditLogRepo:    auditLogRepo,
+	}
+}
+
+// Record сохраняет изменение сущности вместе с автором и идентификатором запроса
+func (s *auditLogService) Record(ctx context.Context, entry core.AuditEntry) error {
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return core.NewTechnicalError(err, auditLogServiceCode, "Ошибка при сериализации изменений для журнала аудита")
+	}
+
+	actor := SystemActor
+	if userInfo, err := auth.GetUserInfoCtx(ctx); err == nil && userInfo.Username != "" {
+		actor = userInfo.Username
+	}
+
+	auditLog := AuditLog{
+		Entity:    entry.Entity,
+		EntityID:  entry.EntityID,
+		Operation: string(entry.Operation),
+		Actor:     actor,
+		RequestID: core.RequestIDFromContext(ctx),
+		Changes:   string(changes),
+	}
+	if _, err := s.auditLogRepo.Create(ctx, auditLog); err != nil {
+		return core.NewTechnicalError(err, auditLogServiceCode, "Ошибка при записи в журнал аудита")
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ActuallyHello/backendstory/pkg/core"
+)
+
+// circuitBreakerAuthService оборачивает вызовы keycloak размыкателем цепи: пока keycloak
+// недоступен, запросы сразу получают ошибку вместо ожидания до таймаута сервера
+type circuitBreakerAuthService struct {
+	authService AuthService
+	breaker     *core.CircuitBreaker
+}
+
+func NewCircuitBreakerAuthService(
+	authService AuthService,
+	breaker *core.CircuitBreaker,
+) *circuitBreakerAuthService {
+	return &circuitBreakerAuthService{
+		authService: authService,
+		breaker:     breaker,
+	}
+}
+
+// NewKeycloakCircuitBreaker создает размыкатель, который считает сбоями только недоступность keycloak
+func NewKeycloakCircuitBreaker(options ...core.CircuitBreakerOption) *core.CircuitBreaker {
+	return core.NewCircuitBreaker("keycloak", append([]core.CircuitBreakerOption{
+		core.SetFailureClassifierOpt(isKeycloakUnavailable),
+	}, options...)...)
+}
+
+func (s *circuitBreakerAuthService) RegisterUser(ctx context.Context, username, email, password string) error {
+	return callKeycloakNoResult(ctx, s.breaker, func(ctx context.Context) error {
+		return s.authService.RegisterUser(ctx, username, email, password)
+	})
+}
+
+func (s *circuitBreakerAuthService) DeleteUser(ctx context.Context, email string) error {
+	return callKeycloakNoResult(ctx, s.breaker, func(ctx context.Context) error {
+		return s.authService.DeleteUser(ctx, email)
+	})
+}
+
+func (s *circuitBreakerAuthService) Login(ctx context.Context, username, password string) (JWT, error) {
+	return callKeycloak(ctx, s.breaker, func(ctx context.Context) (JWT, error) {
+		return s.authService.Login(ctx, username, password)
+	})
+}
+
+func (s *circuitBreakerAuthService) RefreshToken(ctx context.Context, refreshToken string) (JWT, error) {
+	return callKeycloak(ctx, s.breaker, func(ctx context.Context) (JWT, error) {
+		return s.authService.RefreshToken(ctx, refreshToken)
+	})
+}
+
+func (s *circuitBreakerAuthService) GetUserByEmail(ctx context.Context, email string) (UserDTO, error) {
+	return callKeycloak(ctx, s.breaker, func(ctx context.Context) (UserDTO, error) {
+		return s.authService.GetUserByEmail(ctx, email)
+	})
+}
+
+func (s *circuitBreakerAuthService) GetUsers(ctx context.Context) ([]UserDTO, error) {
+	return callKeycloak(ctx, s.breaker, s.authService.GetUsers)
+}
+
+func (s *circuitBreakerAuthService) GetRoles(ctx context.Context) ([]string, error) {
+	return callKeycloak(ctx, s.breaker, s.authService.GetRoles)
+}
+
+func (s *circuitBreakerAuthService) GetRolesByUser(ctx context.Context, username string) ([]string, error) {
+	return callKeycloak(ctx, s.breaker, func(ctx context.Context) ([]string, error) {
+		return s.authService.GetRolesByUser(ctx, username)
+	})
+}
+
+func (s *circuitBreakerAuthService) GetTokenUserInfo(ctx context.Context, token string) (TokenUserInfo, error) {
+	return callKeycloak(ctx, s.breaker, func(ctx context.Context) (TokenUserInfo, error) {
+		return s.authService.GetTokenUserInfo(ctx, token)
+	})
+}
+
+// CircuitBreakerStats возвращает состояние размыкателя keycloak
+func (s *circuitBreakerAuthService) CircuitBreakerStats() core.CircuitBreakerStats {
+	return s.breaker.Stats()
+}
+
+func callKeycloak[T any](ctx context.Context, breaker *core.CircuitBreaker, fn func(ctx context.Context) (T, error)) (T, error) {
+	res, err := core.CallWithBreaker(ctx, breaker, fn)
+	if errors.Is(err, core.ErrCircuitOpen) {
+		return res, core.NewTechnicalError(err, core.AuthServiceUnavailableCode, "Сервис авторизации временно недоступен, повторите запрос позже")
+	}
+	return res, err
+}
+
+func callKeycloakNoResult(ctx context.Context, breaker *core.CircuitBreaker, fn func(ctx context.Context) error) error {
+	_, err := callKeycloak(ctx, breaker, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
@@ -25,7 +25,7 @@ func NewAuthHandler(
 	authService AuthService,
 ) *AuthHandler {
 	return &AuthHandler{
-		validate:    validator.New(),
+		validate:    core.DefaultValidator(),
 		authService: authService,
 	}
 }
@@ -46,19 +46,19 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var req RegisterUserRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := core.DecodeJSON(r, &req); err != nil {
 		core.HandleError(w, r, core.NewValidationError(err, authHandlerCode, err.Error()))
 		return
 	}
 	if err := h.validate.Struct(req); err != nil {
-		details := core.CollectValidationDetails(err)
+		details := core.CollectValidationDetails(r.Context(), err)
 		core.HandleValidationError(w, r, core.NewValidationError(err, authHandlerCode, err.Error()), details)
 		return
 	}
 
 	// check if user with same email exists
 	if _, err := h.authService.GetUserByEmail(ctx, req.Email); err == nil {
-		core.HandleError(w, r, core.NewLogicalError(nil, authHandlerCode, "Пользователь с таким email уже зарегистрирован!"))
+		core.HandleError(w, r, core.NewConflictError(nil, core.UserAlreadyExistsCode, "Пользователь с таким email уже зарегистрирован"))
 		return
 	}
 
@@ -112,19 +112,19 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var req LoginRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := core.DecodeJSON(r, &req); err != nil {
 		core.HandleError(w, r, core.NewValidationError(err, authHandlerCode, err.Error()))
 		return
 	}
 	if err := h.validate.Struct(req); err != nil {
-		details := core.CollectValidationDetails(err)
+		details := core.CollectValidationDetails(r.Context(), err)
 		core.HandleValidationError(w, r, core.NewValidationError(err, authHandlerCode, err.Error()), details)
 		return
 	}
 
 	token, err := h.authService.Login(ctx, req.Login, req.Password)
 	if err != nil {
-		core.HandleError(w, r, core.NewAccessError(err, authHandlerCode, err.Error()))
+		core.HandleError(w, r, err)
 		return
 	}
 
@@ -191,7 +191,7 @@ func (h *AuthHandler) GetUserRoles(w http.ResponseWriter, r *http.Request) {
 
 	username := r.PathValue("username")
 	if username == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, authHandlerCode, "Логин пользователя отсуствует"))
+		core.HandleError(w, r, core.NewLogicalError(nil, core.PathParamMissingCode, "Отсутствует параметр %s", "username"))
 		return
 	}
 
@@ -252,7 +252,7 @@ func (h *AuthHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 
 	username := r.PathValue("username")
 	if username == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, authHandlerCode, "Логин пользователя отсуствует"))
+		core.HandleError(w, r, core.NewLogicalError(nil, core.PathParamMissingCode, "Отсутствует параметр %s", "username"))
 		return
 	}
 
@@ -285,7 +285,7 @@ func (h *AuthHandler) GetHeaderTokenInfo(w http.ResponseWriter, r *http.Request)
 
 	authHeader := r.Header.Get(authorization)
 	if authHeader == "" {
-		core.HandleError(w, r, core.NewAccessError(nil, authHandlerCode, "Отсуствует токен авторизации!"))
+		core.HandleError(w, r, core.NewAccessError(nil, core.AuthTokenMissingCode, "Не указан токен авторизации"))
 		return
 	}
 
@@ -320,12 +320,12 @@ func (h *AuthHandler) GetBodyTokenInfo(w http.ResponseWriter, r *http.Request) {
 
 	var tokenReq TokenRequest
 
-	if err := json.NewDecoder(r.Body).Decode(&tokenReq); err != nil {
+	if err := core.DecodeJSON(r, &tokenReq); err != nil {
 		core.HandleError(w, r, core.NewValidationError(err, authHandlerCode, err.Error()))
 		return
 	}
 	if err := h.validate.Struct(tokenReq); err != nil {
-		details := core.CollectValidationDetails(err)
+		details := core.CollectValidationDetails(r.Context(), err)
 		core.HandleValidationError(w, r, core.NewValidationError(err, authHandlerCode, err.Error()), details)
 		return
 	}
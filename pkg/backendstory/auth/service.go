@@ -2,12 +2,15 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"net/http"
 	"time"
 
 	"github.com/ActuallyHello/backendstory/pkg/config"
 	"github.com/ActuallyHello/backendstory/pkg/core"
 	"github.com/Nerzal/gocloak/v13"
+	"github.com/golang-jwt/jwt/v5"
 )
 
 type TokenCtx string
@@ -18,6 +21,10 @@ const (
 	guestRole                       = "guest"
 	TokenCtxKey         TokenCtx    = "token"
 	UserInfoCtxKey      UserInfoCtx = "userInfo"
+
+	keycloakMaxRetries = 3
+	keycloakBaseDelay  = 200 * time.Millisecond
+	keycloakMaxDelay   = 2 * time.Second
 )
 
 type AuthService interface {
@@ -45,25 +52,24 @@ func NewKeycloakService(ctx context.Context, cfg *config.KeycloakConfig) (*keycl
 	client := gocloak.NewClient(cfg.Host)
 
 	// try to get client token
-	tokenObj, err := core.Retry(
+	token, err := retryKeycloak(
+		ctx,
 		"Login keycloak client",
-		func() (any, error) {
+		func(ctx context.Context) (*gocloak.JWT, error) {
 			return client.LoginClient(ctx, cfg.ClientID, cfg.ClientSecret, cfg.Realm)
 		},
-		core.SetMaxRetriesOpt(3),
+		core.SetBaseDelayOpt(time.Second),
 		core.SetMaxDelayOpt(5*time.Second),
 	)
 	if err != nil {
 		return nil, core.NewTechnicalError(err, keycloakAuthService, "Невозможно установить соединение с keycloak")
 	}
-	token, ok := tokenObj.(*gocloak.JWT)
-	if !ok {
-		return nil, core.NewTechnicalError(nil, keycloakAuthService, "Невозможно получить информацию из токена авторизации")
-	}
 
 	// try to get specified client
-	clients, err := client.GetClients(ctx, token.AccessToken, cfg.Realm, gocloak.GetClientsParams{
-		ClientID: &cfg.ClientID, // Фильтруем по ClientID
+	clients, err := retryKeycloak(ctx, "Get keycloak clients", func(ctx context.Context) ([]*gocloak.Client, error) {
+		return client.GetClients(ctx, token.AccessToken, cfg.Realm, gocloak.GetClientsParams{
+			ClientID: &cfg.ClientID, // Фильтруем по ClientID
+		})
 	})
 	if err != nil {
 		return nil, core.NewTechnicalError(err, keycloakAuthService, "Невозможно получить клиентов keycloak")
@@ -84,7 +90,9 @@ func NewKeycloakService(ctx context.Context, cfg *config.KeycloakConfig) (*keycl
 }
 
 func (kc *keycloakService) RegisterUser(ctx context.Context, username, email, password string) error {
-	kcRole, err := kc.client.GetClientRole(ctx, kc.token.AccessToken, kc.cfg.Realm, kc.clientID, guestRole)
+	kcRole, err := retryKeycloak(ctx, "Get keycloak client role", func(ctx context.Context) (*gocloak.Role, error) {
+		return kc.client.GetClientRole(ctx, kc.token.AccessToken, kc.cfg.Realm, kc.clientID, guestRole)
+	})
 	if err != nil {
 		return core.NewTechnicalError(err, keycloakAuthService, "Роль 'Гость' отсутствует")
 	}
@@ -118,7 +126,7 @@ func (kc *keycloakService) RegisterUser(ctx context.Context, username, email, pa
 func (kc *keycloakService) Login(ctx context.Context, username, password string) (JWT, error) {
 	token, err := kc.client.Login(ctx, kc.cfg.ClientID, kc.cfg.ClientSecret, kc.cfg.Realm, username, password)
 	if err != nil {
-		return JWT{}, core.NewAccessError(err, keycloakAuthService, "Ошибка при авторизации в keycloak")
+		return JWT{}, core.NewAccessError(err, core.AuthCredentialsInvalidCode, "Неверный логин или пароль")
 	}
 	return JWT{
 		AccessToken:      token.AccessToken,
@@ -131,7 +139,7 @@ func (kc *keycloakService) Login(ctx context.Context, username, password string)
 func (kc *keycloakService) RefreshToken(ctx context.Context, refreshToken string) (JWT, error) {
 	token, err := kc.client.RefreshToken(ctx, refreshToken, kc.cfg.ClientID, kc.cfg.ClientSecret, kc.cfg.Realm)
 	if err != nil {
-		return JWT{}, core.NewAccessError(err, keycloakAuthService, err.Error())
+		return JWT{}, core.NewAccessError(err, core.AuthRefreshTokenInvalidCode, "Токен обновления недействителен или истек")
 	}
 	return JWT{
 		AccessToken:      token.AccessToken,
@@ -143,7 +151,9 @@ func (kc *keycloakService) RefreshToken(ctx context.Context, refreshToken string
 
 func (kc *keycloakService) GetRoles(ctx context.Context) ([]string, error) {
 	params := gocloak.GetRoleParams{}
-	kcRoles, err := kc.client.GetClientRoles(ctx, kc.token.AccessToken, kc.cfg.Realm, kc.clientID, params)
+	kcRoles, err := retryKeycloak(ctx, "Get keycloak client roles", func(ctx context.Context) ([]*gocloak.Role, error) {
+		return kc.client.GetClientRoles(ctx, kc.token.AccessToken, kc.cfg.Realm, kc.clientID, params)
+	})
 	if err != nil {
 		return nil, core.NewTechnicalError(err, keycloakAuthService, "Невозможно получить роли keycloak")
 	}
@@ -160,7 +170,9 @@ func (kc *keycloakService) GetRolesByUser(ctx context.Context, username string)
 		return nil, err
 	}
 
-	kcRoles, err := kc.client.GetClientRolesByUserID(ctx, kc.token.AccessToken, kc.cfg.Realm, kc.clientID, userDTO.ID)
+	kcRoles, err := retryKeycloak(ctx, "Get keycloak user roles", func(ctx context.Context) ([]*gocloak.Role, error) {
+		return kc.client.GetClientRolesByUserID(ctx, kc.token.AccessToken, kc.cfg.Realm, kc.clientID, userDTO.ID)
+	})
 	if err != nil {
 		return nil, core.NewTechnicalError(err, keycloakAuthService, "Невозможно получить роли keycloak")
 	}
@@ -174,57 +186,63 @@ func (kc *keycloakService) GetRolesByUser(ctx context.Context, username string)
 }
 
 func (kc *keycloakService) GetTokenUserInfo(ctx context.Context, token string) (TokenUserInfo, error) {
-	_, claims, err := kc.client.DecodeAccessToken(ctx, token, kc.cfg.Realm)
+	claims, err := retryKeycloak(ctx, "Decode keycloak access token", func(ctx context.Context) (*jwt.MapClaims, error) {
+		_, claims, err := kc.client.DecodeAccessToken(ctx, token, kc.cfg.Realm)
+		return claims, err
+	})
 	if err != nil {
-		return TokenUserInfo{}, core.NewTechnicalError(err, keycloakAuthService, "Ошибка при расшифровке токена авторизации")
+		if isKeycloakUnavailable(err) {
+			return TokenUserInfo{}, core.NewTechnicalError(err, keycloakAuthService, "Ошибка при расшифровке токена авторизации")
+		}
+		return TokenUserInfo{}, core.NewAccessError(err, core.AuthTokenInvalidCode, "Токен авторизации недействителен или истек")
 	}
 
 	var tokenUserInfo TokenUserInfo
 
 	emailRaw, ok := (*claims)["email"]
 	if !ok {
-		return TokenUserInfo{}, core.NewLogicalError(nil, keycloakAuthService, "Неверный формат токена. Не найден тэг : email")
+		return TokenUserInfo{}, core.NewLogicalError(nil, core.AuthTokenClaimsInvalidCode, "Неверный формат токена. Не найден тэг : email")
 	}
 	email, ok := emailRaw.(string)
 	if !ok {
-		return TokenUserInfo{}, core.NewLogicalError(nil, keycloakAuthService, "Неверный формат токена. Тэг email некорректный!")
+		return TokenUserInfo{}, core.NewLogicalError(nil, core.AuthTokenClaimsInvalidCode, "Неверный формат токена. Тэг email некорректный!")
 	}
 	tokenUserInfo.Email = email
 
 	usernameRaw, ok := (*claims)["preferred_username"]
 	if !ok {
-		return TokenUserInfo{}, core.NewLogicalError(nil, keycloakAuthService, "Неверный формат токена. Не найден тэг : preferred_username")
+		return TokenUserInfo{}, core.NewLogicalError(nil, core.AuthTokenClaimsInvalidCode, "Неверный формат токена. Не найден тэг : preferred_username")
 	}
 	username, ok := usernameRaw.(string)
 	if !ok {
-		return TokenUserInfo{}, core.NewLogicalError(nil, keycloakAuthService, "Неверный формат токена. Тэг preferred_username некорректный!")
+		return TokenUserInfo{}, core.NewLogicalError(nil, core.AuthTokenClaimsInvalidCode, "Неверный формат токена. Тэг preferred_username некорректный!")
 	}
 	tokenUserInfo.Username = username
 
 	resource_access, ok := (*claims)["resource_access"]
 	if !ok {
-		return TokenUserInfo{}, core.NewLogicalError(nil, keycloakAuthService, "Неверный формат токена. Не найден тэг : resource_access")
+		return TokenUserInfo{}, core.NewLogicalError(nil, core.AuthTokenClaimsInvalidCode, "Неверный формат токена. Не найден тэг : resource_access")
 	}
 	tokenClients, ok := resource_access.(map[string]any)
 	if !ok {
-		return TokenUserInfo{}, core.NewLogicalError(nil, keycloakAuthService, "Неверный формат токена. Тэг resource_access некорректный!")
+		return TokenUserInfo{}, core.NewLogicalError(nil, core.AuthTokenClaimsInvalidCode, "Неверный формат токена. Тэг resource_access некорректный!")
 	}
 	clientResourcesRaw, ok := tokenClients[kc.cfg.ClientID]
 	if !ok {
-		return TokenUserInfo{}, core.NewLogicalError(nil, keycloakAuthService, "Неверный формат токена. Не найдены клиенты по переданному идентификатору!")
+		return TokenUserInfo{}, core.NewLogicalError(nil, core.AuthTokenClaimsInvalidCode, "Неверный формат токена. Не найдены клиенты по переданному идентификатору!")
 	}
 	clientResources, ok := clientResourcesRaw.(map[string]any)
 	if !ok {
-		return TokenUserInfo{}, core.NewLogicalError(nil, keycloakAuthService, "Неверный формат токена. Некорректный формат информации о клиентах!")
+		return TokenUserInfo{}, core.NewLogicalError(nil, core.AuthTokenClaimsInvalidCode, "Неверный формат токена. Некорректный формат информации о клиентах!")
 	}
 
 	rolesRaw, ok := clientResources["roles"]
 	if !ok {
-		return TokenUserInfo{}, core.NewLogicalError(nil, keycloakAuthService, "Неверный формат токена. Нет данных по ролям клиента!")
+		return TokenUserInfo{}, core.NewLogicalError(nil, core.AuthTokenClaimsInvalidCode, "Неверный формат токена. Нет данных по ролям клиента!")
 	}
 	rolesSlice, ok := rolesRaw.([]interface{})
 	if !ok {
-		return TokenUserInfo{}, core.NewLogicalError(nil, keycloakAuthService, "Неверный формат токена. Некорректный формат ролей!")
+		return TokenUserInfo{}, core.NewLogicalError(nil, core.AuthTokenClaimsInvalidCode, "Неверный формат токена. Некорректный формат ролей!")
 	}
 	roles := make([]string, len(rolesSlice))
 	for i := 0; i < len(rolesSlice); i++ {
@@ -237,7 +255,9 @@ func (kc *keycloakService) GetTokenUserInfo(ctx context.Context, token string) (
 
 func (kc *keycloakService) GetUsers(ctx context.Context) ([]UserDTO, error) {
 	params := gocloak.GetUsersParams{}
-	kcUsers, err := kc.client.GetUsers(ctx, kc.token.AccessToken, kc.cfg.Realm, params)
+	kcUsers, err := retryKeycloak(ctx, "Get keycloak users", func(ctx context.Context) ([]*gocloak.User, error) {
+		return kc.client.GetUsers(ctx, kc.token.AccessToken, kc.cfg.Realm, params)
+	})
 	if err != nil {
 		return nil, core.NewTechnicalError(err, keycloakAuthService, "Ошибка при поиске пользователей по заданным параметрам")
 	}
@@ -260,12 +280,14 @@ func (kc *keycloakService) GetUserByEmail(ctx context.Context, email string) (Us
 		Email: &email,
 	}
 	// always return 1 element
-	kcUsers, err := kc.client.GetUsers(ctx, kc.token.AccessToken, kc.cfg.Realm, params)
+	kcUsers, err := retryKeycloak(ctx, "Get keycloak user by email", func(ctx context.Context) ([]*gocloak.User, error) {
+		return kc.client.GetUsers(ctx, kc.token.AccessToken, kc.cfg.Realm, params)
+	})
 	if err != nil {
 		return UserDTO{}, core.NewTechnicalError(err, keycloakAuthService, "Ошибка при получении пользователя!")
 	}
 	if len(kcUsers) == 0 {
-		return UserDTO{}, core.NewLogicalError(err, keycloakAuthService, "Пользователя с такими данными не существует!")
+		return UserDTO{}, core.NewNotFoundError(core.UserNotFoundCode, "Пользователя с такими данными не существует")
 	}
 
 	userDTO := UserDTO{
@@ -291,10 +313,37 @@ func (kc *keycloakService) DeleteUser(ctx context.Context, email string) error {
 	return nil
 }
 
+// retryKeycloak повторяет идемпотентный вызов keycloak при временных сбоях.
+// Изменяющие вызовы (создание пользователя, вход) не повторяются
+func retryKeycloak[T any](ctx context.Context, operation string, fn func(ctx context.Context) (T, error), options ...core.RetryOption) (T, error) {
+	return core.Retry(ctx, operation, fn, append([]core.RetryOption{
+		core.SetMaxRetriesOpt(keycloakMaxRetries),
+		core.SetBaseDelayOpt(keycloakBaseDelay),
+		core.SetMaxDelayOpt(keycloakMaxDelay),
+		core.SetRetryableOpt(isKeycloakUnavailable),
+	}, options...)...)
+}
+
+// isKeycloakUnavailable ошибка говорит о недоступности keycloak: нет ответа (код 0), ответ 5xx,
+// перегрузка или таймаут. Остальные ответы означают ошибку в запросе, их повтор бесполезен
+func isKeycloakUnavailable(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var apiErr *gocloak.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.Code == 0 ||
+		apiErr.Code >= http.StatusInternalServerError ||
+		apiErr.Code == http.StatusTooManyRequests ||
+		apiErr.Code == http.StatusRequestTimeout
+}
+
 func GetTokenCtx(ctx context.Context) (string, error) {
 	tokenCtxKey, ok := ctx.Value(TokenCtxKey).(string)
 	if !ok {
-		return "", core.NewLogicalError(nil, keycloakAuthService, "Токен не установлен в контекст!")
+		return "", core.NewLogicalError(nil, core.AuthContextMissingCode, "Токен не установлен в контекст")
 	}
 	return tokenCtxKey, nil
 }
@@ -302,7 +351,7 @@ func GetTokenCtx(ctx context.Context) (string, error) {
 func GetUserInfoCtx(ctx context.Context) (TokenUserInfo, error) {
 	userInfoCtxKey, ok := ctx.Value(UserInfoCtxKey).(TokenUserInfo)
 	if !ok {
-		return TokenUserInfo{}, core.NewLogicalError(nil, keycloakAuthService, "Информация о пользователя отсутствует в токене!")
+		return TokenUserInfo{}, core.NewLogicalError(nil, core.AuthContextMissingCode, "Информация о пользователе отсутствует в контексте")
 	}
 	return userInfoCtxKey, nil
 }
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ActuallyHello/backendstory/pkg/core"
+	"github.com/Nerzal/gocloak/v13"
+	"github.com/stretchr/testify/assert"
+)
+
+// fastKeycloakRetry сокращает задержки, сохраняя число попыток и классификатор keycloak
+var fastKeycloakRetry = []core.RetryOption{
+	core.SetBaseDelayOpt(time.Millisecond),
+	core.SetMaxDelayOpt(time.Millisecond),
+}
+
+func TestIsKeycloakUnavailable(t *testing.T) {
+	apiError := func(code int) error {
+		return fmt.Errorf("keycloak: %w", &gocloak.APIError{Code: code, Message: http.StatusText(code)})
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "no response", err: apiError(0), want: true},
+		{name: "internal error", err: apiError(http.StatusInternalServerError), want: true},
+		{name: "bad gateway", err: apiError(http.StatusBadGateway), want: true},
+		{name: "service unavailable", err: apiError(http.StatusServiceUnavailable), want: true},
+		{name: "too many requests", err: apiError(http.StatusTooManyRequests), want: true},
+		{name: "request timeout", err: apiError(http.StatusRequestTimeout), want: true},
+		{name: "deadline", err: fmt.Errorf("login: %w", context.DeadlineExceeded), want: true},
+		{name: "bad request", err: apiError(http.StatusBadRequest), want: false},
+		{name: "unauthorized", err: apiError(http.StatusUnauthorized), want: false},
+		{name: "not found", err: apiError(http.StatusNotFound), want: false},
+		{name: "conflict", err: apiError(http.StatusConflict), want: false},
+		{name: "canceled", err: context.Canceled, want: false},
+		{name: "not api error", err: errors.New("invalid token"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isKeycloakUnavailable(tt.err))
+		})
+	}
+}
+
+func TestRetryKeycloakRetriesUnavailable(t *testing.T) {
+	calls := 0
+	_, err := retryKeycloak(context.Background(), "test", func(context.Context) (string, error) {
+		calls++
+		return "", &gocloak.APIError{Code: http.StatusServiceUnavailable}
+	}, fastKeycloakRetry...)
+
+	var apiErr *gocloak.APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, keycloakMaxRetries, calls)
+}
+
+func TestRetryKeycloakRecovers(t *testing.T) {
+	calls := 0
+	res, err := retryKeycloak(context.Background(), "test", func(context.Context) (string, error) {
+		calls++
+		if calls == 1 {
+			return "", &gocloak.APIError{Code: 0, Message: "connection refused"}
+		}
+		return "token", nil
+	}, fastKeycloakRetry...)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "token", res)
+	assert.Equal(t, 2, calls)
+}
+
+func TestRetryKeycloakDoesNotRetryClientErrors(t *testing.T) {
+	calls := 0
+	_, err := retryKeycloak(context.Background(), "test", func(context.Context) (string, error) {
+		calls++
+		return "", &gocloak.APIError{Code: http.StatusUnauthorized}
+	}, fastKeycloakRetry...)
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryKeycloakStopsOnContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	_, err := retryKeycloak(ctx, "test", func(context.Context) (string, error) {
+		calls++
+		cancel()
+		return "", &gocloak.APIError{Code: http.StatusBadGateway}
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, calls)
+}
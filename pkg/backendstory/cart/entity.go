@@ -15,3 +15,11 @@ func (Cart) TableName() string {
 func (Cart) LocalTableName() string {
 	return "Корзина"
 }
+
+var cartSearchFields = core.NewSearchFields(
+	core.SearchField{Name: "person_id", Column: "PERSONID", Type: core.FieldUint, Sortable: true},
+)
+
+func (Cart) SearchFields() *core.SearchFields {
+	return cartSearchFields
+}
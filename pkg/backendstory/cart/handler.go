@@ -1,7 +1,6 @@
 package cart
 
 import (
-	"encoding/json"
 	"net/http"
 	"strconv"
 
@@ -16,14 +15,17 @@ const (
 type CartHandler struct {
 	validate    *validator.Validate
 	cartService CartService
+	crud        *core.CrudHandler[Cart, CartDTO]
 }
 
 func NewCartHandler(
 	cartService CartService,
 ) *CartHandler {
+	validate := core.DefaultValidator()
 	return &CartHandler{
-		validate:    validator.New(),
+		validate:    validate,
 		cartService: cartService,
+		crud:        core.NewCrudHandler(cartHandlerCode, validate, cartService, core.MapWith(ToCartDTO)),
 	}
 }
 
@@ -47,12 +49,12 @@ func (h *CartHandler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var req CartCreateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := core.DecodeJSON(r, &req); err != nil {
 		core.HandleError(w, r, core.NewTechnicalError(err, cartHandlerCode, err.Error()))
 		return
 	}
 	if err := h.validate.Struct(req); err != nil {
-		details := core.CollectValidationDetails(err)
+		details := core.CollectValidationDetails(r.Context(), err)
 		core.HandleValidationError(w, r, core.NewLogicalError(err, cartHandlerCode, err.Error()), details)
 		return
 	}
@@ -66,8 +68,31 @@ func (h *CartHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(ToCartDTO(cart))
+	h.crud.WriteEntity(w, r, http.StatusCreated, cart)
+}
+
+// GetAll возвращает все корзины
+// @Summary Получить все корзины
+// @Description Возвращает список всех корзин постранично, с фильтрацией и сортировкой из строки запроса
+// @Tags Carts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param filter query string false "Условия через ';', например person_id==5"
+// @Param sort query string false "Поля сортировки через ',', префикс '-' - по убыванию, например -created_at"
+// @Param limit query int false "Размер страницы" default(20)
+// @Param offset query int false "Смещение"
+// @Param after query string false "Курсор следующей страницы"
+// @Param before query string false "Курсор предыдущей страницы"
+// @Success 200 {object} core.Page[CartDTO] "Список корзин"
+// @Failure 400 {object} core.ErrorResponse "Некорректные параметры фильтра"
+// @Failure 401 {object} core.ErrorResponse "Не авторизован"
+// @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
+// @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /carts [get]
+// @Id getCartAll
+func (h *CartHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	h.crud.GetAll(w, r)
 }
 
 // GetById возвращает корзину по ID
@@ -79,6 +104,7 @@ func (h *CartHandler) Create(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Param id path int true "ID корзины"
 // @Success 200 {object} CartDTO "Корзина"
+// @Header 200 {string} ETag "Версия записи"
 // @Failure 400 {object} core.ErrorResponse "Неверный ID"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
@@ -87,27 +113,7 @@ func (h *CartHandler) Create(w http.ResponseWriter, r *http.Request) {
 // @Router /carts/{id} [get]
 // @Id GetCartById
 func (h *CartHandler) GetById(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	reqID := r.PathValue("id")
-	if reqID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, cartHandlerCode, "Отсуствует ИД параметр"))
-		return
-	}
-	id, err := strconv.Atoi(reqID)
-	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, cartHandlerCode, "ИД параметр должен быть числовым!"+err.Error()))
-		return
-	}
-
-	cart, err := h.cartService.GetByID(ctx, uint(id))
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(ToCartDTO(cart))
+	h.crud.GetById(w, r)
 }
 
 // GetWithSearchCriteria выполняет поиск корзин по критериям
@@ -118,7 +124,7 @@ func (h *CartHandler) GetById(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Security BearerAuth
 // @Param request body core.SearchCriteria true "Критерии поиска"
-// @Success 200 {array} CartDTO "Список найденных корзин"
+// @Success 200 {object} core.Page[CartDTO] "Список найденных корзин"
 // @Failure 400 {object} core.ErrorResponse "Ошибка валидации"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
@@ -126,32 +132,7 @@ func (h *CartHandler) GetById(w http.ResponseWriter, r *http.Request) {
 // @Router /carts/search [post]
 // @Id searchCart
 func (h *CartHandler) GetWithSearchCriteria(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	var req core.SearchCriteria
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		core.HandleError(w, r, core.NewTechnicalError(err, cartHandlerCode, err.Error()))
-		return
-	}
-	if err := h.validate.Struct(req); err != nil {
-		details := core.CollectValidationDetails(err)
-		core.HandleValidationError(w, r, core.NewLogicalError(err, cartHandlerCode, err.Error()), details)
-		return
-	}
-
-	carts, err := h.cartService.GetWithSearchCriteria(ctx, req)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	dtos := make([]CartDTO, 0, len(carts))
-	for _, cart := range carts {
-		dtos = append(dtos, ToCartDTO(cart))
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(dtos)
+	h.crud.GetWithSearchCriteria(w, r)
 }
 
 // GetByPersonID возвращает корзину по ID пользователя
@@ -175,12 +156,12 @@ func (h *CartHandler) GetByPersonID(w http.ResponseWriter, r *http.Request) {
 
 	reqID := r.PathValue("person_id")
 	if reqID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, cartHandlerCode, "Отсутствует ИД параметр"))
+		core.HandleError(w, r, core.NewLogicalError(nil, core.PathParamMissingCode, "Отсутствует параметр %s", "person_id"))
 		return
 	}
 	id, err := strconv.Atoi(reqID)
 	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, cartHandlerCode, "ИД параметр должен быть числовым!"+err.Error()))
+		core.HandleError(w, r, core.NewLogicalError(err, core.PathParamInvalidCode, "Параметр %s должен быть числовым", "person_id"))
 		return
 	}
 
@@ -190,6 +171,5 @@ func (h *CartHandler) GetByPersonID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(ToCartDTO(cart))
+	h.crud.WriteEntity(w, r, http.StatusOK, cart)
 }
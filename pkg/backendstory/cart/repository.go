@@ -29,7 +29,7 @@ func (r *cartRepository) FindByPersonID(ctx context.Context, personID uint) (Car
 	var cart Cart
 	if err := r.GetDB(ctx).Where("PERSONID = ?", personID).First(&cart).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return Cart{}, core.NewNotFoundError("Не существует корзины у данного пользователя")
+			return Cart{}, core.NewNotFoundError(core.CartNotFoundCode, "Не существует корзины у данного пользователя")
 		}
 		return Cart{}, err
 	}
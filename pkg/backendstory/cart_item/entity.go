@@ -17,3 +17,13 @@ func (CartItem) TableName() string {
 func (CartItem) LocalTableName() string {
 	return "Элемент корзины"
 }
+
+var cartItemSearchFields = core.NewSearchFields(
+	core.SearchField{Name: "quantity", Column: "QUANTITY", Type: core.FieldUint, Sortable: true},
+	core.SearchField{Name: "cart_id", Column: "CARTID", Type: core.FieldUint, Sortable: true},
+	core.SearchField{Name: "product_id", Column: "PRODUCTID", Type: core.FieldUint, Sortable: true},
+)
+
+func (CartItem) SearchFields() *core.SearchFields {
+	return cartItemSearchFields
+}
@@ -1,7 +1,6 @@
 package cartitem
 
 import (
-	"encoding/json"
 	"net/http"
 	"strconv"
 
@@ -16,14 +15,17 @@ const (
 type CartItemHandler struct {
 	validate        *validator.Validate
 	cartItemService CartItemService
+	crud            *core.CrudHandler[CartItem, CartItemDTO]
 }
 
 func NewCartItemHandler(
 	cartItemService CartItemService,
 ) *CartItemHandler {
+	validate := core.DefaultValidator()
 	return &CartItemHandler{
-		validate:        validator.New(),
+		validate:        validate,
 		cartItemService: cartItemService,
+		crud:            core.NewCrudHandler(cartItemHandlerCode, validate, cartItemService, core.MapWith(ToCartItemDTO)),
 	}
 }
 
@@ -47,12 +49,12 @@ func (h *CartItemHandler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var req CartItemCreateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := core.DecodeJSON(r, &req); err != nil {
 		core.HandleError(w, r, core.NewTechnicalError(err, cartItemHandlerCode, err.Error()))
 		return
 	}
 	if err := h.validate.Struct(req); err != nil {
-		details := core.CollectValidationDetails(err)
+		details := core.CollectValidationDetails(r.Context(), err)
 		core.HandleValidationError(w, r, core.NewLogicalError(err, cartItemHandlerCode, err.Error()), details)
 		return
 	}
@@ -68,8 +70,7 @@ func (h *CartItemHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(ToCartItemDTO(cartItem))
+	h.crud.WriteEntity(w, r, http.StatusCreated, cartItem)
 }
 
 // Update обновляет элемент корзины
@@ -80,7 +81,9 @@ func (h *CartItemHandler) Create(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Security BearerAuth
 // @Param request body CartItemUpdateRequest true "Данные для обновления элемента корзины"
+// @Param If-Match header string false "Версия записи из ETag"
 // @Success 201 {object} CartItemDTO "Обновление элемента корзины"
+// @Header 201 {string} ETag "Версия записи"
 // @Failure 400 {object} core.ErrorResponse "Ошибка валидации"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
@@ -92,12 +95,12 @@ func (h *CartItemHandler) Update(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var req CartItemUpdateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := core.DecodeJSON(r, &req); err != nil {
 		core.HandleError(w, r, core.NewTechnicalError(err, cartItemHandlerCode, err.Error()))
 		return
 	}
 	if err := h.validate.Struct(req); err != nil {
-		details := core.CollectValidationDetails(err)
+		details := core.CollectValidationDetails(r.Context(), err)
 		core.HandleValidationError(w, r, core.NewLogicalError(err, cartItemHandlerCode, err.Error()), details)
 		return
 	}
@@ -107,6 +110,10 @@ func (h *CartItemHandler) Update(w http.ResponseWriter, r *http.Request) {
 		core.HandleError(w, r, err)
 		return
 	}
+	if err := core.ApplyIfMatch(r, &cartItem); err != nil {
+		core.HandleError(w, r, err)
+		return
+	}
 
 	cartItem.Quantity = req.Quantity
 	cartItem, err = h.cartItemService.Update(ctx, cartItem)
@@ -115,8 +122,7 @@ func (h *CartItemHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(ToCartItemDTO(cartItem))
+	h.crud.WriteEntity(w, r, http.StatusCreated, cartItem)
 }
 
 // Delete удаляет элемент корзины
@@ -139,12 +145,12 @@ func (h *CartItemHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	reqID := r.PathValue("id")
 	if reqID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, cartItemHandlerCode, "Отсуствует ИД параметр"))
+		core.HandleError(w, r, core.NewLogicalError(nil, core.PathParamMissingCode, "Отсутствует параметр %s", "id"))
 		return
 	}
 	id, err := strconv.Atoi(reqID)
 	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, cartItemHandlerCode, "ИД параметр должен быть числовым!"+err.Error()))
+		core.HandleError(w, r, core.NewLogicalError(err, core.PathParamInvalidCode, "Параметр %s должен быть числовым", "id"))
 		return
 	}
 
@@ -163,6 +169,30 @@ func (h *CartItemHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// GetAll возвращает все элементы корзин
+// @Summary Получить все элементы корзин
+// @Description Возвращает список всех элементов корзин постранично, с фильтрацией и сортировкой из строки запроса
+// @Tags CartItems
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param filter query string false "Условия через ';', например cart_id==3;quantity>=2"
+// @Param sort query string false "Поля сортировки через ',', префикс '-' - по убыванию, например -quantity"
+// @Param limit query int false "Размер страницы" default(20)
+// @Param offset query int false "Смещение"
+// @Param after query string false "Курсор следующей страницы"
+// @Param before query string false "Курсор предыдущей страницы"
+// @Success 200 {object} core.Page[CartItemDTO] "Список элементов корзин"
+// @Failure 400 {object} core.ErrorResponse "Некорректные параметры фильтра"
+// @Failure 401 {object} core.ErrorResponse "Не авторизован"
+// @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
+// @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /cart-items [get]
+// @Id getCartItemAll
+func (h *CartItemHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	h.crud.GetAll(w, r)
+}
+
 // GetById возвращает элемент корзины по ID
 // @Summary Получить элемент корзины по ID
 // @Description Возвращает элемент корзины по указанному идентификатору
@@ -172,6 +202,7 @@ func (h *CartItemHandler) Delete(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Param id path int true "ID элемента корзины"
 // @Success 200 {object} CartItemDTO "Элемент корзины"
+// @Header 200 {string} ETag "Версия записи"
 // @Failure 400 {object} core.ErrorResponse "Неверный ID"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
@@ -180,27 +211,7 @@ func (h *CartItemHandler) Delete(w http.ResponseWriter, r *http.Request) {
 // @Router /cart-items/{id} [get]
 // @Id getCartItemById
 func (h *CartItemHandler) GetById(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	reqID := r.PathValue("id")
-	if reqID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, cartItemHandlerCode, "Отсуствует ИД параметр"))
-		return
-	}
-	id, err := strconv.Atoi(reqID)
-	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, cartItemHandlerCode, "ИД параметр должен быть числовым!"+err.Error()))
-		return
-	}
-
-	cartItem, err := h.cartItemService.GetByID(ctx, uint(id))
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(ToCartItemDTO(cartItem))
+	h.crud.GetById(w, r)
 }
 
 // GetWithSearchCriteria выполняет поиск элементов корзины по критериям
@@ -211,7 +222,7 @@ func (h *CartItemHandler) GetById(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Security BearerAuth
 // @Param request body core.SearchCriteria true "Критерии поиска"
-// @Success 200 {array} CartItemDTO "Список найденных элементов корзины"
+// @Success 200 {object} core.Page[CartItemDTO] "Список найденных элементов корзины"
 // @Failure 400 {object} core.ErrorResponse "Ошибка валидации"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
@@ -219,32 +230,7 @@ func (h *CartItemHandler) GetById(w http.ResponseWriter, r *http.Request) {
 // @Router /cart-items/search [post]
 // @Id searchCartItem
 func (h *CartItemHandler) GetWithSearchCriteria(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	var req core.SearchCriteria
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		core.HandleError(w, r, core.NewTechnicalError(err, cartItemHandlerCode, err.Error()))
-		return
-	}
-	if err := h.validate.Struct(req); err != nil {
-		details := core.CollectValidationDetails(err)
-		core.HandleValidationError(w, r, core.NewLogicalError(err, cartItemHandlerCode, err.Error()), details)
-		return
-	}
-
-	cartItems, err := h.cartItemService.GetWithSearchCriteria(ctx, req)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	dtos := make([]CartItemDTO, 0, len(cartItems))
-	for _, cartItem := range cartItems {
-		dtos = append(dtos, ToCartItemDTO(cartItem))
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(dtos)
+	h.crud.GetWithSearchCriteria(w, r)
 }
 
 // GetByCartID возвращает элементы корзины по ID корзины
@@ -268,12 +254,12 @@ func (h *CartItemHandler) GetByCartID(w http.ResponseWriter, r *http.Request) {
 
 	reqID := r.PathValue("cart_id")
 	if reqID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, cartItemHandlerCode, "Отсуствует ИД параметр"))
+		core.HandleError(w, r, core.NewLogicalError(nil, core.PathParamMissingCode, "Отсутствует параметр %s", "cart_id"))
 		return
 	}
 	id, err := strconv.Atoi(reqID)
 	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, cartItemHandlerCode, "ИД параметр должен быть числовым!"+err.Error()))
+		core.HandleError(w, r, core.NewLogicalError(err, core.PathParamInvalidCode, "Параметр %s должен быть числовым", "cart_id"))
 		return
 	}
 
@@ -283,11 +269,5 @@ func (h *CartItemHandler) GetByCartID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	dtos := make([]CartItemDTO, 0, len(cartItems))
-	for _, cartItem := range cartItems {
-		dtos = append(dtos, ToCartItemDTO(cartItem))
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(dtos)
+	h.crud.WriteList(w, r, cartItems)
 }
@@ -30,7 +30,7 @@ func (r *cartItemRepository) FindByCartID(ctx context.Context, cartID uint) ([]C
 	var cartItems []CartItem
 	if err := r.GetDB(ctx).Where("CARTID = ?", cartID).Find(&cartItems).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, core.NewNotFoundError("Элементов корзины не найдено")
+			return nil, core.NewNotFoundError(core.CartItemNotFoundCode, "Элементов корзины не найдено")
 		}
 		return nil, err
 	}
@@ -42,7 +42,7 @@ func (r *cartItemRepository) FindByCartIDAndProductID(ctx context.Context, cartI
 	var cartItem CartItem
 	if err := r.GetDB(ctx).Where("CARTID = ? AND PRODUCTID = ?", cartID, productID).First(&cartItem).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return CartItem{}, core.NewNotFoundError("Элементов корзины не найдено")
+			return CartItem{}, core.NewNotFoundError(core.CartItemNotFoundCode, "Элементов корзины не найдено")
 		}
 		return CartItem{}, err
 	}
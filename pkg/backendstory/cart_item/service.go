@@ -3,7 +3,6 @@ package cartitem
 import (
 	"context"
 	"errors"
-	"fmt"
 
 	"github.com/ActuallyHello/backendstory/pkg/backendstory/enum"
 	"github.com/ActuallyHello/backendstory/pkg/backendstory/enumvalue"
@@ -57,7 +56,7 @@ func (s *cartItemService) Create(ctx context.Context, cartItem CartItem) (CartIt
 		}
 	}
 	if existing.ID > 0 {
-		return CartItem{}, core.NewLogicalError(nil, cartItemServiceCode, "Данный товар уже существует в корзине!")
+		return CartItem{}, core.NewConflictError(nil, core.CartItemDuplicateCode, "Данный товар уже есть в корзине")
 	}
 
 	if err := s.checkProduct(ctx, cartItem); err != nil {
@@ -129,7 +128,7 @@ func (s *cartItemService) checkProduct(ctx context.Context, cartItem CartItem) e
 
 func (s *cartItemService) checkProductQuantity(cartItem CartItem, product product.Product) error {
 	if product.Quantity < cartItem.Quantity {
-		return core.NewLogicalError(nil, cartItemServiceCode, fmt.Sprintf("Невозможно создать элемент корзины! Текущее количество товара %s: %d", product.Label, product.Quantity))
+		return core.NewLogicalError(nil, core.ProductOutOfStockCode, "Недостаточно товара %s на складе, доступно: %d", product.Label, product.Quantity)
 	}
 	return nil
 }
@@ -140,7 +139,7 @@ func (s *cartItemService) checkProductStatus(ctx context.Context, checkProduct p
 		return err
 	}
 	if currentProductStatus.Code != product.AvailableProductStatus {
-		return core.NewLogicalError(nil, cartItemServiceCode, "Товар недоступен для добавления в корзину")
+		return core.NewLogicalError(nil, core.ProductUnavailableCode, "Товар %s недоступен для заказа", checkProduct.Label)
 	}
 	return nil
 }
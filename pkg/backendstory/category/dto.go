@@ -1,6 +1,10 @@
 package category
 
-import "time"
+import (
+	"time"
+
+	"github.com/ActuallyHello/backendstory/pkg/core"
+)
 
 // CategoryCreateRequest represents request for creating category
 // @Name CategoryCreateRequest
@@ -17,8 +21,14 @@ type CategoryUpdateRequest struct {
 	Code       string `json:"code" validate:"omitempty,min=1,max=50"`
 	Label      string `json:"label" validate:"omitempty,min=1,max=255"`
 	CategoryID *uint  `json:"category_id" validate:"omitempty,min=1"`
+	// Version версия из ETag, с которой клиент начал редактирование, при несовпадении обновление отклоняется
+	Version uint `json:"version" validate:"required,gt=0"`
 }
 
+// CategoryBatchRequest represents batch request for creating, updating and deleting categories
+// @Name CategoryBatchRequest
+type CategoryBatchRequest = core.BatchRequest[CategoryCreateRequest, CategoryUpdateRequest]
+
 // CategoryDTO represents category data transfer object
 // @Name CategoryDTO
 type CategoryDTO struct {
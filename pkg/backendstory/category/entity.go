@@ -21,3 +21,13 @@ func (Category) TableName() string {
 func (Category) LocalTableName() string {
 	return "Категория"
 }
+
+var categorySearchFields = core.NewSearchFields(
+	core.SearchField{Name: "code", Column: "CODE", Type: core.FieldString, Sortable: true},
+	core.SearchField{Name: "label", Column: "LABEL", Type: core.FieldString, Sortable: true},
+	core.SearchField{Name: "category_id", Column: "CATEGORYID", Type: core.FieldUint, Sortable: true, Nullable: true},
+)
+
+func (Category) SearchFields() *core.SearchFields {
+	return categorySearchFields
+}
@@ -1,8 +1,8 @@
 package category
 
 import (
+	"context"
 	"database/sql"
-	"encoding/json"
 	"net/http"
 	"strconv"
 
@@ -17,15 +17,21 @@ const (
 type CategoryHandler struct {
 	validate               *validator.Validate
 	categoryerationService CategoryService
+	crud                   *core.CrudHandler[Category, CategoryDTO]
+	batch                  *core.BatchHandler[Category, CategoryDTO, CategoryCreateRequest, CategoryUpdateRequest]
 }
 
 func NewCategoryHandler(
 	categoryerationService CategoryService,
 ) *CategoryHandler {
-	return &CategoryHandler{
-		validate:               validator.New(),
+	validate := core.DefaultValidator()
+	h := &CategoryHandler{
+		validate:               validate,
 		categoryerationService: categoryerationService,
+		crud:                   core.NewCrudHandler(categoryHandlerCode, validate, categoryerationService, core.MapWith(ToCategoryDTO)),
 	}
+	h.batch = core.NewBatchHandler(h.crud, categoryerationService, h.toCreatedCategory, h.toUpdatedCategory)
+	return h
 }
 
 // Create создает новую категорию
@@ -48,28 +54,20 @@ func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var req CategoryCreateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := core.DecodeJSON(r, &req); err != nil {
 		core.HandleError(w, r, core.NewTechnicalError(err, categoryHandlerCode, err.Error()))
 		return
 	}
 	if err := h.validate.Struct(req); err != nil {
-		details := core.CollectValidationDetails(err)
+		details := core.CollectValidationDetails(r.Context(), err)
 		core.HandleValidationError(w, r, core.NewLogicalError(err, categoryHandlerCode, err.Error()), details)
 		return
 	}
 
-	var categoryID sql.NullInt32
-	if req.CategoryID != nil {
-		categoryID = sql.NullInt32{
-			Int32: int32(*req.CategoryID),
-			Valid: true,
-		}
-	}
-
 	category := Category{
 		Code:       req.Code,
 		Label:      req.Label,
-		CategoryID: categoryID,
+		CategoryID: toNullCategoryID(req.CategoryID),
 	}
 	category, err := h.categoryerationService.Create(ctx, category)
 	if err != nil {
@@ -77,39 +75,105 @@ func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(ToCategoryDTO(category))
+	h.crud.WriteEntity(w, r, http.StatusCreated, category)
 }
 
-// GetAll возвращает все категории
-// @Summary Получить все категории
-// @Description Возвращает список всех категорий в системе
+// Batch создает, обновляет и удаляет категории одним запросом
+// @Summary Пакетная обработка категорий
+// @Description Применяет пакет операций над категориями. В режиме all_or_nothing (по умолчанию) ошибка любого элемента откатывает весь пакет,
+// @Description в режиме best_effort элементы применяются независимо. Результат возвращается по каждому элементу
 // @Tags Categories
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {array} CategoryDTO "Список категорий"
+// @Param request body CategoryBatchRequest true "Пакет операций"
+// @Success 200 {object} core.BatchResponse[CategoryDTO] "Все элементы применены"
+// @Success 207 {object} core.BatchResponse[CategoryDTO] "Часть элементов завершилась ошибкой"
+// @Failure 400 {object} core.ErrorResponse "Неверный режим или размер пакета"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
 // @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
-// @Router /categories [get]
-// @Id getCategoryAll
-func (h *CategoryHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+// @Router /categories/batch [post]
+// @Id batchCategories
+func (h *CategoryHandler) Batch(w http.ResponseWriter, r *http.Request) {
+	h.batch.Batch(w, r)
+}
+
+// toCreatedCategory проверяет элемент пакета на создание и собирает из него категорию
+func (h *CategoryHandler) toCreatedCategory(ctx context.Context, req CategoryCreateRequest) (Category, error) {
+	if err := h.validate.Struct(req); err != nil {
+		return Category{}, core.NewValidationError(err, core.ValidationErrorCode, "Данные запроса не прошли проверку, подробности в details")
+	}
+	if req.CategoryID != nil {
+		if _, err := h.categoryerationService.GetByID(ctx, *req.CategoryID); err != nil {
+			return Category{}, err
+		}
+	}
+	return Category{
+		Code:       req.Code,
+		Label:      req.Label,
+		CategoryID: toNullCategoryID(req.CategoryID),
+	}, nil
+}
+
+// toUpdatedCategory проверяет элемент пакета на обновление и применяет его к текущей категории
+func (h *CategoryHandler) toUpdatedCategory(ctx context.Context, req CategoryUpdateRequest) (Category, error) {
+	if err := h.validate.Struct(req); err != nil {
+		return Category{}, core.NewValidationError(err, core.ValidationErrorCode, "Данные запроса не прошли проверку, подробности в details")
+	}
 
-	categories, err := h.categoryerationService.GetAll(ctx)
+	category, err := h.categoryerationService.GetByID(ctx, req.ID)
 	if err != nil {
-		core.HandleError(w, r, err)
-		return
+		return Category{}, err
+	}
+	category.SetVersion(req.Version)
+	if req.Code != "" {
+		category.Code = req.Code
+	}
+	if req.Label != "" {
+		category.Label = req.Label
+	}
+	if req.CategoryID != nil {
+		if _, err := h.categoryerationService.GetByID(ctx, *req.CategoryID); err != nil {
+			return Category{}, err
+		}
+		category.CategoryID = toNullCategoryID(req.CategoryID)
 	}
+	return category, nil
+}
 
-	dtos := make([]CategoryDTO, 0, len(categories))
-	for _, category := range categories {
-		dtos = append(dtos, ToCategoryDTO(category))
+func toNullCategoryID(categoryID *uint) sql.NullInt32 {
+	if categoryID == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{
+		Int32: int32(*categoryID),
+		Valid: true,
 	}
+}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(dtos)
+// GetAll возвращает все категории
+// @Summary Получить все категории
+// @Description Возвращает список всех категорий в системе постранично, с фильтрацией и сортировкой из строки запроса
+// @Tags Categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param filter query string false "Условия через ';', например price>=100;category_id==3;status=in=(Available)"
+// @Param sort query string false "Поля сортировки через ',', префикс '-' - по убыванию, например -price,label"
+// @Param limit query int false "Размер страницы" default(20)
+// @Param offset query int false "Смещение"
+// @Param after query string false "Курсор следующей страницы"
+// @Param before query string false "Курсор предыдущей страницы"
+// @Success 200 {object} core.Page[CategoryDTO] "Список категорий"
+// @Failure 400 {object} core.ErrorResponse "Некорректные параметры фильтра"
+// @Failure 401 {object} core.ErrorResponse "Не авторизован"
+// @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
+// @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /categories [get]
+// @Id getCategoryAll
+func (h *CategoryHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	h.crud.GetAll(w, r)
 }
 
 // GetById возвращает категорию по ID
@@ -121,6 +185,7 @@ func (h *CategoryHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Param id path int true "ID категории"
 // @Success 200 {object} CategoryDTO "Категория"
+// @Header 200 {string} ETag "Версия записи"
 // @Failure 400 {object} core.ErrorResponse "Неверный ID"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
@@ -129,27 +194,7 @@ func (h *CategoryHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 // @Router /categories/{id} [get]
 // @Id getCategoryById
 func (h *CategoryHandler) GetById(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	reqID := r.PathValue("id")
-	if reqID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, categoryHandlerCode, "ID parameter missing"))
-		return
-	}
-	id, err := strconv.Atoi(reqID)
-	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, categoryHandlerCode, "ID parameter must be integer!"+err.Error()))
-		return
-	}
-
-	category, err := h.categoryerationService.GetByID(ctx, uint(id))
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(ToCategoryDTO(category))
+	h.crud.GetById(w, r)
 }
 
 // GetByCode возвращает категорию по коду
@@ -173,7 +218,7 @@ func (h *CategoryHandler) GetByCode(w http.ResponseWriter, r *http.Request) {
 
 	code := r.PathValue("code")
 	if code == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, categoryHandlerCode, "ID parameter missing"))
+		core.HandleError(w, r, core.NewLogicalError(nil, core.PathParamMissingCode, "Отсутствует параметр %s", "code"))
 		return
 	}
 
@@ -183,8 +228,7 @@ func (h *CategoryHandler) GetByCode(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(ToCategoryDTO(category))
+	h.crud.WriteEntity(w, r, http.StatusOK, category)
 }
 
 // GetWithSearchCriteria выполняет поиск категорий по критериям
@@ -195,7 +239,7 @@ func (h *CategoryHandler) GetByCode(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Security BearerAuth
 // @Param request body core.SearchCriteria true "Критерии поиска"
-// @Success 200 {array} CategoryDTO "Список найденных категорий"
+// @Success 200 {object} core.Page[CategoryDTO] "Список найденных категорий"
 // @Failure 400 {object} core.ErrorResponse "Ошибка валидации"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
@@ -203,32 +247,7 @@ func (h *CategoryHandler) GetByCode(w http.ResponseWriter, r *http.Request) {
 // @Router /categories/search [post]
 // @Id searchCategory
 func (h *CategoryHandler) GetWithSearchCriteria(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	var req core.SearchCriteria
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		core.HandleError(w, r, core.NewTechnicalError(err, categoryHandlerCode, err.Error()))
-		return
-	}
-	if err := h.validate.Struct(req); err != nil {
-		details := core.CollectValidationDetails(err)
-		core.HandleValidationError(w, r, core.NewLogicalError(err, categoryHandlerCode, err.Error()), details)
-		return
-	}
-
-	categories, err := h.categoryerationService.GetWithSearchCriteria(ctx, req)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	dtos := make([]CategoryDTO, 0, len(categories))
-	for _, category := range categories {
-		dtos = append(dtos, ToCategoryDTO(category))
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(dtos)
+	h.crud.GetWithSearchCriteria(w, r)
 }
 
 // Delete удаляет категорию
@@ -248,31 +267,7 @@ func (h *CategoryHandler) GetWithSearchCriteria(w http.ResponseWriter, r *http.R
 // @Router /categories/{id} [delete]
 // @Id deleteCategory
 func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	reqID := r.PathValue("id")
-	if reqID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, categoryHandlerCode, "ID parameter missing"))
-		return
-	}
-	id, err := strconv.Atoi(reqID)
-	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, categoryHandlerCode, "ID parameter must be integer!"+err.Error()))
-		return
-	}
-
-	category, err := h.categoryerationService.GetByID(ctx, uint(id))
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-	err = h.categoryerationService.Delete(ctx, category)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	w.WriteHeader(http.StatusNoContent)
+	h.crud.Delete(w, r)
 }
 
 // GetByCategoryID возвращает категории по родителю
@@ -296,12 +291,12 @@ func (h *CategoryHandler) GetByCategoryID(w http.ResponseWriter, r *http.Request
 
 	reqCategoryID := r.PathValue("category_id")
 	if reqCategoryID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, categoryHandlerCode, "ID parameter missing"))
+		core.HandleError(w, r, core.NewLogicalError(nil, core.PathParamMissingCode, "Отсутствует параметр %s", "category_id"))
 		return
 	}
 	categoryID, err := strconv.Atoi(reqCategoryID)
 	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, categoryHandlerCode, "CategoryID parameter must be integer!"+err.Error()))
+		core.HandleError(w, r, core.NewLogicalError(err, core.PathParamInvalidCode, "Параметр %s должен быть числовым", "category_id"))
 		return
 	}
 
@@ -311,11 +306,5 @@ func (h *CategoryHandler) GetByCategoryID(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	dtos := make([]CategoryDTO, 0, len(categories))
-	for _, category := range categories {
-		dtos = append(dtos, ToCategoryDTO(category))
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(dtos)
+	h.crud.WriteList(w, r, categories)
 }
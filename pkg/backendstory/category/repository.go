@@ -3,6 +3,7 @@ package category
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/ActuallyHello/backendstory/pkg/core"
 	"gorm.io/gorm"
@@ -30,7 +31,7 @@ func (r *categoryRepository) FindByCode(ctx context.Context, code string) (Categ
 	var category Category
 	if err := r.GetDB(ctx).Where("CODE = ?", code).First(&category).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return Category{}, core.NewNotFoundError("Категория не найдена по переданному коду")
+			return Category{}, core.NewNotFoundError(core.CategoryNotFoundCode, "Категория не найдена по переданному коду")
 		}
 		return Category{}, err
 	}
@@ -44,3 +45,33 @@ func (r *categoryRepository) FindByCategoryID(ctx context.Context, categoryID ui
 	}
 	return categories, nil
 }
+
+// cachedCategoryRepository кэширует чтение категорий, включая собственные выборки репозитория
+type cachedCategoryRepository struct {
+	*core.CachedRepository[Category]
+	categoryRepo CategoryRepository
+}
+
+func NewCachedCategoryRepository(
+	categoryRepo CategoryRepository,
+	backend core.CacheBackend,
+	prefix string,
+	ttl time.Duration,
+) *cachedCategoryRepository {
+	return &cachedCategoryRepository{
+		CachedRepository: core.NewCachedRepository[Category](categoryRepo, backend, prefix, ttl),
+		categoryRepo:     categoryRepo,
+	}
+}
+
+func (r *cachedCategoryRepository) FindByCode(ctx context.Context, code string) (Category, error) {
+	return core.CachedQuery(ctx, r.CachedRepository, "code", code, func(ctx context.Context) (Category, error) {
+		return r.categoryRepo.FindByCode(ctx, code)
+	})
+}
+
+func (r *cachedCategoryRepository) FindByCategoryID(ctx context.Context, categoryID uint) ([]Category, error) {
+	return core.CachedQuery(ctx, r.CachedRepository, "parent", categoryID, func(ctx context.Context) ([]Category, error) {
+		return r.categoryRepo.FindByCategoryID(ctx, categoryID)
+	})
+}
@@ -17,6 +17,7 @@ type CategoryService interface {
 	Create(ctx context.Context, category Category) (Category, error)
 	Update(ctx context.Context, category Category) (Category, error)
 	Delete(ctx context.Context, category Category) error
+	ExecuteBatch(ctx context.Context, batch *core.Batch[Category])
 
 	GetByCode(ctx context.Context, code string) (Category, error)
 	GetByCategoryID(ctx context.Context, categoryID uint) ([]Category, error)
@@ -25,14 +26,17 @@ type CategoryService interface {
 type categoryService struct {
 	core.BaseServiceImpl[Category]
 	categoryRepo CategoryRepository
+	txManager    core.TxManager
 }
 
 func NewCategoryService(
 	categoryRepo CategoryRepository,
+	txManager core.TxManager,
 ) *categoryService {
 	return &categoryService{
 		BaseServiceImpl: *core.NewBaseServiceImpl(categoryRepo),
 		categoryRepo:    categoryRepo,
+		txManager:       txManager,
 	}
 }
 
@@ -44,7 +48,7 @@ func (s *categoryService) Create(ctx context.Context, category Category) (Catego
 		return Category{}, err
 	}
 	if existing.ID > 0 {
-		return Category{}, core.NewLogicalError(nil, categoryServiceCode, "Категория уже существует")
+		return Category{}, core.NewConflictError(nil, core.CategoryAlreadyExistsCode, "Категория уже существует")
 	}
 
 	// Создаем запись
@@ -78,6 +82,26 @@ func (s *categoryService) Delete(ctx context.Context, category Category) error {
 	return nil
 }
 
+// ExecuteBatch применяет пакет операций над категориями, результат записывается в элементы пакета
+func (s *categoryService) ExecuteBatch(ctx context.Context, batch *core.Batch[Category]) {
+	core.ExecuteBatch(ctx, s.txManager, s.GetRepo(), batch, s.prepareBatchItem)
+}
+
+// prepareBatchItem выполняет для элемента пакета те же проверки, что и одиночные операции
+func (s *categoryService) prepareBatchItem(ctx context.Context, operation core.BatchOperation, category Category) (Category, error) {
+	if operation != core.BatchCreate {
+		return category, nil
+	}
+	existing, err := s.GetByCode(ctx, category.Code)
+	if err != nil && errors.Is(err, &core.TechnicalError{}) {
+		return Category{}, err
+	}
+	if existing.ID > 0 {
+		return Category{}, core.NewConflictError(nil, core.CategoryAlreadyExistsCode, "Категория уже существует")
+	}
+	return category, nil
+}
+
 // FindByCode ищет Category по коду
 func (s *categoryService) GetByCode(ctx context.Context, code string) (Category, error) {
 	category, err := s.categoryRepo.FindByCode(ctx, code)
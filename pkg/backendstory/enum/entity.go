@@ -15,3 +15,12 @@ func (Enum) TableName() string {
 func (Enum) LocalTableName() string {
 	return "Перечисление"
 }
+
+var enumSearchFields = core.NewSearchFields(
+	core.SearchField{Name: "code", Column: "CODE", Type: core.FieldString, Sortable: true},
+	core.SearchField{Name: "label", Column: "LABEL", Type: core.FieldString, Sortable: true},
+)
+
+func (Enum) SearchFields() *core.SearchFields {
+	return enumSearchFields
+}
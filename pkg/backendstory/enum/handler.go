@@ -3,7 +3,6 @@ package enum
 import (
 	"encoding/json"
 	"net/http"
-	"strconv"
 
 	"github.com/ActuallyHello/backendstory/pkg/core"
 	"github.com/go-playground/validator/v10"
@@ -16,14 +15,17 @@ const (
 type EnumHandler struct {
 	validate           *validator.Validate
 	enumerationService EnumService
+	crud               *core.CrudHandler[Enum, EnumDTO]
 }
 
 func NewEnumHandler(
 	enumerationService EnumService,
 ) *EnumHandler {
+	validate := core.DefaultValidator()
 	return &EnumHandler{
-		validate:           validator.New(),
+		validate:           validate,
 		enumerationService: enumerationService,
+		crud:               core.NewCrudHandler(enumHandlerCode, validate, enumerationService, core.MapWith(ToEnumDTO)),
 	}
 }
 
@@ -47,12 +49,12 @@ func (h *EnumHandler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var req EnumCreateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := core.DecodeJSON(r, &req); err != nil {
 		core.HandleError(w, r, core.NewTechnicalError(err, enumHandlerCode, err.Error()))
 		return
 	}
 	if err := h.validate.Struct(req); err != nil {
-		details := core.CollectValidationDetails(err)
+		details := core.CollectValidationDetails(r.Context(), err)
 		core.HandleValidationError(w, r, core.NewLogicalError(err, enumHandlerCode, err.Error()), details)
 		return
 	}
@@ -67,39 +69,31 @@ func (h *EnumHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(ToEnumDTO(enum))
+	h.crud.WriteEntity(w, r, http.StatusCreated, enum)
 }
 
 // GetAll возвращает все перечисления
 // @Summary Получить все перечисления
-// @Description Возвращает список всех перечислений в системе
+// @Description Возвращает список всех перечислений в системе постранично, с фильтрацией и сортировкой из строки запроса
 // @Tags Enumerations
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {array} EnumDTO "Список перечислений"
+// @Param filter query string false "Условия через ';', например price>=100;category_id==3;status=in=(Available)"
+// @Param sort query string false "Поля сортировки через ',', префикс '-' - по убыванию, например -price,label"
+// @Param limit query int false "Размер страницы" default(20)
+// @Param offset query int false "Смещение"
+// @Param after query string false "Курсор следующей страницы"
+// @Param before query string false "Курсор предыдущей страницы"
+// @Success 200 {object} core.Page[EnumDTO] "Список перечислений"
+// @Failure 400 {object} core.ErrorResponse "Некорректные параметры фильтра"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
 // @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
 // @Router /enumerations [get]
 // @Id getEnumAll
 func (h *EnumHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	enums, err := h.enumerationService.GetAll(ctx)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	dtos := make([]EnumDTO, 0, len(enums))
-	for _, enum := range enums {
-		dtos = append(dtos, ToEnumDTO(enum))
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(dtos)
+	h.crud.GetAll(w, r)
 }
 
 // GetById возвращает перечисление по ID
@@ -111,6 +105,7 @@ func (h *EnumHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Param id path int true "ID перечисления"
 // @Success 200 {object} EnumDTO "Перечисление"
+// @Header 200 {string} ETag "Версия записи"
 // @Failure 400 {object} core.ErrorResponse "Неверный ID"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
@@ -119,27 +114,7 @@ func (h *EnumHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 // @Router /enumerations/{id} [get]
 // @Id getEnumById
 func (h *EnumHandler) GetById(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	reqID := r.PathValue("id")
-	if reqID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, enumHandlerCode, "Отсуствует ИД параметр"))
-		return
-	}
-	id, err := strconv.Atoi(reqID)
-	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, enumHandlerCode, "ИД параметр должен быть числовым!"+err.Error()))
-		return
-	}
-
-	enum, err := h.enumerationService.GetByID(ctx, uint(id))
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(ToEnumDTO(enum))
+	h.crud.GetById(w, r)
 }
 
 // GetByCode возвращает перечисление по коду
@@ -163,7 +138,7 @@ func (h *EnumHandler) GetByCode(w http.ResponseWriter, r *http.Request) {
 
 	code := r.PathValue("code")
 	if code == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, enumHandlerCode, "Отсуствует параметр - код"))
+		core.HandleError(w, r, core.NewLogicalError(nil, core.PathParamMissingCode, "Отсутствует параметр %s", "code"))
 		return
 	}
 
@@ -173,8 +148,7 @@ func (h *EnumHandler) GetByCode(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(ToEnumDTO(enum))
+	h.crud.WriteEntity(w, r, http.StatusOK, enum)
 }
 
 // GetWithSearchCriteria выполняет поиск перечислений по критериям
@@ -185,7 +159,7 @@ func (h *EnumHandler) GetByCode(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Security BearerAuth
 // @Param request body core.SearchCriteria true "Критерии поиска"
-// @Success 200 {array} EnumDTO "Список найденных перечислений"
+// @Success 200 {object} core.Page[EnumDTO] "Список найденных перечислений"
 // @Failure 400 {object} core.ErrorResponse "Ошибка валидации"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
@@ -193,32 +167,7 @@ func (h *EnumHandler) GetByCode(w http.ResponseWriter, r *http.Request) {
 // @Router /enumerations/search [post]
 // @Id searchEnum
 func (h *EnumHandler) GetWithSearchCriteria(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	var req core.SearchCriteria
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		core.HandleError(w, r, core.NewTechnicalError(err, enumHandlerCode, err.Error()))
-		return
-	}
-	if err := h.validate.Struct(req); err != nil {
-		details := core.CollectValidationDetails(err)
-		core.HandleValidationError(w, r, core.NewLogicalError(err, enumHandlerCode, err.Error()), details)
-		return
-	}
-
-	enums, err := h.enumerationService.GetWithSearchCriteria(ctx, req)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	dtos := make([]EnumDTO, 0, len(enums))
-	for _, enum := range enums {
-		dtos = append(dtos, ToEnumDTO(enum))
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(dtos)
+	h.crud.GetWithSearchCriteria(w, r)
 }
 
 // Delete удаляет перечисление
@@ -238,29 +187,22 @@ func (h *EnumHandler) GetWithSearchCriteria(w http.ResponseWriter, r *http.Reque
 // @Router /enumerations/{id} [delete]
 // @Id deleteEnum
 func (h *EnumHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	reqID := r.PathValue("id")
-	if reqID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, enumHandlerCode, "Отсуствует ИД параметр"))
-		return
-	}
-	id, err := strconv.Atoi(reqID)
-	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, enumHandlerCode, "ИД параметр должен быть числовым!"+err.Error()))
-		return
-	}
-
-	enum, err := h.enumerationService.GetByID(ctx, uint(id))
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-	err = h.enumerationService.Delete(ctx, enum)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
+	h.crud.Delete(w, r)
+}
 
-	w.WriteHeader(http.StatusNoContent)
+// CacheStats возвращает статистику кэша перечислений
+// @Summary Статистика кэша перечислений
+// @Description Возвращает количество попаданий, промахов и записей в кэше перечислений
+// @Tags Enumerations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} core.CacheStats "Статистика кэша"
+// @Failure 401 {object} core.ErrorResponse "Не авторизован"
+// @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
+// @Router /enumerations/cache-stats [get]
+// @Id getEnumCacheStats
+func (h *EnumHandler) CacheStats(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(h.enumerationService.CacheStats())
 }
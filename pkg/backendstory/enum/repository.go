@@ -28,7 +28,7 @@ func (r *enumRepository) FindByCode(ctx context.Context, code string) (Enum, err
 	var enum Enum
 	if err := r.GetDB(ctx).Where("CODE = ?", code).First(&enum).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return Enum{}, core.NewNotFoundError("Перечисление не найдено по переданному коду")
+			return Enum{}, core.NewNotFoundError(core.EnumNotFoundCode, "Перечисление не найдено по переданному коду")
 		}
 		return Enum{}, err
 	}
@@ -3,6 +3,7 @@ package enum
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/ActuallyHello/backendstory/pkg/core"
 )
@@ -19,19 +20,27 @@ type EnumService interface {
 	Delete(ctx context.Context, enum Enum) error
 
 	GetByCode(ctx context.Context, code string) (Enum, error)
+
+	WarmUp(ctx context.Context) error
+	CacheStats() core.CacheStats
 }
 
 type enumService struct {
 	core.BaseServiceImpl[Enum]
 	enumRepo EnumRepository
+
+	// cache перечисления по коду, сбрасывается при любом изменении перечислений
+	cache *core.TTLCache[string, Enum]
 }
 
 func NewEnumService(
 	enumRepo EnumRepository,
+	cacheTTL time.Duration,
 ) *enumService {
 	return &enumService{
 		BaseServiceImpl: *core.NewBaseServiceImpl(enumRepo),
 		enumRepo:        enumRepo,
+		cache:           core.NewTTLCache[string, Enum](cacheTTL),
 	}
 }
 
@@ -41,13 +50,14 @@ func (s *enumService) Create(ctx context.Context, enum Enum) (Enum, error) {
 		return Enum{}, err
 	}
 	if existing.ID > 0 {
-		return Enum{}, core.NewLogicalError(nil, enumServiceCode, "Перечисление уже существует")
+		return Enum{}, core.NewConflictError(nil, core.EnumAlreadyExistsCode, "Перечисление уже существует")
 	}
 
 	created, err := s.GetRepo().Create(ctx, enum)
 	if err != nil {
 		return Enum{}, core.NewTechnicalError(err, enumServiceCode, "Ошибка при создании перечисления")
 	}
+	s.clearCache(ctx)
 	return created, nil
 }
 
@@ -61,6 +71,7 @@ func (s *enumService) Update(ctx context.Context, enum Enum) (Enum, error) {
 	if err != nil {
 		return Enum{}, core.NewTechnicalError(err, enumServiceCode, "Ошибка при обновлении перечисления")
 	}
+	s.clearCache(ctx)
 	return updated, nil
 }
 
@@ -69,10 +80,23 @@ func (s *enumService) Delete(ctx context.Context, enum Enum) error {
 	if err != nil {
 		return core.NewTechnicalError(err, enumServiceCode, "Ошибка при удалении перечисления")
 	}
+	s.clearCache(ctx)
+	return nil
+}
+
+func (s *enumService) Purge(ctx context.Context, id uint) error {
+	if err := s.BaseServiceImpl.Purge(ctx, id); err != nil {
+		return err
+	}
+	s.clearCache(ctx)
 	return nil
 }
 
 func (s *enumService) GetByCode(ctx context.Context, code string) (Enum, error) {
+	if enum, ok := s.cache.Get(code); ok {
+		return enum, nil
+	}
+
 	enum, err := s.enumRepo.FindByCode(ctx, code)
 	if err != nil {
 		if errors.Is(err, &core.NotFoundError{}) {
@@ -80,5 +104,33 @@ func (s *enumService) GetByCode(ctx context.Context, code string) (Enum, error)
 		}
 		return Enum{}, core.NewTechnicalError(err, enumServiceCode, "Ошибка при получении перечисления по коду")
 	}
+	s.cache.Set(code, enum)
 	return enum, nil
 }
+
+// clearCache сбрасывает кэш после фиксации транзакции, в которой изменено перечисление
+func (s *enumService) clearCache(ctx context.Context) {
+	core.AfterCommit(ctx, func(ctx context.Context) error {
+		s.cache.Clear()
+		return nil
+	})
+}
+
+// WarmUp загружает все перечисления в кэш
+func (s *enumService) WarmUp(ctx context.Context) error {
+	enums, err := s.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.cache.Clear()
+	for _, enum := range enums {
+		s.cache.Set(enum.Code, enum)
+	}
+	return nil
+}
+
+// CacheStats возвращает статистику кэша перечислений
+func (s *enumService) CacheStats() core.CacheStats {
+	return s.cache.Stats()
+}
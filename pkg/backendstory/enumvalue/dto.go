@@ -2,6 +2,8 @@ package enumvalue
 
 import (
 	"time"
+
+	"github.com/ActuallyHello/backendstory/pkg/core"
 )
 
 // EnumValueCreateRequest represents request for creating enum value
@@ -41,3 +43,10 @@ func ToEnumValueDTO(enumValue EnumValue) EnumValueDTO {
 		EnumID:    enumValue.EnumID,
 	}
 }
+
+// EnumValueCacheStats represents enum value cache statistics
+// @Name EnumValueCacheStats
+type EnumValueCacheStats struct {
+	ByID   core.CacheStats `json:"by_id"`
+	ByCode core.CacheStats `json:"by_code"`
+}
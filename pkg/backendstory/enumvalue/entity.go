@@ -16,3 +16,13 @@ func (EnumValue) TableName() string {
 func (EnumValue) LocalTableName() string {
 	return "Значение перечисления"
 }
+
+var enumValueSearchFields = core.NewSearchFields(
+	core.SearchField{Name: "code", Column: "CODE", Type: core.FieldString, Sortable: true},
+	core.SearchField{Name: "label", Column: "LABEL", Type: core.FieldString, Sortable: true},
+	core.SearchField{Name: "enum_id", Column: "ENUMERATIONID", Type: core.FieldUint, Sortable: true},
+)
+
+func (EnumValue) SearchFields() *core.SearchFields {
+	return enumValueSearchFields
+}
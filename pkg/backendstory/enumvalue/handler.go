@@ -16,14 +16,17 @@ const (
 type EnumValueHandler struct {
 	validate         *validator.Validate
 	enumValueService EnumValueService
+	crud             *core.CrudHandler[EnumValue, EnumValueDTO]
 }
 
 func NewEnumValueHandler(
 	enumValueService EnumValueService,
 ) *EnumValueHandler {
+	validate := core.DefaultValidator()
 	return &EnumValueHandler{
-		validate:         validator.New(),
+		validate:         validate,
 		enumValueService: enumValueService,
+		crud:             core.NewCrudHandler(enumValueHandlerCode, validate, enumValueService, core.MapWith(ToEnumValueDTO)),
 	}
 }
 
@@ -46,12 +49,12 @@ func (h *EnumValueHandler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var req EnumValueCreateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := core.DecodeJSON(r, &req); err != nil {
 		core.HandleError(w, r, core.NewTechnicalError(err, enumValueHandlerCode, err.Error()))
 		return
 	}
 	if err := h.validate.Struct(req); err != nil {
-		details := core.CollectValidationDetails(err)
+		details := core.CollectValidationDetails(r.Context(), err)
 		core.HandleValidationError(w, r, core.NewLogicalError(err, enumValueHandlerCode, err.Error()), details)
 		return
 	}
@@ -67,39 +70,31 @@ func (h *EnumValueHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(ToEnumValueDTO(enumValue))
+	h.crud.WriteEntity(w, r, http.StatusCreated, enumValue)
 }
 
 // GetAll возвращает все значения перечислений
 // @Summary Получить все значения перечислений
-// @Description Возвращает список всех значений перечислений
+// @Description Возвращает список всех значений перечислений постранично, с фильтрацией и сортировкой из строки запроса
 // @Tags Enumeration Values
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {array} EnumValueDTO "Список значений перечислений"
+// @Param filter query string false "Условия через ';', например price>=100;category_id==3;status=in=(Available)"
+// @Param sort query string false "Поля сортировки через ',', префикс '-' - по убыванию, например -price,label"
+// @Param limit query int false "Размер страницы" default(20)
+// @Param offset query int false "Смещение"
+// @Param after query string false "Курсор следующей страницы"
+// @Param before query string false "Курсор предыдущей страницы"
+// @Success 200 {object} core.Page[EnumValueDTO] "Список значений перечислений"
+// @Failure 400 {object} core.ErrorResponse "Некорректные параметры фильтра"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
 // @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
 // @Router /enumeration-values [get]
 // @Id getEnumValueAll
 func (h *EnumValueHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	enumValues, err := h.enumValueService.GetAll(ctx)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	dtos := make([]EnumValueDTO, 0, len(enumValues))
-	for _, enumValue := range enumValues {
-		dtos = append(dtos, ToEnumValueDTO(enumValue))
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(dtos)
+	h.crud.GetAll(w, r)
 }
 
 // GetById возвращает значение перечисления по ID
@@ -111,6 +106,7 @@ func (h *EnumValueHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Param id path int true "ID значения перечисления"
 // @Success 200 {object} EnumValueDTO "Значение перечисления"
+// @Header 200 {string} ETag "Версия записи"
 // @Failure 400 {object} core.ErrorResponse "Неверный ID"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
@@ -119,27 +115,7 @@ func (h *EnumValueHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 // @Router /enumeration-values/{id} [get]
 // @Id getEnumValueById
 func (h *EnumValueHandler) GetById(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	reqID := r.PathValue("id")
-	if reqID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, enumValueHandlerCode, "Отсуствует ИД параметр"))
-		return
-	}
-	id, err := strconv.Atoi(reqID)
-	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, enumValueHandlerCode, "ИД параметр должен быть числовым!"+err.Error()))
-		return
-	}
-
-	enumValue, err := h.enumValueService.GetByID(ctx, uint(id))
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(ToEnumValueDTO(enumValue))
+	h.crud.GetById(w, r)
 }
 
 // GetByEnumId возвращает значения перечисления по ID перечисления
@@ -162,12 +138,12 @@ func (h *EnumValueHandler) GetByEnumId(w http.ResponseWriter, r *http.Request) {
 
 	reqEnumID := r.PathValue("enumeration_id")
 	if reqEnumID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, enumValueHandlerCode, "Отсутствует ИД параметр"))
+		core.HandleError(w, r, core.NewLogicalError(nil, core.PathParamMissingCode, "Отсутствует параметр %s", "enumeration_id"))
 		return
 	}
 	enumID, err := strconv.Atoi(reqEnumID)
 	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, enumValueHandlerCode, "ИД параметр должен быть числовым!"+err.Error()))
+		core.HandleError(w, r, core.NewLogicalError(err, core.PathParamInvalidCode, "Параметр %s должен быть числовым", "enumeration_id"))
 		return
 	}
 
@@ -177,13 +153,7 @@ func (h *EnumValueHandler) GetByEnumId(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	dtos := make([]EnumValueDTO, 0, len(enumValues))
-	for _, enumValue := range enumValues {
-		dtos = append(dtos, ToEnumValueDTO(enumValue))
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(dtos)
+	h.crud.WriteList(w, r, enumValues)
 }
 
 // Delete удаляет значение перечисления
@@ -203,31 +173,7 @@ func (h *EnumValueHandler) GetByEnumId(w http.ResponseWriter, r *http.Request) {
 // @Router /enumeration-values/{id} [delete]
 // @Id deleteEnumValue
 func (h *EnumValueHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	reqID := r.PathValue("id")
-	if reqID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, enumValueHandlerCode, "Отсутствует ИД параметр"))
-		return
-	}
-	id, err := strconv.Atoi(reqID)
-	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, enumValueHandlerCode, "ИД параметр должен быть числовым!"+err.Error()))
-		return
-	}
-
-	enumValue, err := h.enumValueService.GetByID(ctx, uint(id))
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-	err = h.enumValueService.Delete(ctx, enumValue)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	w.WriteHeader(http.StatusNoContent)
+	h.crud.Delete(w, r)
 }
 
 // GetWithSearchCriteria выполняет поиск значений перечислений по критериям
@@ -238,7 +184,7 @@ func (h *EnumValueHandler) Delete(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Security BearerAuth
 // @Param request body core.SearchCriteria true "Критерии поиска"
-// @Success 200 {array} EnumValueDTO "Список найденных значений перечислений"
+// @Success 200 {object} core.Page[EnumValueDTO] "Список найденных значений перечислений"
 // @Failure 400 {object} core.ErrorResponse "Ошибка валидации"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
@@ -246,30 +192,22 @@ func (h *EnumValueHandler) Delete(w http.ResponseWriter, r *http.Request) {
 // @Router /enumeration-values/search [post]
 // @Id searchEnumValue
 func (h *EnumValueHandler) GetWithSearchCriteria(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	var req core.SearchCriteria
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		core.HandleError(w, r, core.NewTechnicalError(err, enumValueHandlerCode, err.Error()))
-		return
-	}
-	if err := h.validate.Struct(req); err != nil {
-		details := core.CollectValidationDetails(err)
-		core.HandleValidationError(w, r, core.NewLogicalError(err, enumValueHandlerCode, err.Error()), details)
-		return
-	}
-
-	enumValues, err := h.enumValueService.GetWithSearchCriteria(ctx, req)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	dtos := make([]EnumValueDTO, 0, len(enumValues))
-	for _, enumValue := range enumValues {
-		dtos = append(dtos, ToEnumValueDTO(enumValue))
-	}
+	h.crud.GetWithSearchCriteria(w, r)
+}
 
+// CacheStats возвращает статистику кэшей значений перечислений
+// @Summary Статистика кэша значений перечислений
+// @Description Возвращает количество попаданий, промахов и записей в кэшах значений перечислений по ID и по коду
+// @Tags Enumeration Values
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} EnumValueCacheStats "Статистика кэша"
+// @Failure 401 {object} core.ErrorResponse "Не авторизован"
+// @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
+// @Router /enumeration-values/cache-stats [get]
+// @Id getEnumValueCacheStats
+func (h *EnumValueHandler) CacheStats(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(dtos)
+	json.NewEncoder(w).Encode(h.enumValueService.CacheStats())
 }
@@ -30,7 +30,7 @@ func (r *enumValueRepository) FindByCodeAndEnumID(ctx context.Context, code stri
 	var enumValue EnumValue
 	if err := r.GetDB(ctx).Where("CODE = ?", code).Where("ENUMERATIONID = ?", enumerationID).First(&enumValue).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return EnumValue{}, core.NewNotFoundError("Значение перечисления не существует")
+			return EnumValue{}, core.NewNotFoundError(core.EnumValueNotFoundCode, "Значение перечисления не существует")
 		}
 		return EnumValue{}, err
 	}
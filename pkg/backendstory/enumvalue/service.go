@@ -3,6 +3,9 @@ package enumvalue
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"time"
 
 	"github.com/ActuallyHello/backendstory/pkg/backendstory/enum"
 	"github.com/ActuallyHello/backendstory/pkg/core"
@@ -22,34 +25,59 @@ type EnumValueService interface {
 	GetByEnumID(ctx context.Context, enumID uint) ([]EnumValue, error)
 	GetByCodeAndEnumID(ctx context.Context, code string, enumID uint) (EnumValue, error)
 	GetByCodeAndEnumCode(ctx context.Context, code, enumCode string) (EnumValue, error)
+	GetMapByIDs(ctx context.Context, ids []uint) (map[uint]EnumValue, error)
+
+	WarmUp(ctx context.Context) error
+	CacheStats() EnumValueCacheStats
 }
 
 type enumValueService struct {
 	core.BaseServiceImpl[EnumValue]
 	enumValueRepo EnumValueRepository
 	enumService   enum.EnumService
+
+	// кэши значений по ИД и по паре код + ИД перечисления, сбрасываются при любом изменении значений
+	byID   *core.TTLCache[uint, EnumValue]
+	byCode *core.TTLCache[string, EnumValue]
 }
 
 func NewEnumValueService(
 	enumValueRepo EnumValueRepository,
 	enumService enum.EnumService,
+	cacheTTL time.Duration,
 ) *enumValueService {
 	return &enumValueService{
 		BaseServiceImpl: *core.NewBaseServiceImpl(enumValueRepo),
 		enumValueRepo:   enumValueRepo,
 		enumService:     enumService,
+		byID:            core.NewTTLCache[uint, EnumValue](cacheTTL),
+		byCode:          core.NewTTLCache[string, EnumValue](cacheTTL),
 	}
 }
 
+func codeCacheKey(code string, enumID uint) string {
+	return fmt.Sprintf("%d:%s", enumID, code)
+}
+
+func (s *enumValueService) cacheValue(enumValue EnumValue) {
+	s.byID.Set(enumValue.ID, enumValue)
+	s.byCode.Set(codeCacheKey(enumValue.Code, enumValue.EnumID), enumValue)
+}
+
+func (s *enumValueService) invalidateCache() {
+	s.byID.Clear()
+	s.byCode.Clear()
+}
+
 func (s *enumValueService) Create(ctx context.Context, enumValue EnumValue) (EnumValue, error) {
 	existing, err := s.GetByCodeAndEnumID(ctx, enumValue.Code, enumValue.EnumID)
 	if err != nil && errors.Is(err, &core.TechnicalError{}) {
 		return EnumValue{}, err
 	}
 	if existing.ID > 0 {
-		return EnumValue{}, core.NewLogicalError(
+		return EnumValue{}, core.NewConflictError(
 			nil,
-			enumValueServiceCode,
+			core.EnumValueAlreadyExistsCode,
 			"Значение перечислимого типа уже существует",
 		)
 	}
@@ -58,6 +86,7 @@ func (s *enumValueService) Create(ctx context.Context, enumValue EnumValue) (Enu
 	if err != nil {
 		return EnumValue{}, core.NewTechnicalError(err, enumValueServiceCode, "Невозможно создать значение перечислимого типа")
 	}
+	s.invalidateCache()
 	return created, nil
 }
 
@@ -71,6 +100,7 @@ func (s *enumValueService) Update(ctx context.Context, enumValue EnumValue) (Enu
 	if err != nil {
 		return EnumValue{}, core.NewTechnicalError(err, enumValueServiceCode, "Ошибка при обновлении значения перечислимого типа")
 	}
+	s.invalidateCache()
 	return updated, nil
 }
 
@@ -79,9 +109,68 @@ func (s *enumValueService) Delete(ctx context.Context, enumValue EnumValue) erro
 	if err != nil {
 		return core.NewTechnicalError(err, enumValueServiceCode, "Ошибка при удалении значения перечислимого типа")
 	}
+	s.invalidateCache()
+	return nil
+}
+
+func (s *enumValueService) Purge(ctx context.Context, id uint) error {
+	if err := s.BaseServiceImpl.Purge(ctx, id); err != nil {
+		return err
+	}
+	s.invalidateCache()
 	return nil
 }
 
+func (s *enumValueService) GetByID(ctx context.Context, id uint) (EnumValue, error) {
+	if enumValue, ok := s.byID.Get(id); ok {
+		return enumValue, nil
+	}
+
+	enumValue, err := s.BaseServiceImpl.GetByID(ctx, id)
+	if err != nil {
+		return EnumValue{}, err
+	}
+	s.cacheValue(enumValue)
+	return enumValue, nil
+}
+
+// GetMapByIDs возвращает значения перечислений по ID. Значения, которых нет в кэше,
+// запрашиваются одним запросом. Отсутствие любого из значений считается ошибкой
+func (s *enumValueService) GetMapByIDs(ctx context.Context, ids []uint) (map[uint]EnumValue, error) {
+	values := make(map[uint]EnumValue, len(ids))
+	var missing []uint
+	for _, id := range ids {
+		if _, ok := values[id]; ok {
+			continue
+		}
+		if enumValue, ok := s.byID.Get(id); ok {
+			values[id] = enumValue
+			continue
+		}
+		if !slices.Contains(missing, id) {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return values, nil
+	}
+
+	found, err := s.enumValueRepo.FindByIDs(ctx, missing)
+	if err != nil {
+		return nil, core.NewTechnicalError(err, enumValueServiceCode, "Ошибка при получении значений перечислений по ИД")
+	}
+	for _, enumValue := range found {
+		s.cacheValue(enumValue)
+		values[enumValue.ID] = enumValue
+	}
+	for _, id := range missing {
+		if _, ok := values[id]; !ok {
+			return nil, core.NewNotFoundError(core.EnumValueNotFoundCode, "Значение перечисления с ИД %d не существует", id)
+		}
+	}
+	return values, nil
+}
+
 func (s *enumValueService) GetByEnumID(ctx context.Context, enumID uint) ([]EnumValue, error) {
 	values, err := s.enumValueRepo.FindByEnumID(ctx, enumID)
 	if err != nil {
@@ -91,6 +180,10 @@ func (s *enumValueService) GetByEnumID(ctx context.Context, enumID uint) ([]Enum
 }
 
 func (s *enumValueService) GetByCodeAndEnumID(ctx context.Context, code string, enumID uint) (EnumValue, error) {
+	if enumValue, ok := s.byCode.Get(codeCacheKey(code, enumID)); ok {
+		return enumValue, nil
+	}
+
 	enumValue, err := s.enumValueRepo.FindByCodeAndEnumID(ctx, code, enumID)
 	if err != nil {
 		if errors.Is(err, &core.NotFoundError{}) {
@@ -98,6 +191,7 @@ func (s *enumValueService) GetByCodeAndEnumID(ctx context.Context, code string,
 		}
 		return EnumValue{}, core.NewTechnicalError(err, enumValueServiceCode, "Ошибка при поиске значения перечисления по коду и родителю")
 	}
+	s.cacheValue(enumValue)
 	return enumValue, nil
 }
 
@@ -106,13 +200,27 @@ func (s *enumValueService) GetByCodeAndEnumCode(ctx context.Context, code, enumC
 	if err != nil {
 		return EnumValue{}, err
 	}
+	return s.GetByCodeAndEnumID(ctx, code, enum.ID)
+}
 
-	enumValue, err := s.enumValueRepo.FindByCodeAndEnumID(ctx, code, enum.ID)
+// WarmUp загружает все значения перечислений в кэш
+func (s *enumValueService) WarmUp(ctx context.Context) error {
+	enumValues, err := s.GetAll(ctx)
 	if err != nil {
-		if errors.Is(err, &core.NotFoundError{}) {
-			return EnumValue{}, core.NewLogicalError(err, enumValueServiceCode, err.Error())
-		}
-		return EnumValue{}, core.NewTechnicalError(err, enumValueServiceCode, "Ошибка при поиске значения перечисления по коду и родителю")
+		return err
+	}
+
+	s.invalidateCache()
+	for _, enumValue := range enumValues {
+		s.cacheValue(enumValue)
+	}
+	return nil
+}
+
+// CacheStats возвращает статистику кэшей значений перечислений
+func (s *enumValueService) CacheStats() EnumValueCacheStats {
+	return EnumValueCacheStats{
+		ByID:   s.byID.Stats(),
+		ByCode: s.byCode.Stats(),
 	}
-	return enumValue, nil
 }
@@ -0,0 +1,193 @@
+package enumvalue
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ActuallyHello/backendstory/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRepository репозиторий значений в памяти, считающий обращения к "базе"
+type memoryRepository struct {
+	EnumValueRepository
+
+	rows map[uint]EnumValue
+	err  error
+
+	findByIDCalls   int
+	findByIDsCalls  [][]uint
+	findByCodeCalls int
+}
+
+func newMemoryRepository(rows ...EnumValue) *memoryRepository {
+	repo := &memoryRepository{rows: make(map[uint]EnumValue)}
+	for _, row := range rows {
+		repo.rows[row.ID] = row
+	}
+	return repo
+}
+
+func (r *memoryRepository) FindByID(_ context.Context, id uint) (EnumValue, error) {
+	r.findByIDCalls++
+	enumValue, ok := r.rows[id]
+	if !ok {
+		return EnumValue{}, core.NewNotFoundError(core.RecordNotFoundCode, "Значение перечисления с ИД %d не существует", id)
+	}
+	return enumValue, nil
+}
+
+func (r *memoryRepository) FindByIDs(_ context.Context, ids []uint) ([]EnumValue, error) {
+	r.findByIDsCalls = append(r.findByIDsCalls, ids)
+	if r.err != nil {
+		return nil, r.err
+	}
+	values := make([]EnumValue, 0, len(ids))
+	for _, id := range ids {
+		if enumValue, ok := r.rows[id]; ok {
+			values = append(values, enumValue)
+		}
+	}
+	return values, nil
+}
+
+func (r *memoryRepository) FindAll(context.Context) ([]EnumValue, error) {
+	values := make([]EnumValue, 0, len(r.rows))
+	for _, enumValue := range r.rows {
+		values = append(values, enumValue)
+	}
+	return values, nil
+}
+
+func (r *memoryRepository) FindByCodeAndEnumID(_ context.Context, code string, enumID uint) (EnumValue, error) {
+	r.findByCodeCalls++
+	for _, enumValue := range r.rows {
+		if enumValue.Code == code && enumValue.EnumID == enumID {
+			return enumValue, nil
+		}
+	}
+	return EnumValue{}, core.NewNotFoundError(core.EnumValueNotFoundCode, "Значение перечисления не существует")
+}
+
+func (r *memoryRepository) Update(_ context.Context, enumValue EnumValue) (EnumValue, error) {
+	enumValue.Version++
+	r.rows[enumValue.ID] = enumValue
+	return enumValue, nil
+}
+
+func newEnumValue(id uint, code string) EnumValue {
+	return EnumValue{Base: core.Base{ID: id, Version: 1}, Code: code, Label: code, EnumID: 1}
+}
+
+func TestGetByIDCachesValue(t *testing.T) {
+	repo := newMemoryRepository(newEnumValue(1, "Available"))
+	service := NewEnumValueService(repo, nil, time.Hour)
+
+	for range 3 {
+		enumValue, err := service.GetByID(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, "Available", enumValue.Code)
+	}
+	assert.Equal(t, 1, repo.findByIDCalls)
+
+	// значение, найденное по ИД, доступно и по коду
+	_, err := service.GetByCodeAndEnumID(context.Background(), "Available", 1)
+	require.NoError(t, err)
+	assert.Zero(t, repo.findByCodeCalls)
+	assert.Equal(t, int64(2), service.CacheStats().ByID.Hits)
+}
+
+func TestUpdateInvalidatesCache(t *testing.T) {
+	repo := newMemoryRepository(newEnumValue(1, "Available"))
+	service := NewEnumValueService(repo, nil, time.Hour)
+	_, err := service.GetByID(context.Background(), 1)
+	require.NoError(t, err)
+
+	updated := newEnumValue(1, "Available")
+	updated.Label = "В наличии"
+	_, err = service.Update(context.Background(), updated)
+	require.NoError(t, err)
+
+	enumValue, err := service.GetByID(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "В наличии", enumValue.Label)
+	assert.Equal(t, 2, repo.findByIDCalls)
+}
+
+func TestWarmUpFillsCache(t *testing.T) {
+	repo := newMemoryRepository(newEnumValue(1, "Available"), newEnumValue(2, "Unavailable"))
+	service := NewEnumValueService(repo, nil, time.Hour)
+
+	require.NoError(t, service.WarmUp(context.Background()))
+
+	for _, id := range []uint{1, 2} {
+		_, err := service.GetByID(context.Background(), id)
+		require.NoError(t, err)
+	}
+	_, err := service.GetByCodeAndEnumID(context.Background(), "Unavailable", 1)
+	require.NoError(t, err)
+
+	assert.Zero(t, repo.findByIDCalls)
+	assert.Zero(t, repo.findByCodeCalls)
+	assert.Equal(t, 2, service.CacheStats().ByID.Size)
+}
+
+func TestGetMapByIDsLoadsMissingInOneQuery(t *testing.T) {
+	repo := newMemoryRepository(newEnumValue(1, "Available"), newEnumValue(2, "Unavailable"), newEnumValue(3, "Archived"))
+	service := NewEnumValueService(repo, nil, time.Hour)
+	_, err := service.GetByID(context.Background(), 1)
+	require.NoError(t, err)
+
+	values, err := service.GetMapByIDs(context.Background(), []uint{1, 2, 3, 2, 1})
+	require.NoError(t, err)
+	assert.Len(t, values, 3)
+	assert.Equal(t, "Unavailable", values[2].Code)
+	// из базы запрашиваются только значения, которых нет в кэше, без повторов
+	assert.Equal(t, [][]uint{{2, 3}}, repo.findByIDsCalls)
+
+	_, err = service.GetMapByIDs(context.Background(), []uint{2, 3})
+	require.NoError(t, err)
+	assert.Len(t, repo.findByIDsCalls, 1)
+}
+
+func TestGetMapByIDsEmpty(t *testing.T) {
+	repo := newMemoryRepository()
+	service := NewEnumValueService(repo, nil, time.Hour)
+
+	values, err := service.GetMapByIDs(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, values)
+	assert.Empty(t, repo.findByIDsCalls)
+}
+
+func TestGetMapByIDsMissingValueIsNotFound(t *testing.T) {
+	repo := newMemoryRepository(newEnumValue(1, "Available"))
+	service := NewEnumValueService(repo, nil, time.Hour)
+
+	values, err := service.GetMapByIDs(context.Background(), []uint{1, 7})
+	assert.Nil(t, values)
+	var notFoundErr *core.NotFoundError
+	require.ErrorAs(t, err, &notFoundErr)
+	assert.Equal(t, core.EnumValueNotFoundCode, notFoundErr.Code)
+	assert.Equal(t, http.StatusNotFound, core.ResolveProblem(err).Problem.Status)
+
+	// найденные значения все равно попадают в кэш
+	_, err = service.GetByID(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Zero(t, repo.findByIDCalls)
+}
+
+func TestGetMapByIDsRepositoryError(t *testing.T) {
+	repo := newMemoryRepository()
+	repo.err = errors.New("connection refused")
+	service := NewEnumValueService(repo, nil, time.Hour)
+
+	_, err := service.GetMapByIDs(context.Background(), []uint{1})
+	var technicalErr *core.TechnicalError
+	require.ErrorAs(t, err, &technicalErr)
+	assert.ErrorIs(t, err, repo.err)
+}
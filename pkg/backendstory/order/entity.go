@@ -30,3 +30,15 @@ func (Order) TableName() string {
 func (Order) LocalTableName() string {
 	return "Заказ"
 }
+
+var orderSearchFields = core.NewSearchFields(
+	core.SearchField{Name: "details", Column: "DETAILS", Type: core.FieldString},
+	core.SearchField{Name: "status", Column: "STATUSID", Type: core.FieldEnum, EnumCode: OrderStatus},
+	core.SearchField{Name: "status_id", Column: "STATUSID", Type: core.FieldUint, Sortable: true},
+	core.SearchField{Name: "client_id", Column: "CLIENTID", Type: core.FieldUint, Sortable: true},
+	core.SearchField{Name: "manager_id", Column: "MANAGERID", Type: core.FieldUint, Sortable: true, Nullable: true},
+)
+
+func (Order) SearchFields() *core.SearchFields {
+	return orderSearchFields
+}
@@ -0,0 +1,40 @@
+package order
+
+const (
+	orderAggregate = "Order"
+
+	OrderCreatedEvent   = "OrderCreated"
+	OrderApprovedEvent  = "OrderApproved"
+	OrderCancelledEvent = "OrderCancelled"
+)
+
+// OrderCreated заказ создан из товаров корзины
+type OrderCreated struct {
+	OrderID     uint   `json:"order_id"`
+	ClientID    uint   `json:"client_id"`
+	CartItemIDs []uint `json:"cart_item_ids"`
+}
+
+func (e OrderCreated) EventType() string     { return OrderCreatedEvent }
+func (e OrderCreated) AggregateType() string { return orderAggregate }
+func (e OrderCreated) AggregateID() uint     { return e.OrderID }
+
+// OrderApproved заказ подтвержден, товар списан со склада
+type OrderApproved struct {
+	OrderID  uint `json:"order_id"`
+	ClientID uint `json:"client_id"`
+}
+
+func (e OrderApproved) EventType() string     { return OrderApprovedEvent }
+func (e OrderApproved) AggregateType() string { return orderAggregate }
+func (e OrderApproved) AggregateID() uint     { return e.OrderID }
+
+// OrderCancelled заказ отменен
+type OrderCancelled struct {
+	OrderID  uint `json:"order_id"`
+	ClientID uint `json:"client_id"`
+}
+
+func (e OrderCancelled) EventType() string     { return OrderCancelledEvent }
+func (e OrderCancelled) AggregateType() string { return orderAggregate }
+func (e OrderCancelled) AggregateID() uint     { return e.OrderID }
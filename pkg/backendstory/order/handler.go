@@ -1,8 +1,8 @@
 package order
 
 import (
+	"context"
 	"database/sql"
-	"encoding/json"
 	"net/http"
 	"strconv"
 
@@ -22,6 +22,7 @@ type OrderHandler struct {
 	orderService     OrderService
 	personService    person.PersonService
 	enumValueService enumvalue.EnumValueService
+	crud             *core.CrudHandler[Order, OrderDTO]
 }
 
 func NewOrderHandler(
@@ -29,12 +30,43 @@ func NewOrderHandler(
 	personService person.PersonService,
 	enumValueService enumvalue.EnumValueService,
 ) *OrderHandler {
-	return &OrderHandler{
-		validate:         validator.New(),
+	validate := core.DefaultValidator()
+	h := &OrderHandler{
+		validate:         validate,
 		orderService:     orderService,
 		personService:    personService,
 		enumValueService: enumValueService,
 	}
+	h.crud = core.NewCrudHandler(orderHandlerCode, validate, orderService, h.toOrderDTO).
+		WithListMapper(h.toOrderDTOs)
+	return h
+}
+
+// toOrderDTO преобразует заказ в DTO вместе со статусом
+func (h *OrderHandler) toOrderDTO(ctx context.Context, order Order) (OrderDTO, error) {
+	orderStatus, err := h.enumValueService.GetByID(ctx, order.StatusID)
+	if err != nil {
+		return OrderDTO{}, err
+	}
+	return ToOrderDTO(order, enumvalue.ToEnumValueDTO(orderStatus)), nil
+}
+
+// toOrderDTOs преобразует список заказов в DTO, получая статусы одним запросом
+func (h *OrderHandler) toOrderDTOs(ctx context.Context, orders []Order) ([]OrderDTO, error) {
+	statusIDs := make([]uint, 0, len(orders))
+	for _, order := range orders {
+		statusIDs = append(statusIDs, order.StatusID)
+	}
+	statuses, err := h.enumValueService.GetMapByIDs(ctx, statusIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]OrderDTO, 0, len(orders))
+	for _, order := range orders {
+		dtos = append(dtos, ToOrderDTO(order, enumvalue.ToEnumValueDTO(statuses[order.StatusID])))
+	}
+	return dtos, nil
 }
 
 // Create создает новый заказ
@@ -58,12 +90,12 @@ func (h *OrderHandler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var req OrderCreateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := core.DecodeJSON(r, &req); err != nil {
 		core.HandleError(w, r, core.NewTechnicalError(err, orderHandlerCode, err.Error()))
 		return
 	}
 	if err := h.validate.Struct(req); err != nil {
-		details := core.CollectValidationDetails(err)
+		details := core.CollectValidationDetails(r.Context(), err)
 		core.HandleValidationError(w, r, core.NewLogicalError(err, orderHandlerCode, err.Error()), details)
 		return
 	}
@@ -76,26 +108,26 @@ func (h *OrderHandler) Create(w http.ResponseWriter, r *http.Request) {
 		core.HandleError(w, r, err)
 		return
 	}
-	orderStatus, err := h.enumValueService.GetByID(ctx, order.StatusID)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
 
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(ToOrderDTO(order, enumvalue.ToEnumValueDTO(orderStatus)))
+	h.crud.WriteEntity(w, r, http.StatusCreated, order)
 }
 
 // ChangeStatus изменяет статус заказа
 // @Summary Изменить статус заказа
-// @Description Изменяет статус заказа и назначает менеджера (если не назначен)
+// @Description Изменяет статус заказа и назначает менеджера (если не назначен).
+// @Description Подтверждение (Approved) может быть частичным: элементы, которые нельзя подтвердить по бизнес-причине
+// @Description (например, не хватает товара на складе), отменяются, а заказ подтверждается с остальными элементами.
+// @Description Итоговый состав заказа нужно перечитать по элементам заказа. Если не подтвержден ни один элемент,
+// @Description возвращается ошибка первого из них, и заказ не изменяется
 // @Tags Orders
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "ID заказа"
 // @Param status path string true "Новый статус заказа (Approved, Cancelled)"
+// @Param If-Match header string false "Версия записи из ETag"
 // @Success 200 {object} OrderDTO "Обновленный заказ"
+// @Header 200 {string} ETag "Версия записи"
 // @Failure 400 {object} core.ErrorResponse "Неверный запрос или статус"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
@@ -109,18 +141,18 @@ func (h *OrderHandler) ChangeStatus(w http.ResponseWriter, r *http.Request) {
 
 	reqID := r.PathValue("id")
 	if reqID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, orderHandlerCode, "Отсутствует ИД параметр"))
+		core.HandleError(w, r, core.NewLogicalError(nil, core.PathParamMissingCode, "Отсутствует параметр %s", "id"))
 		return
 	}
 	id, err := strconv.Atoi(reqID)
 	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, orderHandlerCode, "ИД параметр должен быть числовым!"+err.Error()))
+		core.HandleError(w, r, core.NewLogicalError(err, core.PathParamInvalidCode, "Параметр %s должен быть числовым", "id"))
 		return
 	}
 
 	status := r.PathValue("status")
 	if status == "" {
-		core.HandleError(w, r, core.NewLogicalError(err, orderHandlerCode, "Параметр статуса пустой!"))
+		core.HandleError(w, r, core.NewLogicalError(nil, core.PathParamMissingCode, "Отсутствует параметр %s", "status"))
 		return
 	}
 
@@ -140,6 +172,10 @@ func (h *OrderHandler) ChangeStatus(w http.ResponseWriter, r *http.Request) {
 		core.HandleError(w, r, err)
 		return
 	}
+	if err := core.ApplyIfMatch(r, &order); err != nil {
+		core.HandleError(w, r, err)
+		return
+	}
 
 	order.ManagerID = sql.NullInt32{
 		Int32: int32(manager.ID),
@@ -151,15 +187,7 @@ func (h *OrderHandler) ChangeStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	orderStatus, err := h.enumValueService.GetByID(ctx, order.StatusID)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(
-		ToOrderDTO(order, enumvalue.ToEnumValueDTO(orderStatus)),
-	)
+	h.crud.WriteEntity(w, r, http.StatusOK, order)
 }
 
 // AddDetails добавить детали заказа
@@ -170,11 +198,14 @@ func (h *OrderHandler) ChangeStatus(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "ID заказа"
+// @Param If-Match header string false "Версия записи из ETag"
 // @Success 200 {object} OrderDTO "Обновленный заказ"
+// @Header 200 {string} ETag "Версия записи"
 // @Failure 400 {object} core.ErrorResponse "Неверный запрос"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
 // @Failure 404 {object} core.ErrorResponse "Заказ не найден"
+// @Failure 409 {object} core.ErrorResponse "Запись изменена другим пользователем"
 // @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
 // @Router /orders/add-details [post]
 // @Id addDetails
@@ -182,12 +213,12 @@ func (h *OrderHandler) AddDetails(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var req OrderUpdateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := core.DecodeJSON(r, &req); err != nil {
 		core.HandleError(w, r, core.NewTechnicalError(err, orderHandlerCode, err.Error()))
 		return
 	}
 	if err := h.validate.Struct(req); err != nil {
-		details := core.CollectValidationDetails(err)
+		details := core.CollectValidationDetails(r.Context(), err)
 		core.HandleValidationError(w, r, core.NewLogicalError(err, orderHandlerCode, err.Error()), details)
 		return
 	}
@@ -208,6 +239,10 @@ func (h *OrderHandler) AddDetails(w http.ResponseWriter, r *http.Request) {
 		core.HandleError(w, r, err)
 		return
 	}
+	if err := core.ApplyIfMatch(r, &order); err != nil {
+		core.HandleError(w, r, err)
+		return
+	}
 
 	order.Details = req.Details
 	order.ManagerID = sql.NullInt32{
@@ -220,16 +255,7 @@ func (h *OrderHandler) AddDetails(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	orderStatus, err := h.enumValueService.GetByID(ctx, order.StatusID)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(
-		ToOrderDTO(order, enumvalue.ToEnumValueDTO(orderStatus)),
-	)
+	h.crud.WriteEntity(w, r, http.StatusOK, order)
 }
 
 // Delete удаляет заказ
@@ -250,32 +276,31 @@ func (h *OrderHandler) AddDetails(w http.ResponseWriter, r *http.Request) {
 // @Router /orders/{id} [delete]
 // @Id deleteOrder
 func (h *OrderHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	reqID := r.PathValue("id")
-	if reqID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, orderHandlerCode, "Отсутствует ИД параметр"))
-		return
-	}
-	id, err := strconv.Atoi(reqID)
-	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, orderHandlerCode, "ИД параметр должен быть числовым!"+err.Error()))
-		return
-	}
-
-	order, err := h.orderService.GetByID(ctx, uint(id))
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	err = h.orderService.Delete(ctx, order)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
+	h.crud.Delete(w, r)
+}
 
-	w.WriteHeader(http.StatusNoContent)
+// GetAll возвращает все заказы
+// @Summary Получить все заказы
+// @Description Возвращает список всех заказов постранично, с фильтрацией и сортировкой из строки запроса
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param filter query string false "Условия через ';', например client_id==5;status=in=(InProgress,Approved)"
+// @Param sort query string false "Поля сортировки через ',', префикс '-' - по убыванию, например -created_at"
+// @Param limit query int false "Размер страницы" default(20)
+// @Param offset query int false "Смещение"
+// @Param after query string false "Курсор следующей страницы"
+// @Param before query string false "Курсор предыдущей страницы"
+// @Success 200 {object} core.Page[OrderDTO] "Список заказов"
+// @Failure 400 {object} core.ErrorResponse "Некорректные параметры фильтра"
+// @Failure 401 {object} core.ErrorResponse "Не авторизован"
+// @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
+// @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /orders [get]
+// @Id getOrderAll
+func (h *OrderHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	h.crud.GetAll(w, r)
 }
 
 // GetById возвращает заказ по ID
@@ -287,6 +312,7 @@ func (h *OrderHandler) Delete(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Param id path int true "ID заказа"
 // @Success 200 {object} OrderDTO "Заказ"
+// @Header 200 {string} ETag "Версия записи"
 // @Failure 400 {object} core.ErrorResponse "Неверный ID заказа"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
@@ -295,35 +321,7 @@ func (h *OrderHandler) Delete(w http.ResponseWriter, r *http.Request) {
 // @Router /orders/{id} [get]
 // @Id getOrderById
 func (h *OrderHandler) GetById(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	reqID := r.PathValue("id")
-	if reqID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, orderHandlerCode, "Отсутствует ИД параметр"))
-		return
-	}
-	id, err := strconv.Atoi(reqID)
-	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, orderHandlerCode, "ИД параметр должен быть числовым!"+err.Error()))
-		return
-	}
-
-	order, err := h.orderService.GetByID(ctx, uint(id))
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	orderStatus, err := h.enumValueService.GetByID(ctx, order.StatusID)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(
-		ToOrderDTO(order, enumvalue.ToEnumValueDTO(orderStatus)),
-	)
+	h.crud.GetById(w, r)
 }
 
 // GetWithSearchCriteria возвращает список заказов по критериям поиска
@@ -334,7 +332,7 @@ func (h *OrderHandler) GetById(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Security BearerAuth
 // @Param request body core.SearchCriteria true "Критерии поиска"
-// @Success 200 {array} OrderDTO "Список заказов"
+// @Success 200 {object} core.Page[OrderDTO] "Список заказов"
 // @Failure 400 {object} core.ErrorResponse "Неверные критерии поиска"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
@@ -343,37 +341,7 @@ func (h *OrderHandler) GetById(w http.ResponseWriter, r *http.Request) {
 // @Router /orders/search [post]
 // @Id searchOrders
 func (h *OrderHandler) GetWithSearchCriteria(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	var req core.SearchCriteria
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		core.HandleError(w, r, core.NewTechnicalError(err, orderHandlerCode, err.Error()))
-		return
-	}
-	if err := h.validate.Struct(req); err != nil {
-		details := core.CollectValidationDetails(err)
-		core.HandleValidationError(w, r, core.NewLogicalError(err, orderHandlerCode, err.Error()), details)
-		return
-	}
-
-	orders, err := h.orderService.GetWithSearchCriteria(ctx, req)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	dtos := make([]OrderDTO, 0, len(orders))
-	for _, order := range orders {
-		orderStatus, err := h.enumValueService.GetByID(ctx, order.StatusID)
-		if err != nil {
-			core.HandleError(w, r, err)
-			return
-		}
-		dtos = append(dtos, ToOrderDTO(order, enumvalue.ToEnumValueDTO(orderStatus)))
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(dtos)
+	h.crud.GetWithSearchCriteria(w, r)
 }
 
 // GetByStatus возвращает заказы по статусу
@@ -396,7 +364,7 @@ func (h *OrderHandler) GetByStatus(w http.ResponseWriter, r *http.Request) {
 
 	status := r.PathValue("status")
 	if status == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, orderHandlerCode, "Отсутствует параметр статус"))
+		core.HandleError(w, r, core.NewLogicalError(nil, core.PathParamMissingCode, "Отсутствует параметр %s", "status"))
 		return
 	}
 
@@ -406,18 +374,7 @@ func (h *OrderHandler) GetByStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	dtos := make([]OrderDTO, 0, len(orders))
-	for _, order := range orders {
-		orderStatus, err := h.enumValueService.GetByID(ctx, order.StatusID)
-		if err != nil {
-			core.HandleError(w, r, err)
-			return
-		}
-		dtos = append(dtos, ToOrderDTO(order, enumvalue.ToEnumValueDTO(orderStatus)))
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(dtos)
+	h.crud.WriteList(w, r, orders)
 }
 
 // GetByClientID возвращает заказы по ID клиента
@@ -440,12 +397,12 @@ func (h *OrderHandler) GetByClientID(w http.ResponseWriter, r *http.Request) {
 
 	reqClientID := r.PathValue("client_id")
 	if reqClientID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, orderHandlerCode, "Отсутствует ИД параметр"))
+		core.HandleError(w, r, core.NewLogicalError(nil, core.PathParamMissingCode, "Отсутствует параметр %s", "client_id"))
 		return
 	}
 	clientID, err := strconv.Atoi(reqClientID)
 	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, orderHandlerCode, "ИД параметр должен быть числовым!"+err.Error()))
+		core.HandleError(w, r, core.NewLogicalError(err, core.PathParamInvalidCode, "Параметр %s должен быть числовым", "client_id"))
 		return
 	}
 
@@ -455,18 +412,7 @@ func (h *OrderHandler) GetByClientID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	dtos := make([]OrderDTO, 0, len(orders))
-	for _, order := range orders {
-		orderStatus, err := h.enumValueService.GetByID(ctx, order.StatusID)
-		if err != nil {
-			core.HandleError(w, r, err)
-			return
-		}
-		dtos = append(dtos, ToOrderDTO(order, enumvalue.ToEnumValueDTO(orderStatus)))
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(dtos)
+	h.crud.WriteList(w, r, orders)
 }
 
 // GetByManagerID возвращает заказы по ID менеджера
@@ -489,12 +435,12 @@ func (h *OrderHandler) GetByManagerID(w http.ResponseWriter, r *http.Request) {
 
 	reqManagerID := r.PathValue("manager_id")
 	if reqManagerID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, orderHandlerCode, "Отсутствует ИД параметр"))
+		core.HandleError(w, r, core.NewLogicalError(nil, core.PathParamMissingCode, "Отсутствует параметр %s", "manager_id"))
 		return
 	}
 	managerID, err := strconv.Atoi(reqManagerID)
 	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, orderHandlerCode, "ИД параметр должен быть числовым!"+err.Error()))
+		core.HandleError(w, r, core.NewLogicalError(err, core.PathParamInvalidCode, "Параметр %s должен быть числовым", "manager_id"))
 		return
 	}
 
@@ -504,18 +450,7 @@ func (h *OrderHandler) GetByManagerID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	dtos := make([]OrderDTO, 0, len(orders))
-	for _, order := range orders {
-		orderStatus, err := h.enumValueService.GetByID(ctx, order.StatusID)
-		if err != nil {
-			core.HandleError(w, r, err)
-			return
-		}
-		dtos = append(dtos, ToOrderDTO(order, enumvalue.ToEnumValueDTO(orderStatus)))
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(dtos)
+	h.crud.WriteList(w, r, orders)
 }
 
 // GetByManagerIDAndStatus возвращает заказы по ID менеджера и статусу
@@ -539,17 +474,17 @@ func (h *OrderHandler) GetByManagerIDAndStatus(w http.ResponseWriter, r *http.Re
 
 	reqManagerID := r.PathValue("manager_id")
 	if reqManagerID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, orderHandlerCode, "Отсутствует ИД параметр"))
+		core.HandleError(w, r, core.NewLogicalError(nil, core.PathParamMissingCode, "Отсутствует параметр %s", "manager_id"))
 		return
 	}
 	managerID, err := strconv.Atoi(reqManagerID)
 	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, orderHandlerCode, "ИД параметр должен быть числовым!"+err.Error()))
+		core.HandleError(w, r, core.NewLogicalError(err, core.PathParamInvalidCode, "Параметр %s должен быть числовым", "manager_id"))
 		return
 	}
 	status := r.PathValue("status")
 	if status == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, orderHandlerCode, "Отсутствует параметр статус"))
+		core.HandleError(w, r, core.NewLogicalError(nil, core.PathParamMissingCode, "Отсутствует параметр %s", "status"))
 		return
 	}
 
@@ -559,16 +494,5 @@ func (h *OrderHandler) GetByManagerIDAndStatus(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	dtos := make([]OrderDTO, 0, len(orders))
-	for _, order := range orders {
-		orderStatus, err := h.enumValueService.GetByID(ctx, order.StatusID)
-		if err != nil {
-			core.HandleError(w, r, err)
-			return
-		}
-		dtos = append(dtos, ToOrderDTO(order, enumvalue.ToEnumValueDTO(orderStatus)))
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(dtos)
+	h.crud.WriteList(w, r, orders)
 }
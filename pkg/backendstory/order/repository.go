@@ -31,7 +31,7 @@ func (r *orderRepository) FindByStatusID(ctx context.Context, statusID uint) ([]
 	var orders []Order
 	if err := r.GetDB(ctx).Where("STATUSID = ?", statusID).Find(&orders).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, core.NewNotFoundError("Не существует заказов с данным статусом")
+			return nil, core.NewNotFoundError(core.OrderNotFoundCode, "Не существует заказов с данным статусом")
 		}
 		return nil, err
 	}
@@ -42,7 +42,7 @@ func (r *orderRepository) FindByClientID(ctx context.Context, clientID uint) ([]
 	var orders []Order
 	if err := r.GetDB(ctx).Where("CLIENTID = ?", clientID).Find(&orders).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, core.NewNotFoundError("Не существует заказов у данного клиента")
+			return nil, core.NewNotFoundError(core.OrderNotFoundCode, "Не существует заказов у данного клиента")
 		}
 		return nil, err
 	}
@@ -53,7 +53,7 @@ func (r *orderRepository) FindByManagerID(ctx context.Context, managerID uint) (
 	var orders []Order
 	if err := r.GetDB(ctx).Where("MANAGERID = ?", managerID).Find(&orders).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, core.NewNotFoundError("Не существует заказов у данного менеджера")
+			return nil, core.NewNotFoundError(core.OrderNotFoundCode, "Не существует заказов у данного менеджера")
 		}
 		return nil, err
 	}
@@ -64,7 +64,7 @@ func (r *orderRepository) FindByManagerIDAndStatusID(ctx context.Context, manage
 	var orders []Order
 	if err := r.GetDB(ctx).Where("MANAGERID = ? AND STATUSID = ?", managerID, statusID).Find(&orders).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, core.NewNotFoundError("Не существует заказов у данного менеджера")
+			return nil, core.NewNotFoundError(core.OrderNotFoundCode, "Не существует заказов у данного менеджера")
 		}
 		return nil, err
 	}
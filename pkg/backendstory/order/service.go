@@ -21,6 +21,8 @@ type OrderService interface {
 	Update(ctx context.Context, order Order) (Order, error)
 	Delete(ctx context.Context, order Order) error
 
+	// Approve подтверждает заказ. Подтверждение может быть частичным: элементы, которые нельзя
+	// подтвердить по бизнес-причине, отменяются, заказ подтверждается с остальными
 	Approve(ctx context.Context, order Order) (Order, error)
 	Cancel(ctx context.Context, order Order) (Order, error)
 	ChangeStatus(ctx context.Context, order Order, status string) (Order, error)
@@ -38,6 +40,7 @@ type orderService struct {
 	enumService      enum.EnumService
 	enumValueService enumvalue.EnumValueService
 	orderItemService orderitem.OrderItemService
+	eventPublisher   core.EventPublisher
 }
 
 func NewOrderService(
@@ -46,6 +49,7 @@ func NewOrderService(
 	enumService enum.EnumService,
 	enumValueService enumvalue.EnumValueService,
 	orderItemService orderitem.OrderItemService,
+	eventPublisher core.EventPublisher,
 ) *orderService {
 	return &orderService{
 		BaseServiceImpl:  *core.NewBaseServiceImpl(orderRepo),
@@ -54,6 +58,7 @@ func NewOrderService(
 		enumService:      enumService,
 		enumValueService: enumValueService,
 		orderItemService: orderItemService,
+		eventPublisher:   eventPublisher,
 	}
 }
 
@@ -81,7 +86,11 @@ func (s *orderService) Create(ctx context.Context, order Order, cartItemIDs []ui
 			}
 		}
 
-		return nil
+		return s.eventPublisher.Publish(ctx, OrderCreated{
+			OrderID:     order.ID,
+			ClientID:    order.ClientID,
+			CartItemIDs: cartItemIDs,
+		})
 	})
 	return newOrder, err
 }
@@ -101,7 +110,7 @@ func (s *orderService) ChangeStatus(ctx context.Context, order Order, status str
 		}
 		return order, nil
 	default:
-		return Order{}, core.NewLogicalError(nil, orderServiceCode, "Неизвестный статус заказа!")
+		return Order{}, core.NewLogicalError(nil, core.OrderStatusUnknownCode, "Неизвестный статус заказа '%s'", status)
 	}
 }
 
@@ -109,8 +118,11 @@ func (s *orderService) Approve(ctx context.Context, order Order) (Order, error)
 	var approvedOrder Order
 	err := s.txManager.Do(ctx, func(ctx context.Context) error {
 		currentOrderStatus, err := s.enumValueService.GetByID(ctx, order.StatusID)
+		if err != nil {
+			return err
+		}
 		if currentOrderStatus.Code == CancelledOrderStatus {
-			return core.NewLogicalError(nil, orderServiceCode, "Невозможно подтвердить отмененный заказ!")
+			return core.NewLogicalError(nil, core.OrderAlreadyCancelledCode, "Заказ с ИД %d уже отменен", order.ID)
 		}
 
 		orderItems, err := s.orderItemService.GetByOrderID(ctx, order.ID)
@@ -118,10 +130,8 @@ func (s *orderService) Approve(ctx context.Context, order Order) (Order, error)
 			return err
 		}
 
-		for _, orderItem := range orderItems {
-			if _, err := s.orderItemService.Approve(ctx, orderItem); err != nil {
-				return err
-			}
+		if err := s.approveItems(ctx, orderItems); err != nil {
+			return err
 		}
 
 		approvedStatus, err := s.enumValueService.GetByCodeAndEnumCode(ctx, ApprovedOrderStatus, OrderStatus)
@@ -130,21 +140,62 @@ func (s *orderService) Approve(ctx context.Context, order Order) (Order, error)
 		}
 
 		order.StatusID = approvedStatus.ID
-		order, err = s.orderRepo.Update(ctx, order)
+		// при повторе транзакции order должен сохранить прочитанную версию
+		updated, err := s.orderRepo.Update(ctx, order)
 		if err != nil {
 			return err
 		}
-		approvedOrder = order
+		approvedOrder = updated
 
-		return nil
+		return s.eventPublisher.Publish(ctx, OrderApproved{OrderID: updated.ID, ClientID: updated.ClientID})
 	})
 
 	return approvedOrder, err
 }
 
+// approveItems подтверждает элементы заказа. Элемент, который нельзя подтвердить по бизнес-причине
+// (например, не хватает товара), откатывается к точке сохранения и отменяется, остальные подтверждаются.
+// Если не подтвержден ни один элемент, возвращается первая ошибка
+func (s *orderService) approveItems(ctx context.Context, orderItems []orderitem.OrderItem) error {
+	var firstErr error
+	approved := 0
+	for _, orderItem := range orderItems {
+		_, err := s.orderItemService.Approve(ctx, orderItem)
+		if err == nil {
+			approved++
+			continue
+		}
+
+		var logicalErr *core.LogicalError
+		if !errors.As(err, &logicalErr) {
+			return err
+		}
+		core.LoggerFromContext(ctx).Warn("Order item cancelled on approve", "order_item_id", orderItem.ID, "err", err)
+		if firstErr == nil {
+			firstErr = err
+		}
+		if _, err := s.orderItemService.Cancel(ctx, orderItem); err != nil {
+			return err
+		}
+	}
+
+	if approved == 0 && firstErr != nil {
+		return firstErr
+	}
+	return nil
+}
+
 func (s *orderService) Cancel(ctx context.Context, order Order) (Order, error) {
 	var cancelledOrder Order
 	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		currentOrderStatus, err := s.enumValueService.GetByID(ctx, order.StatusID)
+		if err != nil {
+			return err
+		}
+		if currentOrderStatus.Code == CancelledOrderStatus {
+			return core.NewLogicalError(nil, core.OrderAlreadyCancelledCode, "Заказ с ИД %d уже отменен", order.ID)
+		}
+
 		orderItems, err := s.orderItemService.GetByOrderID(ctx, order.ID)
 		if err != nil {
 			return err
@@ -161,13 +212,13 @@ func (s *orderService) Cancel(ctx context.Context, order Order) (Order, error) {
 		}
 
 		order.StatusID = cancelled.ID
-		order, err = s.orderRepo.Update(ctx, order)
+		updated, err := s.orderRepo.Update(ctx, order)
 		if err != nil {
 			return err
 		}
-		cancelledOrder = order
+		cancelledOrder = updated
 
-		return nil
+		return s.eventPublisher.Publish(ctx, OrderCancelled{OrderID: updated.ID, ClientID: updated.ClientID})
 	})
 
 	return cancelledOrder, err
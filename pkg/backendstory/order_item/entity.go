@@ -27,3 +27,14 @@ func (OrderItem) TableName() string {
 func (OrderItem) LocalTableName() string {
 	return "Элемента заказа"
 }
+
+var orderItemSearchFields = core.NewSearchFields(
+	core.SearchField{Name: "status", Column: "STATUSID", Type: core.FieldEnum, EnumCode: OrderItemStatus},
+	core.SearchField{Name: "status_id", Column: "STATUSID", Type: core.FieldUint, Sortable: true},
+	core.SearchField{Name: "order_id", Column: "ORDERID", Type: core.FieldUint, Sortable: true},
+	core.SearchField{Name: "cart_item_id", Column: "CARTITEMID", Type: core.FieldUint, Sortable: true},
+)
+
+func (OrderItem) SearchFields() *core.SearchFields {
+	return orderItemSearchFields
+}
@@ -1,7 +1,7 @@
 package orderitem
 
 import (
-	"encoding/json"
+	"context"
 	"net/http"
 	"strconv"
 
@@ -18,17 +18,49 @@ type OrderItemHandler struct {
 	validate         *validator.Validate
 	orderItemService OrderItemService
 	enumValueService enumvalue.EnumValueService
+	crud             *core.CrudHandler[OrderItem, OrderItemDTO]
 }
 
 func NewOrderItemHandler(
 	orderItemService OrderItemService,
 	enumValueService enumvalue.EnumValueService,
 ) *OrderItemHandler {
-	return &OrderItemHandler{
-		validate:         validator.New(),
+	validate := core.DefaultValidator()
+	h := &OrderItemHandler{
+		validate:         validate,
 		orderItemService: orderItemService,
 		enumValueService: enumValueService,
 	}
+	h.crud = core.NewCrudHandler(orderItemHandlerCode, validate, orderItemService, h.toOrderItemDTO).
+		WithListMapper(h.toOrderItemDTOs)
+	return h
+}
+
+// toOrderItemDTO преобразует элемент заказа в DTO вместе со статусом
+func (h *OrderItemHandler) toOrderItemDTO(ctx context.Context, orderItem OrderItem) (OrderItemDTO, error) {
+	orderItemStatus, err := h.enumValueService.GetByID(ctx, orderItem.StatusID)
+	if err != nil {
+		return OrderItemDTO{}, err
+	}
+	return ToOrderItemDTO(orderItem, enumvalue.ToEnumValueDTO(orderItemStatus)), nil
+}
+
+// toOrderItemDTOs преобразует список элементов заказа в DTO, получая статусы одним запросом
+func (h *OrderItemHandler) toOrderItemDTOs(ctx context.Context, orderItems []OrderItem) ([]OrderItemDTO, error) {
+	statusIDs := make([]uint, 0, len(orderItems))
+	for _, orderItem := range orderItems {
+		statusIDs = append(statusIDs, orderItem.StatusID)
+	}
+	statuses, err := h.enumValueService.GetMapByIDs(ctx, statusIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]OrderItemDTO, 0, len(orderItems))
+	for _, orderItem := range orderItems {
+		dtos = append(dtos, ToOrderItemDTO(orderItem, enumvalue.ToEnumValueDTO(statuses[orderItem.StatusID])))
+	}
+	return dtos, nil
 }
 
 // Create создает новый элемент заказа
@@ -52,12 +84,12 @@ func (h *OrderItemHandler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var req OrderItemCreateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := core.DecodeJSON(r, &req); err != nil {
 		core.HandleError(w, r, core.NewTechnicalError(err, orderItemHandlerCode, err.Error()))
 		return
 	}
 	if err := h.validate.Struct(req); err != nil {
-		details := core.CollectValidationDetails(err)
+		details := core.CollectValidationDetails(r.Context(), err)
 		core.HandleValidationError(w, r, core.NewLogicalError(err, orderItemHandlerCode, err.Error()), details)
 		return
 	}
@@ -72,14 +104,7 @@ func (h *OrderItemHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	orderItemStatus, err := h.enumValueService.GetByID(ctx, orderItem.StatusID)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(ToOrderItemDTO(orderItem, enumvalue.ToEnumValueDTO(orderItemStatus)))
+	h.crud.WriteEntity(w, r, http.StatusCreated, orderItem)
 }
 
 // ChangeStatus изменяет статус элемента заказа
@@ -91,7 +116,9 @@ func (h *OrderItemHandler) Create(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Param id path int true "ID элемента заказа"
 // @Param status path string true "Новый статус элемента заказа (pending, ready_to_ship, shipped, delivered, cancelled)"
+// @Param If-Match header string false "Версия записи из ETag"
 // @Success 201 {object} OrderItemDTO "Обновленный элемент заказа"
+// @Header 201 {string} ETag "Версия записи"
 // @Failure 400 {object} core.ErrorResponse "Неверный запрос или статус"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
@@ -105,40 +132,37 @@ func (h *OrderItemHandler) ChangeStatus(w http.ResponseWriter, r *http.Request)
 
 	reqID := r.PathValue("id")
 	if reqID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, orderItemHandlerCode, "Отсутствует ИД параметр"))
+		core.HandleError(w, r, core.NewLogicalError(nil, core.PathParamMissingCode, "Отсутствует параметр %s", "id"))
 		return
 	}
 	id, err := strconv.Atoi(reqID)
 	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, orderItemHandlerCode, "ИД параметр должен быть числовым!"+err.Error()))
+		core.HandleError(w, r, core.NewLogicalError(err, core.PathParamInvalidCode, "Параметр %s должен быть числовым", "id"))
 		return
 	}
 	status := r.PathValue("status")
 	if status == "" {
-		core.HandleError(w, r, core.NewLogicalError(err, orderItemHandlerCode, "Параметр действия над элементом заказа пустой!"))
+		core.HandleError(w, r, core.NewLogicalError(nil, core.PathParamMissingCode, "Отсутствует параметр %s", "status"))
 		return
 	}
 
 	orderItem, err := h.orderItemService.GetByID(ctx, uint(id))
 	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(nil, orderItemHandlerCode, "Элемента заказа не существует"))
+		core.HandleError(w, r, err)
 		return
 	}
-
-	orderItem, err = h.orderItemService.ChangeStatus(ctx, orderItem, status)
-	if err != nil {
+	if err := core.ApplyIfMatch(r, &orderItem); err != nil {
 		core.HandleError(w, r, err)
 		return
 	}
 
-	orderItemStatus, err := h.enumValueService.GetByID(ctx, orderItem.StatusID)
+	orderItem, err = h.orderItemService.ChangeStatus(ctx, orderItem, status)
 	if err != nil {
 		core.HandleError(w, r, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(ToOrderItemDTO(orderItem, enumvalue.ToEnumValueDTO(orderItemStatus)))
+	h.crud.WriteEntity(w, r, http.StatusCreated, orderItem)
 }
 
 // Delete удаляет элемент заказа
@@ -159,32 +183,31 @@ func (h *OrderItemHandler) ChangeStatus(w http.ResponseWriter, r *http.Request)
 // @Router /order-items/{id} [delete]
 // @Id deleteOrderItem
 func (h *OrderItemHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	reqID := r.PathValue("id")
-	if reqID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, orderItemHandlerCode, "Отсутствует ИД параметр"))
-		return
-	}
-	id, err := strconv.Atoi(reqID)
-	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, orderItemHandlerCode, "ИД параметр должен быть числовым!"+err.Error()))
-		return
-	}
-
-	orderItem, err := h.orderItemService.GetByID(ctx, uint(id))
-	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(nil, orderItemHandlerCode, "Элемента заказа не существует"))
-		return
-	}
-
-	err = h.orderItemService.Delete(ctx, orderItem)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
+	h.crud.Delete(w, r)
+}
 
-	w.WriteHeader(http.StatusNoContent)
+// GetAll возвращает все элементы заказов
+// @Summary Получить все элементы заказов
+// @Description Возвращает список всех элементов заказов постранично, с фильтрацией и сортировкой из строки запроса
+// @Tags OrderItems
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param filter query string false "Условия через ';', например order_id==3;status==Approved"
+// @Param sort query string false "Поля сортировки через ',', префикс '-' - по убыванию, например -created_at"
+// @Param limit query int false "Размер страницы" default(20)
+// @Param offset query int false "Смещение"
+// @Param after query string false "Курсор следующей страницы"
+// @Param before query string false "Курсор предыдущей страницы"
+// @Success 200 {object} core.Page[OrderItemDTO] "Список элементов заказов"
+// @Failure 400 {object} core.ErrorResponse "Некорректные параметры фильтра"
+// @Failure 401 {object} core.ErrorResponse "Не авторизован"
+// @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
+// @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /order-items [get]
+// @Id getOrderItemAll
+func (h *OrderItemHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	h.crud.GetAll(w, r)
 }
 
 // GetById возвращает элемент заказа по ID
@@ -196,6 +219,7 @@ func (h *OrderItemHandler) Delete(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Param id path int true "ID элемента заказа"
 // @Success 200 {object} OrderItemDTO "Элемент заказа"
+// @Header 200 {string} ETag "Версия записи"
 // @Failure 400 {object} core.ErrorResponse "Неверный ID элемента заказа"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
@@ -204,33 +228,7 @@ func (h *OrderItemHandler) Delete(w http.ResponseWriter, r *http.Request) {
 // @Router /order-items/{id} [get]
 // @Id getOrderItemById
 func (h *OrderItemHandler) GetById(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	reqID := r.PathValue("id")
-	if reqID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, orderItemHandlerCode, "Отсутствует ИД параметр"))
-		return
-	}
-	id, err := strconv.Atoi(reqID)
-	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, orderItemHandlerCode, "ИД параметр должен быть числовым!"+err.Error()))
-		return
-	}
-
-	orderItem, err := h.orderItemService.GetByID(ctx, uint(id))
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	orderItemStatus, err := h.enumValueService.GetByID(ctx, orderItem.StatusID)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(ToOrderItemDTO(orderItem, enumvalue.ToEnumValueDTO(orderItemStatus)))
+	h.crud.GetById(w, r)
 }
 
 // GetWithSearchCriteria возвращает список элементов заказа по критериям поиска
@@ -241,7 +239,7 @@ func (h *OrderItemHandler) GetById(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Security BearerAuth
 // @Param request body core.SearchCriteria true "Критерии поиска"
-// @Success 200 {array} OrderItemDTO "Список элементов заказа"
+// @Success 200 {object} core.Page[OrderItemDTO] "Список элементов заказа"
 // @Failure 400 {object} core.ErrorResponse "Неверные критерии поиска"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
@@ -250,37 +248,7 @@ func (h *OrderItemHandler) GetById(w http.ResponseWriter, r *http.Request) {
 // @Router /order-items/search [post]
 // @Id searchOrderItems
 func (h *OrderItemHandler) GetWithSearchCriteria(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	var req core.SearchCriteria
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		core.HandleError(w, r, core.NewTechnicalError(err, orderItemHandlerCode, err.Error()))
-		return
-	}
-	if err := h.validate.Struct(req); err != nil {
-		details := core.CollectValidationDetails(err)
-		core.HandleValidationError(w, r, core.NewLogicalError(err, orderItemHandlerCode, err.Error()), details)
-		return
-	}
-
-	orderItems, err := h.orderItemService.GetWithSearchCriteria(ctx, req)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	dtos := make([]OrderItemDTO, 0, len(orderItems))
-	for _, orderItem := range orderItems {
-		orderItemStatus, err := h.enumValueService.GetByID(ctx, orderItem.StatusID)
-		if err != nil {
-			core.HandleError(w, r, err)
-			return
-		}
-		dtos = append(dtos, ToOrderItemDTO(orderItem, enumvalue.ToEnumValueDTO(orderItemStatus)))
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(dtos)
+	h.crud.GetWithSearchCriteria(w, r)
 }
 
 // GetByOrderID возвращает элементы заказа по ID заказа
@@ -303,12 +271,12 @@ func (h *OrderItemHandler) GetByOrderID(w http.ResponseWriter, r *http.Request)
 
 	reqOrderID := r.PathValue("order_id")
 	if reqOrderID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, orderItemHandlerCode, "Отсутствует ИД параметр"))
+		core.HandleError(w, r, core.NewLogicalError(nil, core.PathParamMissingCode, "Отсутствует параметр %s", "order_id"))
 		return
 	}
 	orderID, err := strconv.Atoi(reqOrderID)
 	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, orderItemHandlerCode, "ИД параметр должен быть числовым!"+err.Error()))
+		core.HandleError(w, r, core.NewLogicalError(err, core.PathParamInvalidCode, "Параметр %s должен быть числовым", "order_id"))
 		return
 	}
 
@@ -318,16 +286,5 @@ func (h *OrderItemHandler) GetByOrderID(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	orderItemDTOs := make([]OrderItemDTO, 0, len(orderItems))
-	for _, orderItem := range orderItems {
-		orderItemStatus, err := h.enumValueService.GetByID(ctx, orderItem.StatusID)
-		if err != nil {
-			core.HandleError(w, r, err)
-			return
-		}
-		orderItemDTOs = append(orderItemDTOs, ToOrderItemDTO(orderItem, enumvalue.ToEnumValueDTO(orderItemStatus)))
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(orderItemDTOs)
+	h.crud.WriteList(w, r, orderItems)
 }
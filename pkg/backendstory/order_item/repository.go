@@ -28,7 +28,7 @@ func (r *orderItemRepository) FindByOrderID(ctx context.Context, orderID uint) (
 	var orderItems []OrderItem
 	if err := r.GetDB(ctx).Where("ORDERID = ?", orderID).Find(&orderItems).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, core.NewNotFoundError("Не существует заказов с данным статусом")
+			return nil, core.NewNotFoundError(core.OrderItemNotFoundCode, "Не существует заказов с данным статусом")
 		}
 		return nil, err
 	}
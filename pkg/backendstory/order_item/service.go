@@ -3,7 +3,6 @@ package orderitem
 import (
 	"context"
 	"errors"
-	"fmt"
 	"slices"
 
 	cartitem "github.com/ActuallyHello/backendstory/pkg/backendstory/cart_item"
@@ -14,7 +13,7 @@ import (
 )
 
 const (
-	orderItemServiceCode = "ORDER_SERVICE"
+	orderItemServiceCode = "ORDER_ITEM_SERVICE"
 )
 
 type OrderItemService interface {
@@ -89,19 +88,22 @@ func (s *orderItemService) ChangeStatus(ctx context.Context, orderItem OrderItem
 	case CancelledOrderItemStatus:
 		return s.Cancel(ctx, orderItem)
 	default:
-		return OrderItem{}, core.NewLogicalError(nil, orderItemServiceCode, "Невозможно изменить статус заказа на "+status)
+		return OrderItem{}, core.NewLogicalError(nil, core.OrderItemStatusUnknownCode, "Неизвестный статус элемента заказа '%s'", status)
 	}
 }
 
+// Approve подтверждает элемент заказа и списывает товар со склада. Внутри транзакции заказа
+// выполняется в точке сохранения, поэтому при ошибке откатываются только изменения элемента
 func (s *orderItemService) Approve(ctx context.Context, orderItem OrderItem) (OrderItem, error) {
 	var approvedOrderItem OrderItem
-	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+	txSettings := core.DefaultGormTxSettings(core.SetPropagationOpt(core.TxNested))
+	err := s.txManager.DoWithSettings(ctx, txSettings, func(ctx context.Context) error {
 		currentStatus, err := s.enumValueService.GetByID(ctx, orderItem.StatusID)
 		if err != nil {
 			return err
 		}
 		if slices.Contains([]string{CancelledOrderItemStatus}, currentStatus.Code) {
-			return core.NewLogicalError(nil, orderItemServiceCode, fmt.Sprintf("Невозможно подтверджить заказ! Элемент заказа в статусе '%s'", currentStatus.Label))
+			return core.NewLogicalError(nil, core.OrderItemAlreadyCancelledCode, "Элемент заказа с ИД %d уже отменен", orderItem.ID)
 		}
 
 		approvedStatus, err := s.enumValueService.GetByCodeAndEnumCode(ctx, ApprovedOrderItemStatus, OrderItemStatus)
@@ -120,7 +122,7 @@ func (s *orderItemService) Approve(ctx context.Context, orderItem OrderItem) (Or
 		}
 
 		if product.Quantity < cartItem.Quantity {
-			return core.NewLogicalError(nil, orderItemServiceCode, fmt.Sprintf("Невозможно подтвердить элемент заказа! Текущее количество товара %s: %d", product.Label, product.Quantity))
+			return core.NewLogicalError(nil, core.ProductOutOfStockCode, "Недостаточно товара %s на складе, доступно: %d", product.Label, product.Quantity)
 		}
 
 		product.Quantity = product.Quantity - cartItem.Quantity
@@ -130,11 +132,11 @@ func (s *orderItemService) Approve(ctx context.Context, orderItem OrderItem) (Or
 		}
 
 		orderItem.StatusID = approvedStatus.ID
-		orderItem, err = s.Update(ctx, orderItem)
+		// при повторе транзакции orderItem должен сохранить прочитанную версию
+		approvedOrderItem, err = s.Update(ctx, orderItem)
 		if err != nil {
 			return err
 		}
-		approvedOrderItem = orderItem
 
 		return nil
 	})
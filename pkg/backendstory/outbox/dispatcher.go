@@ -0,0 +1,182 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ActuallyHello/backendstory/pkg/core"
+)
+
+const (
+	// maxRetryDelay предел задержки между повторными попытками доставки
+	maxRetryDelay = time.Hour
+	// claimTimeout время, на которое выбранные события скрываются от других экземпляров.
+	// Если экземпляр остановится во время доставки, события будут доставлены повторно по его истечении
+	claimTimeout = 15 * time.Minute
+)
+
+// Dispatcher периодически доставляет накопленные в OUTBOX события всем получателям.
+// Событие помечается доставленным только после успешной доставки каждому из них,
+// поэтому при сбоях получатели могут получить событие повторно
+type Dispatcher struct {
+	txManager   core.TxManager
+	outboxRepo  OutboxRepository
+	sinks       []Sink
+	interval    time.Duration
+	batchSize   int
+	maxAttempts uint
+}
+
+func NewDispatcher(
+	txManager core.TxManager,
+	outboxRepo OutboxRepository,
+	sinks []Sink,
+	interval time.Duration,
+	batchSize int,
+	maxAttempts uint,
+) *Dispatcher {
+	return &Dispatcher{
+		txManager:   txManager,
+		outboxRepo:  outboxRepo,
+		sinks:       sinks,
+		interval:    interval,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Run доставляет события с заданным интервалом до отмены контекста
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// обрабатываем пачки подряд, пока очередь не опустеет
+			for {
+				processed, err := d.DispatchPending(ctx)
+				if err != nil {
+					if !errors.Is(err, context.Canceled) {
+						slog.Error("Failed to dispatch outbox events", "err", err)
+					}
+					break
+				}
+				if processed < d.batchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// DispatchPending доставляет одну пачку событий и возвращает количество обработанных.
+// События резервируются в короткой транзакции, доставляются вне её, а результат доставки
+// записывается второй транзакцией. Так блокировки строк не удерживаются на время обращения
+// к получателям, а повтор транзакции после взаимной блокировки не доставляет события повторно
+func (d *Dispatcher) DispatchPending(ctx context.Context) (int, error) {
+	messages, err := d.claim(ctx)
+	if err != nil || len(messages) == 0 {
+		return 0, err
+	}
+
+	for i := range messages {
+		d.deliver(ctx, &messages[i])
+	}
+
+	err = d.txManager.Do(ctx, func(ctx context.Context) error {
+		for _, message := range messages {
+			if _, err := d.outboxRepo.Update(ctx, message); err != nil {
+				var conflictErr *core.ConflictError
+				if errors.As(err, &conflictErr) {
+					// резерв истек, и событие уже обработал другой экземпляр
+					slog.Warn("Outbox event was claimed by another dispatcher", "id", message.ID)
+					continue
+				}
+				return err
+			}
+		}
+		return nil
+	})
+	return len(messages), err
+}
+
+// claim выбирает события, ожидающие доставки, и переносит их следующую попытку на claimTimeout вперед,
+// чтобы другие экземпляры приложения не взяли их, пока идет доставка
+func (d *Dispatcher) claim(ctx context.Context) ([]OutboxMessage, error) {
+	var claimed []OutboxMessage
+	err := d.txManager.Do(ctx, func(ctx context.Context) error {
+		claimed = nil
+		now := time.Now()
+		messages, err := d.outboxRepo.FindDue(ctx, now, d.batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, message := range messages {
+			message.NextAttemptAt = now.Add(claimTimeout)
+			message, err := d.outboxRepo.Update(ctx, message)
+			if err != nil {
+				return err
+			}
+			claimed = append(claimed, message)
+		}
+		return nil
+	})
+	return claimed, err
+}
+
+// deliver отправляет событие всем получателям и обновляет состояние доставки
+func (d *Dispatcher) deliver(ctx context.Context, message *OutboxMessage) {
+	envelope := ToEnvelope(*message)
+
+	var deliveryErr error
+	for _, sink := range d.sinks {
+		if err := sink.Deliver(ctx, envelope); err != nil {
+			deliveryErr = errors.Join(deliveryErr, fmt.Errorf("%s: %w", sink.Name(), err))
+		}
+	}
+
+	message.Attempts++
+	if deliveryErr == nil {
+		message.Status = SentStatus
+		message.SentAt = sql.NullTime{Time: time.Now(), Valid: true}
+		message.LastError = ""
+		return
+	}
+
+	message.LastError = deliveryErr.Error()
+	if message.Attempts >= d.maxAttempts {
+		message.Status = DeadStatus
+		slog.Error("Outbox event was not delivered and moved to dead status",
+			"id", message.ID,
+			"type", message.EventType,
+			"attempts", message.Attempts,
+			"err", deliveryErr,
+		)
+		return
+	}
+	message.NextAttemptAt = time.Now().Add(d.retryDelay(message.Attempts))
+	slog.Warn("Outbox event delivery failed, will retry",
+		"id", message.ID,
+		"type", message.EventType,
+		"attempts", message.Attempts,
+		"next_attempt_at", message.NextAttemptAt,
+		"err", deliveryErr,
+	)
+}
+
+// retryDelay экспоненциальная задержка от интервала опроса: interval * 2^(attempts-1)
+func (d *Dispatcher) retryDelay(attempts uint) time.Duration {
+	delay := d.interval
+	for i := uint(1); i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
@@ -0,0 +1,236 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ActuallyHello/backendstory/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryOutboxRepository хранилище событий в памяти
+type memoryOutboxRepository struct {
+	core.BaseRepository[OutboxMessage]
+
+	rows map[uint]OutboxMessage
+}
+
+func newMemoryOutboxRepository(messages ...OutboxMessage) *memoryOutboxRepository {
+	repo := &memoryOutboxRepository{rows: make(map[uint]OutboxMessage)}
+	for i, message := range messages {
+		message.ID = uint(i + 1)
+		message.Version = 1
+		repo.rows[message.ID] = message
+	}
+	return repo
+}
+
+func (r *memoryOutboxRepository) FindDue(_ context.Context, now time.Time, limit int) ([]OutboxMessage, error) {
+	var due []OutboxMessage
+	for _, id := range slices.Sorted(maps.Keys(r.rows)) {
+		message := r.rows[id]
+		if message.Status == PendingStatus && !message.NextAttemptAt.After(now) && len(due) < limit {
+			due = append(due, message)
+		}
+	}
+	return due, nil
+}
+
+func (r *memoryOutboxRepository) Update(_ context.Context, message OutboxMessage) (OutboxMessage, error) {
+	if r.rows[message.ID].Version != message.Version {
+		return message, core.NewConflictError(nil, core.RecordVersionConflictCode, "version conflict")
+	}
+	message.Version++
+	r.rows[message.ID] = message
+	return message, nil
+}
+
+// fakeTxManager выполняет функцию над хранилищем в памяти. Первые deadlocks попыток каждой
+// транзакции завершаются взаимной блокировкой: изменения откатываются, и функция выполняется
+// повторно, как это делает TxManager
+type fakeTxManager struct {
+	repo      *memoryOutboxRepository
+	deadlocks int
+	active    bool
+}
+
+func (m *fakeTxManager) Do(ctx context.Context, f func(context.Context) error) error {
+	return m.DoWithSettings(ctx, core.DefaultGormTxSettings(), f)
+}
+
+func (m *fakeTxManager) DoWithSettings(ctx context.Context, _ core.TxSettings, f func(context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		snapshot := maps.Clone(m.repo.rows)
+		m.active = true
+		err := f(ctx)
+		m.active = false
+		if err == nil && attempt <= m.deadlocks {
+			m.repo.rows = snapshot
+			continue
+		}
+		if err != nil {
+			m.repo.rows = snapshot
+		}
+		return err
+	}
+}
+
+// txCheckSink проверяет, что доставка выполняется вне транзакции
+type txCheckSink struct {
+	Sink
+	t         *testing.T
+	txManager *fakeTxManager
+}
+
+func (s *txCheckSink) Deliver(ctx context.Context, envelope Envelope) error {
+	assert.False(s.t, s.txManager.active, "event %d delivered inside transaction", envelope.ID)
+	return s.Sink.Deliver(ctx, envelope)
+}
+
+type failingSink struct{}
+
+func (failingSink) Name() string {
+	return "failing"
+}
+
+func (failingSink) Deliver(context.Context, Envelope) error {
+	return errors.New("receiver is down")
+}
+
+func pendingMessage(eventType string) OutboxMessage {
+	return OutboxMessage{
+		EventType:     eventType,
+		AggregateType: "order",
+		AggregateID:   1,
+		Payload:       `{"id":1}`,
+		Status:        PendingStatus,
+		NextAttemptAt: time.Now().Add(-time.Second),
+	}
+}
+
+func receivedEvents(sink *ChannelSink) []Envelope {
+	var events []Envelope
+	for {
+		select {
+		case envelope := <-sink.Events():
+			events = append(events, envelope)
+		default:
+			return events
+		}
+	}
+}
+
+func TestDispatcherDeliversPendingEvents(t *testing.T) {
+	repo := newMemoryOutboxRepository(pendingMessage("order.created"), pendingMessage("order.approved"))
+	txManager := &fakeTxManager{repo: repo}
+	sink := NewChannelSink(10)
+	dispatcher := NewDispatcher(txManager, repo, []Sink{&txCheckSink{Sink: sink, t: t, txManager: txManager}}, time.Second, 10, 3)
+
+	processed, err := dispatcher.DispatchPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, processed)
+
+	events := receivedEvents(sink)
+	require.Len(t, events, 2)
+	assert.Equal(t, "order.created", events[0].Type)
+	assert.Equal(t, "order.approved", events[1].Type)
+	assert.JSONEq(t, `{"id":1}`, string(events[0].Payload))
+
+	for _, message := range repo.rows {
+		assert.Equal(t, SentStatus, message.Status)
+		assert.Equal(t, uint(1), message.Attempts)
+		assert.True(t, message.SentAt.Valid)
+	}
+
+	processed, err = dispatcher.DispatchPending(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, processed)
+	assert.Empty(t, receivedEvents(sink))
+}
+
+func TestDispatcherDoesNotRedeliverOnTransactionRetry(t *testing.T) {
+	repo := newMemoryOutboxRepository(pendingMessage("order.created"), pendingMessage("order.approved"))
+	// и резервирование, и запись результата откатываются взаимной блокировкой и выполняются повторно
+	txManager := &fakeTxManager{repo: repo, deadlocks: 1}
+	sink := NewChannelSink(10)
+	dispatcher := NewDispatcher(txManager, repo, []Sink{sink}, time.Second, 10, 3)
+
+	processed, err := dispatcher.DispatchPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, processed)
+	assert.Len(t, receivedEvents(sink), 2)
+}
+
+func TestDispatcherHidesClaimedEvents(t *testing.T) {
+	repo := newMemoryOutboxRepository(pendingMessage("order.created"))
+	txManager := &fakeTxManager{repo: repo}
+	var dueDuringDelivery []OutboxMessage
+	sink := sinkFunc(func(ctx context.Context, _ Envelope) error {
+		// другой экземпляр опрашивает очередь, пока идет доставка
+		var err error
+		dueDuringDelivery, err = repo.FindDue(ctx, time.Now(), 10)
+		return err
+	})
+	dispatcher := NewDispatcher(txManager, repo, []Sink{sink}, time.Second, 10, 3)
+
+	_, err := dispatcher.DispatchPending(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, dueDuringDelivery)
+	assert.Equal(t, SentStatus, repo.rows[1].Status)
+}
+
+func TestDispatcherSchedulesRetryAndGivesUp(t *testing.T) {
+	repo := newMemoryOutboxRepository(pendingMessage("order.created"))
+	txManager := &fakeTxManager{repo: repo}
+	sink := NewChannelSink(10)
+	interval := time.Second
+	dispatcher := NewDispatcher(txManager, repo, []Sink{sink, failingSink{}}, interval, 10, 2)
+
+	before := time.Now()
+	_, err := dispatcher.DispatchPending(context.Background())
+	require.NoError(t, err)
+
+	message := repo.rows[1]
+	assert.Equal(t, PendingStatus, message.Status)
+	assert.Equal(t, uint(1), message.Attempts)
+	assert.Contains(t, message.LastError, "failing: receiver is down")
+	assert.WithinDuration(t, before.Add(interval), message.NextAttemptAt, 500*time.Millisecond)
+	// исправный получатель тоже получит событие повторно, поэтому ID постоянен
+	require.Len(t, receivedEvents(sink), 1)
+
+	message.NextAttemptAt = time.Now().Add(-time.Second)
+	repo.rows[1] = message
+	_, err = dispatcher.DispatchPending(context.Background())
+	require.NoError(t, err)
+
+	message = repo.rows[1]
+	assert.Equal(t, DeadStatus, message.Status)
+	assert.Equal(t, uint(2), message.Attempts)
+	events := receivedEvents(sink)
+	require.Len(t, events, 1)
+	assert.Equal(t, uint(1), events[0].ID)
+}
+
+func TestDispatcherRetryDelay(t *testing.T) {
+	dispatcher := NewDispatcher(nil, nil, nil, time.Minute, 10, 10)
+
+	assert.Equal(t, time.Minute, dispatcher.retryDelay(1))
+	assert.Equal(t, 2*time.Minute, dispatcher.retryDelay(2))
+	assert.Equal(t, 8*time.Minute, dispatcher.retryDelay(4))
+	assert.Equal(t, maxRetryDelay, dispatcher.retryDelay(20))
+}
+
+type sinkFunc func(ctx context.Context, envelope Envelope) error
+
+func (f sinkFunc) Name() string {
+	return "func"
+}
+
+func (f sinkFunc) Deliver(ctx context.Context, envelope Envelope) error {
+	return f(ctx, envelope)
+}
@@ -0,0 +1,54 @@
+package outbox
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/ActuallyHello/backendstory/pkg/core"
+)
+
+const (
+	// PendingStatus событие ожидает доставки
+	PendingStatus = "pending"
+	// SentStatus событие доставлено всем получателям
+	SentStatus = "sent"
+	// DeadStatus событие не доставлено за отведенное число попыток
+	DeadStatus = "dead"
+)
+
+type OutboxMessage struct {
+	core.Base
+
+	EventType     string       `gorm:"column:EVENTTYPE"`
+	AggregateType string       `gorm:"column:AGGREGATETYPE"`
+	AggregateID   uint         `gorm:"column:AGGREGATEID"`
+	Payload       string       `gorm:"column:PAYLOAD"`
+	Status        string       `gorm:"column:STATUS"`
+	Attempts      uint         `gorm:"column:ATTEMPTS"`
+	NextAttemptAt time.Time    `gorm:"column:NEXTATTEMPTAT"`
+	SentAt        sql.NullTime `gorm:"column:SENTAT"`
+	LastError     string       `gorm:"column:LASTERROR"`
+}
+
+func (OutboxMessage) TableName() string {
+	return "OUTBOX"
+}
+
+func (OutboxMessage) LocalTableName() string {
+	return "Событие"
+}
+
+// Unaudited служебная таблица доставки событий не попадает в журнал аудита
+func (OutboxMessage) Unaudited() {}
+
+var outboxMessageSearchFields = core.NewSearchFields(
+	core.SearchField{Name: "event_type", Column: "EVENTTYPE", Type: core.FieldString, Sortable: true},
+	core.SearchField{Name: "aggregate_type", Column: "AGGREGATETYPE", Type: core.FieldString, Sortable: true},
+	core.SearchField{Name: "aggregate_id", Column: "AGGREGATEID", Type: core.FieldUint, Sortable: true},
+	core.SearchField{Name: "status", Column: "STATUS", Type: core.FieldString, Sortable: true},
+	core.SearchField{Name: "attempts", Column: "ATTEMPTS", Type: core.FieldUint, Sortable: true},
+)
+
+func (OutboxMessage) SearchFields() *core.SearchFields {
+	return outboxMessageSearchFields
+}
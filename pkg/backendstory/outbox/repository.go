@@ -0,0 +1,42 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/ActuallyHello/backendstory/pkg/core"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository interface {
+	core.BaseRepository[OutboxMessage]
+
+	FindDue(ctx context.Context, now time.Time, limit int) ([]OutboxMessage, error)
+}
+
+type outboxRepository struct {
+	core.BaseRepositoryImpl[OutboxMessage]
+}
+
+func NewOutboxRepository(db *gorm.DB) *outboxRepository {
+	return &outboxRepository{
+		BaseRepositoryImpl: *core.NewBaseRepositoryImpl[OutboxMessage](db),
+	}
+}
+
+// FindDue ищет события, ожидающие доставки, и блокирует их до конца транзакции.
+// Заблокированные другим экземпляром приложения события пропускаются
+func (r *outboxRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]OutboxMessage, error) {
+	var messages []OutboxMessage
+	err := r.GetDB(ctx).
+		Where("STATUS = ? AND NEXTATTEMPTAT <= ?", PendingStatus, now).
+		Order("ID").
+		Limit(limit).
+		Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate, Options: clause.LockingOptionsSkipLocked}).
+		Find(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
@@ -0,0 +1,61 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ActuallyHello/backendstory/pkg/core"
+)
+
+const (
+	outboxServiceCode = "OUTBOX_SERVICE"
+)
+
+type OutboxService interface {
+	core.BaseService[OutboxMessage]
+	core.EventPublisher
+}
+
+type outboxService struct {
+	core.BaseServiceImpl[OutboxMessage]
+	outboxRepo OutboxRepository
+}
+
+func NewOutboxService(
+	outboxRepo OutboxRepository,
+) *outboxService {
+	return &outboxService{
+		BaseServiceImpl: *core.NewBaseServiceImpl(outboxRepo),
+		outboxRepo:      outboxRepo,
+	}
+}
+
+// Publish записывает события в таблицу OUTBOX в транзакции из контекста
+func (s *outboxService) Publish(ctx context.Context, events ...core.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	messages := make([]OutboxMessage, 0, len(events))
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return core.NewTechnicalError(err, outboxServiceCode, "Ошибка при сериализации события "+event.EventType())
+		}
+		messages = append(messages, OutboxMessage{
+			EventType:     event.EventType(),
+			AggregateType: event.AggregateType(),
+			AggregateID:   event.AggregateID(),
+			Payload:       string(payload),
+			Status:        PendingStatus,
+			NextAttemptAt: now,
+		})
+	}
+
+	if _, err := s.outboxRepo.CreateMany(ctx, messages); err != nil {
+		return core.NewTechnicalError(err, outboxServiceCode, "Ошибка при сохранении событий")
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Envelope событие в том виде, в котором оно доставляется получателям.
+// ID постоянен между повторными доставками и служит ключом идемпотентности
+type Envelope struct {
+	ID            uint            `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   uint            `json:"aggregate_id"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+func ToEnvelope(message OutboxMessage) Envelope {
+	return Envelope{
+		ID:            message.ID,
+		Type:          message.EventType,
+		AggregateType: message.AggregateType,
+		AggregateID:   message.AggregateID,
+		OccurredAt:    message.CreatedAt,
+		Payload:       json.RawMessage(message.Payload),
+	}
+}
+
+// Sink получатель событий. Доставка выполняется как минимум один раз,
+// поэтому получатель должен быть готов к повторам с тем же ID
+type Sink interface {
+	Name() string
+	Deliver(ctx context.Context, envelope Envelope) error
+}
+
+// LogSink пишет события в лог приложения
+type LogSink struct{}
+
+func NewLogSink() *LogSink {
+	return &LogSink{}
+}
+
+func (s *LogSink) Name() string {
+	return "log"
+}
+
+func (s *LogSink) Deliver(ctx context.Context, envelope Envelope) error {
+	slog.InfoContext(ctx, "Domain event",
+		"id", envelope.ID,
+		"type", envelope.Type,
+		"aggregate_type", envelope.AggregateType,
+		"aggregate_id", envelope.AggregateID,
+		"payload", string(envelope.Payload),
+	)
+	return nil
+}
+
+// FileSink дописывает события в файл, по одному JSON на строку
+type FileSink struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileSink(path string) *FileSink {
+	return &FileSink{
+		path: path,
+	}
+}
+
+func (s *FileSink) Name() string {
+	return "file"
+}
+
+func (s *FileSink) Deliver(_ context.Context, envelope Envelope) error {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// HTTPSink отправляет события POST-запросом. Ответ вне диапазона 2xx считается ошибкой доставки
+type HTTPSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTPSink(url string, timeout time.Duration) *HTTPSink {
+	return &HTTPSink{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *HTTPSink) Name() string {
+	return "http"
+}
+
+func (s *HTTPSink) Deliver(ctx context.Context, envelope Envelope) error {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", fmt.Sprint(envelope.ID))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("event sink %s responded with status %d", s.url, resp.StatusCode)
+	}
+	return nil
+}
+
+// ChannelSink передает события в канал. Используется в тестах и для подписчиков внутри процесса
+type ChannelSink struct {
+	events chan Envelope
+}
+
+func NewChannelSink(buffer int) *ChannelSink {
+	return &ChannelSink{
+		events: make(chan Envelope, buffer),
+	}
+}
+
+func (s *ChannelSink) Name() string {
+	return "channel"
+}
+
+func (s *ChannelSink) Deliver(ctx context.Context, envelope Envelope) error {
+	select {
+	case s.events <- envelope:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Events канал доставленных событий
+func (s *ChannelSink) Events() <-chan Envelope {
+	return s.events
+}
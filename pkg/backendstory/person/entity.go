@@ -1,14 +1,12 @@
 package person
 
 import (
-	"database/sql"
-
 	"github.com/ActuallyHello/backendstory/pkg/core"
 )
 
 type Person struct {
 	core.Base
-	DeletedAt sql.NullTime `gorm:"column:DELETEDAT"`
+	core.SoftDelete
 
 	Firstname string `gorm:"column:FIRSTNAME"`
 	Lastname  string `gorm:"column:LASTNAME"`
@@ -23,3 +21,15 @@ func (Person) TableName() string {
 func (Person) LocalTableName() string {
 	return "Клиент"
 }
+
+var personSearchFields = core.NewSearchFields(
+	core.SearchField{Name: "firstname", Column: "FIRSTNAME", Type: core.FieldString, Sortable: true},
+	core.SearchField{Name: "lastname", Column: "LASTNAME", Type: core.FieldString, Sortable: true},
+	core.SearchField{Name: "phone", Column: "PHONE", Type: core.FieldString, Sortable: true},
+	core.SearchField{Name: "user_login", Column: "USERLOGIN", Type: core.FieldString, Sortable: true},
+	core.SearchField{Name: "deleted_at", Column: "DELETEDAT", Type: core.FieldTime, Sortable: true, Nullable: true},
+)
+
+func (Person) SearchFields() *core.SearchFields {
+	return personSearchFields
+}
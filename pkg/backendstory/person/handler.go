@@ -1,9 +1,7 @@
 package person
 
 import (
-	"encoding/json"
 	"net/http"
-	"strconv"
 
 	"github.com/ActuallyHello/backendstory/pkg/core"
 	"github.com/go-playground/validator/v10"
@@ -16,14 +14,17 @@ const (
 type PersonHandler struct {
 	validate      *validator.Validate
 	personService PersonService
+	crud          *core.CrudHandler[Person, PersonDTO]
 }
 
 func NewPersonHandler(
 	personService PersonService,
 ) *PersonHandler {
+	validate := core.DefaultValidator()
 	return &PersonHandler{
-		validate:      validator.New(),
+		validate:      validate,
 		personService: personService,
+		crud:          core.NewCrudHandler(personHandlerCode, validate, personService, core.MapWith(ToPersonDTO)),
 	}
 }
 
@@ -47,12 +48,12 @@ func (h *PersonHandler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var req CreatePersonRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := core.DecodeJSON(r, &req); err != nil {
 		core.HandleError(w, r, core.NewTechnicalError(err, personHandlerCode, err.Error()))
 		return
 	}
 	if err := h.validate.Struct(req); err != nil {
-		details := core.CollectValidationDetails(err)
+		details := core.CollectValidationDetails(r.Context(), err)
 		core.HandleValidationError(w, r, core.NewLogicalError(err, personHandlerCode, err.Error()), details)
 		return
 	}
@@ -69,39 +70,38 @@ func (h *PersonHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(ToPersonDTO(person))
+	h.crud.WriteEntity(w, r, http.StatusCreated, person)
 }
 
 // GetAll возвращает всех персон
 // @Summary Получить всех персон
-// @Description Возвращает список всех персон в системе
+// @Description Возвращает список всех персон в системе постранично, с фильтрацией и сортировкой из строки запроса
 // @Tags Persons
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {array} PersonDTO "Список персон"
+// @Param filter query string false "Условия через ';', например price>=100;category_id==3;status=in=(Available)"
+// @Param sort query string false "Поля сортировки через ',', префикс '-' - по убыванию, например -price,label"
+// @Param limit query int false "Размер страницы" default(20)
+// @Param offset query int false "Смещение"
+// @Param after query string false "Курсор следующей страницы"
+// @Param before query string false "Курсор предыдущей страницы"
+// @Param deleted query string false "Удаленные записи: exclude, include или only" default(exclude)
+// @Success 200 {object} core.Page[PersonDTO] "Список персон"
+// @Failure 400 {object} core.ErrorResponse "Некорректные параметры фильтра"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
 // @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
 // @Router /persons [get]
 // @Id getPersonAll
 func (h *PersonHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	persons, err := h.personService.GetAll(ctx)
+	ctx, err := core.WithDeletedScope(r.Context(), r.URL.Query().Get("deleted"))
 	if err != nil {
 		core.HandleError(w, r, err)
 		return
 	}
 
-	dtos := make([]PersonDTO, 0, len(persons))
-	for _, person := range persons {
-		dtos = append(dtos, ToPersonDTO(person))
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(dtos)
+	h.crud.GetAll(w, r.WithContext(ctx))
 }
 
 // GetById возвращает персону по ID
@@ -113,6 +113,7 @@ func (h *PersonHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Param id path int true "ID персоны"
 // @Success 200 {object} PersonDTO "Персона"
+// @Header 200 {string} ETag "Версия записи"
 // @Failure 400 {object} core.ErrorResponse "Неверный ID"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
@@ -121,27 +122,7 @@ func (h *PersonHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 // @Router /persons/{id} [get]
 // @Id getPersonById
 func (h *PersonHandler) GetById(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	reqID := r.PathValue("id")
-	if reqID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, personHandlerCode, "Отсуствует ИД параметр"))
-		return
-	}
-	id, err := strconv.Atoi(reqID)
-	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, personHandlerCode, "ИД параметр должен быть числовым!"+err.Error()))
-		return
-	}
-
-	person, err := h.personService.GetByID(ctx, uint(id))
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(ToPersonDTO(person))
+	h.crud.GetById(w, r)
 }
 
 // GetByUserLogin возвращает персону по логину пользователя
@@ -165,7 +146,7 @@ func (h *PersonHandler) GetByUserLogin(w http.ResponseWriter, r *http.Request) {
 
 	userLogin := r.PathValue("user_login")
 	if userLogin == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, personHandlerCode, "Отсуствует логин пользователя"))
+		core.HandleError(w, r, core.NewLogicalError(nil, core.PathParamMissingCode, "Отсутствует параметр %s", "user_login"))
 		return
 	}
 
@@ -175,21 +156,19 @@ func (h *PersonHandler) GetByUserLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(ToPersonDTO(person))
+	h.crud.WriteEntity(w, r, http.StatusOK, person)
 }
 
 // Delete удаляет персону
 // @Summary Удалить персону
-// @Description Удаляет персону по указанному идентификатору. Поддерживает мягкое удаление (soft delete)
+// @Description Мягко удаляет персону по указанному идентификатору, запись можно восстановить
 // @Tags Persons
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "ID персоны"
-// @Param soft query boolean false "Флаг мягкого удаления (true/false)" default(true)
 // @Success 204 "Успешно удалено"
-// @Failure 400 {object} core.ErrorResponse "Неверный ID или параметр soft"
+// @Failure 400 {object} core.ErrorResponse "Неверный ID"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
 // @Failure 404 {object} core.ErrorResponse "Персона не найдена"
@@ -197,43 +176,48 @@ func (h *PersonHandler) GetByUserLogin(w http.ResponseWriter, r *http.Request) {
 // @Router /persons/{id} [delete]
 // @Id deletePerson
 func (h *PersonHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	reqID := r.PathValue("id")
-	if reqID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, personHandlerCode, "Отсуствует ИД параметр"))
-		return
-	}
-	id, err := strconv.Atoi(reqID)
-	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, personHandlerCode, "ИД параметр должен быть числовым! "+err.Error()))
-		return
-	}
-
-	// Получаем параметр soft из query string
-	softDelete := true
-	softParam := r.URL.Query().Get("soft")
-	if softParam != "" {
-		soft, err := strconv.ParseBool(softParam)
-		if err != nil {
-			core.HandleError(w, r, core.NewLogicalError(err, personHandlerCode, "Невалидный признак удаления!"+err.Error()))
-			return
-		}
-		softDelete = soft
-	}
+	h.crud.Delete(w, r)
+}
 
-	person, err := h.personService.GetByID(ctx, uint(id))
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-	err = h.personService.Delete(ctx, person, softDelete)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
+// Restore восстанавливает мягко удаленную персону
+// @Summary Восстановить персону
+// @Description Восстанавливает мягко удаленную персону по указанному идентификатору
+// @Tags Persons
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID персоны"
+// @Success 200 {object} PersonDTO "Восстановленная персона"
+// @Failure 400 {object} core.ErrorResponse "Неверный ID"
+// @Failure 401 {object} core.ErrorResponse "Не авторизован"
+// @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} core.ErrorResponse "Удаленная персона не найдена"
+// @Failure 409 {object} core.ErrorResponse "Персона была изменена другим пользователем"
+// @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /persons/{id}/restore [post]
+// @Id restorePerson
+func (h *PersonHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	h.crud.Restore(w, r)
+}
 
-	w.WriteHeader(http.StatusNoContent)
+// Purge безвозвратно удаляет персону
+// @Summary Удалить персону безвозвратно
+// @Description Удаляет персону из базы данных, в том числе ранее мягко удаленную
+// @Tags Persons
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID персоны"
+// @Success 204 "Успешно удалено"
+// @Failure 400 {object} core.ErrorResponse "Неверный ID"
+// @Failure 401 {object} core.ErrorResponse "Не авторизован"
+// @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} core.ErrorResponse "Персона не найдена"
+// @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /persons/{id}/purge [delete]
+// @Id purgePerson
+func (h *PersonHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	h.crud.Purge(w, r)
 }
 
 // GetWithSearchCriteria выполняет поиск людей по критериям
@@ -244,7 +228,7 @@ func (h *PersonHandler) Delete(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Security BearerAuth
 // @Param request body core.SearchCriteria true "Критерии поиска"
-// @Success 200 {array} PersonDTO "Список найденных людей"
+// @Success 200 {object} core.Page[PersonDTO] "Список найденных людей"
 // @Failure 400 {object} core.ErrorResponse "Ошибка валидации"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
@@ -252,30 +236,5 @@ func (h *PersonHandler) Delete(w http.ResponseWriter, r *http.Request) {
 // @Router /persons/search [post]
 // @Id searchPerson
 func (h *PersonHandler) GetWithSearchCriteria(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	var req core.SearchCriteria
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		core.HandleError(w, r, core.NewTechnicalError(err, personHandlerCode, err.Error()))
-		return
-	}
-	if err := h.validate.Struct(req); err != nil {
-		details := core.CollectValidationDetails(err)
-		core.HandleValidationError(w, r, core.NewLogicalError(err, personHandlerCode, err.Error()), details)
-		return
-	}
-
-	persons, err := h.personService.GetWithSearchCriteria(ctx, req)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	dtos := make([]PersonDTO, 0, len(persons))
-	for _, person := range persons {
-		dtos = append(dtos, ToPersonDTO(person))
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(dtos)
+	h.crud.GetWithSearchCriteria(w, r)
 }
@@ -29,7 +29,7 @@ func (r *personRepository) FindByUserLogin(ctx context.Context, userLogin string
 	var person Person
 	if err := r.GetDB(ctx).Where("USERLOGIN = ?", userLogin).First(&person).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return Person{}, core.NewNotFoundError("Клиент не найден по заданному логину")
+			return Person{}, core.NewNotFoundError(core.PersonNotFoundCode, "Клиент не найден по заданному логину")
 		}
 		return Person{}, err
 	}
@@ -2,9 +2,7 @@ package person
 
 import (
 	"context"
-	"database/sql"
 	"errors"
-	"time"
 
 	"github.com/ActuallyHello/backendstory/pkg/core"
 )
@@ -18,7 +16,7 @@ type PersonService interface {
 
 	Create(ctx context.Context, person Person) (Person, error)
 	Update(ctx context.Context, person Person) (Person, error)
-	Delete(ctx context.Context, person Person, soft bool) error
+	Delete(ctx context.Context, person Person) error
 
 	GetByUserLogin(ctx context.Context, userLogin string) (Person, error)
 }
@@ -39,13 +37,13 @@ func NewPersonService(
 
 // Create создает новую запись Person
 func (s *personService) Create(ctx context.Context, person Person) (Person, error) {
-	// Проверка существования Person с таким UserLogin
-	existingByUserLogin, err := s.personRepo.FindByUserLogin(ctx, person.UserLogin)
+	// Проверка существования Person с таким UserLogin, включая удаленных: логин уникален в таблице
+	existingByUserLogin, err := s.personRepo.FindByUserLogin(core.WithDeleted(ctx), person.UserLogin)
 	if err != nil && errors.Is(err, &core.TechnicalError{}) {
 		return Person{}, err
 	}
 	if existingByUserLogin.ID > 0 {
-		return Person{}, core.NewLogicalError(nil, personServiceCode, "Клиент уже существует")
+		return Person{}, core.NewConflictError(nil, core.PersonAlreadyExistsCode, "Клиент уже существует")
 	}
 
 	// Создаем запись
@@ -71,21 +69,11 @@ func (s *personService) Update(ctx context.Context, person Person) (Person, erro
 	return updated, nil
 }
 
-// Delete удаляет Person (мягко или полностью)
-func (s *personService) Delete(ctx context.Context, person Person, soft bool) error {
-	if soft {
-		person.DeletedAt = sql.NullTime{
-			Time:  time.Now(),
-			Valid: true,
-		}
-		if _, err := s.Update(ctx, person); err != nil {
-			return err
-		}
-	} else {
-		err := s.personRepo.Delete(ctx, person)
-		if err != nil {
-			return core.NewTechnicalError(err, personServiceCode, "Ошибка при удалении клиента")
-		}
+// Delete мягко удаляет Person, восстановить запись можно через Restore
+func (s *personService) Delete(ctx context.Context, person Person) error {
+	err := s.personRepo.Delete(ctx, person)
+	if err != nil {
+		return core.NewTechnicalError(err, personServiceCode, "Ошибка при удалении клиента")
 	}
 	return nil
 }
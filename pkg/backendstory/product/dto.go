@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/ActuallyHello/backendstory/pkg/backendstory/enumvalue"
+	"github.com/ActuallyHello/backendstory/pkg/core"
 )
 
 // ProductCreateRequest represents request for creating product
@@ -39,8 +40,14 @@ type ProductUpdateRequest struct {
 	Quantity   uint   `json:"quantity" validate:"required,gte=0"`
 	CategoryID uint   `json:"category_id" validate:"omitempty,min=1,max=255"`
 	StatusID   uint   `json:"status_id" validate:"required,gt=0"`
+	// Version версия записи из ETag, при несовпадении обновление отклоняется
+	Version uint `json:"version" validate:"required,gt=0"`
 }
 
+// ProductBatchRequest represents batch request for creating, updating and deleting products
+// @Name ProductBatchRequest
+type ProductBatchRequest = core.BatchRequest[ProductCreateRequest, ProductUpdateRequest]
+
 // ProductDTO represents product data transfer object
 // @Name ProductDTO
 type ProductDTO struct {
@@ -1,8 +1,6 @@
 package product
 
 import (
-	"database/sql"
-
 	"github.com/ActuallyHello/backendstory/pkg/core"
 	"github.com/shopspring/decimal"
 )
@@ -15,6 +13,7 @@ const (
 
 type Product struct {
 	core.Base
+	core.SoftDelete
 
 	Label      string          `gorm:"column:LABEL"`
 	Code       string          `gorm:"column:CODE"`
@@ -23,7 +22,6 @@ type Product struct {
 	Quantity   uint            `gorm:"column:QUANTITY"`
 	CategoryID uint            `gorm:"column:CATEGORYID"`
 	StatusID   uint            `gorm:"column:STATUSID"`
-	DeletedAt  sql.NullTime    `gorm:"column:DELETEDAT"`
 	IsVisible  bool            `gorm:"column:ISVISIBLE"`
 }
 
@@ -34,3 +32,20 @@ func (Product) TableName() string {
 func (Product) LocalTableName() string {
 	return "Продукт"
 }
+
+var productSearchFields = core.NewSearchFields(
+	core.SearchField{Name: "code", Column: "CODE", Type: core.FieldString, Sortable: true},
+	core.SearchField{Name: "label", Column: "LABEL", Type: core.FieldString, Sortable: true},
+	core.SearchField{Name: "sku", Column: "SKU", Type: core.FieldString, Sortable: true},
+	core.SearchField{Name: "price", Column: "PRICE", Type: core.FieldDecimal, Sortable: true},
+	core.SearchField{Name: "quantity", Column: "QUANTITY", Type: core.FieldUint, Sortable: true},
+	core.SearchField{Name: "category_id", Column: "CATEGORYID", Type: core.FieldUint, Sortable: true},
+	core.SearchField{Name: "status", Column: "STATUSID", Type: core.FieldEnum, EnumCode: ProductStatus},
+	core.SearchField{Name: "status_id", Column: "STATUSID", Type: core.FieldUint, Sortable: true},
+	core.SearchField{Name: "is_visible", Column: "ISVISIBLE", Type: core.FieldBool, Sortable: true},
+	core.SearchField{Name: "deleted_at", Column: "DELETEDAT", Type: core.FieldTime, Sortable: true, Nullable: true},
+)
+
+func (Product) SearchFields() *core.SearchFields {
+	return productSearchFields
+}
@@ -0,0 +1,62 @@
+package product
+
+import (
+	"github.com/ActuallyHello/backendstory/pkg/core"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	productAggregate = "Product"
+
+	ProductPriceChangedEvent  = "ProductPriceChanged"
+	ProductStatusChangedEvent = "ProductStatusChanged"
+	StockChangedEvent         = "StockChanged"
+)
+
+// ProductPriceChanged изменилась цена продукта
+type ProductPriceChanged struct {
+	ProductID uint            `json:"product_id"`
+	OldPrice  decimal.Decimal `json:"old_price"`
+	NewPrice  decimal.Decimal `json:"new_price"`
+}
+
+func (e ProductPriceChanged) EventType() string     { return ProductPriceChangedEvent }
+func (e ProductPriceChanged) AggregateType() string { return productAggregate }
+func (e ProductPriceChanged) AggregateID() uint     { return e.ProductID }
+
+// ProductStatusChanged изменился статус продукта
+type ProductStatusChanged struct {
+	ProductID   uint `json:"product_id"`
+	OldStatusID uint `json:"old_status_id"`
+	NewStatusID uint `json:"new_status_id"`
+}
+
+func (e ProductStatusChanged) EventType() string     { return ProductStatusChangedEvent }
+func (e ProductStatusChanged) AggregateType() string { return productAggregate }
+func (e ProductStatusChanged) AggregateID() uint     { return e.ProductID }
+
+// StockChanged изменился остаток продукта на складе
+type StockChanged struct {
+	ProductID   uint `json:"product_id"`
+	OldQuantity uint `json:"old_quantity"`
+	NewQuantity uint `json:"new_quantity"`
+}
+
+func (e StockChanged) EventType() string     { return StockChangedEvent }
+func (e StockChanged) AggregateType() string { return productAggregate }
+func (e StockChanged) AggregateID() uint     { return e.ProductID }
+
+// productChangeEvents события по отличиям продукта до и после изменения
+func productChangeEvents(before, after Product) []core.Event {
+	var events []core.Event
+	if !before.Price.Equal(after.Price) {
+		events = append(events, ProductPriceChanged{ProductID: after.ID, OldPrice: before.Price, NewPrice: after.Price})
+	}
+	if before.StatusID != after.StatusID {
+		events = append(events, ProductStatusChanged{ProductID: after.ID, OldStatusID: before.StatusID, NewStatusID: after.StatusID})
+	}
+	if before.Quantity != after.Quantity {
+		events = append(events, StockChanged{ProductID: after.ID, OldQuantity: before.Quantity, NewQuantity: after.Quantity})
+	}
+	return events
+}
@@ -1,7 +1,7 @@
 package product
 
 import (
-	"encoding/json"
+	"context"
 	"net/http"
 	"strconv"
 
@@ -21,6 +21,8 @@ type ProductHandler struct {
 	producterationService ProductService
 	enumValueService      enumvalue.EnumValueService
 	categoryService       category.CategoryService
+	crud                  *core.CrudHandler[Product, ProductDTO]
+	batch                 *core.BatchHandler[Product, ProductDTO, ProductCreateRequest, ProductUpdateRequest]
 }
 
 func NewProductHandler(
@@ -28,12 +30,44 @@ func NewProductHandler(
 	enumValueService enumvalue.EnumValueService,
 	categoryService category.CategoryService,
 ) *ProductHandler {
-	return &ProductHandler{
-		validate:              validator.New(),
+	validate := core.DefaultValidator()
+	h := &ProductHandler{
+		validate:              validate,
 		producterationService: producterationService,
 		enumValueService:      enumValueService,
 		categoryService:       categoryService,
 	}
+	h.crud = core.NewCrudHandler(productHandlerCode, validate, producterationService, h.toProductDTO).
+		WithListMapper(h.toProductDTOs)
+	h.batch = core.NewBatchHandler(h.crud, producterationService, h.toCreatedProduct, h.toUpdatedProduct)
+	return h
+}
+
+// toProductDTO преобразует продукт в DTO вместе со статусом
+func (h *ProductHandler) toProductDTO(ctx context.Context, product Product) (ProductDTO, error) {
+	productStatus, err := h.enumValueService.GetByID(ctx, product.StatusID)
+	if err != nil {
+		return ProductDTO{}, err
+	}
+	return ToProductDTO(product, enumvalue.ToEnumValueDTO(productStatus)), nil
+}
+
+// toProductDTOs преобразует список продуктов в DTO, получая статусы одним запросом
+func (h *ProductHandler) toProductDTOs(ctx context.Context, products []Product) ([]ProductDTO, error) {
+	statusIDs := make([]uint, 0, len(products))
+	for _, product := range products {
+		statusIDs = append(statusIDs, product.StatusID)
+	}
+	statuses, err := h.enumValueService.GetMapByIDs(ctx, statusIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]ProductDTO, 0, len(products))
+	for _, product := range products {
+		dtos = append(dtos, ToProductDTO(product, enumvalue.ToEnumValueDTO(statuses[product.StatusID])))
+	}
+	return dtos, nil
 }
 
 // Create создает новый продукт
@@ -56,12 +90,12 @@ func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var req ProductCreateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := core.DecodeJSON(r, &req); err != nil {
 		core.HandleError(w, r, core.NewTechnicalError(err, productHandlerCode, err.Error()))
 		return
 	}
 	if err := h.validate.Struct(req); err != nil {
-		details := core.CollectValidationDetails(err)
+		details := core.CollectValidationDetails(r.Context(), err)
 		core.HandleValidationError(w, r, core.NewLogicalError(err, productHandlerCode, err.Error()), details)
 		return
 	}
@@ -69,7 +103,7 @@ func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 	// check price
 	price, err := decimal.NewFromString(req.Price)
 	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, productHandlerCode, err.Error()))
+		core.HandleError(w, r, core.NewLogicalError(err, core.ProductPriceInvalidCode, "Некорректная цена продукта '%s'", req.Price))
 		return
 	}
 	// check category
@@ -92,50 +126,110 @@ func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 		core.HandleError(w, r, err)
 		return
 	}
-	productStatus, err := h.enumValueService.GetByID(ctx, product.StatusID)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
 
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(ToProductDTO(product, enumvalue.ToEnumValueDTO(productStatus)))
+	h.crud.WriteEntity(w, r, http.StatusCreated, product)
 }
 
-// GetAll возвращает все продукты
-// @Summary Получить все продукты
-// @Description Возвращает список всех продуктов в системе
+// Batch создает, обновляет и удаляет продукты одним запросом
+// @Summary Пакетная обработка продуктов
+// @Description Применяет пакет операций над продуктами. В режиме all_or_nothing (по умолчанию) ошибка любого элемента откатывает весь пакет,
+// @Description в режиме best_effort элементы применяются независимо. Результат возвращается по каждому элементу
 // @Tags Products
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {array} ProductDTO "Список продуктов"
+// @Param request body ProductBatchRequest true "Пакет операций"
+// @Success 200 {object} core.BatchResponse[ProductDTO] "Все элементы применены"
+// @Success 207 {object} core.BatchResponse[ProductDTO] "Часть элементов завершилась ошибкой"
+// @Failure 400 {object} core.ErrorResponse "Неверный режим или размер пакета"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
 // @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
-// @Router /products [get]
-// @Id getProductAll
-func (h *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+// @Router /products/batch [post]
+// @Id batchProducts
+func (h *ProductHandler) Batch(w http.ResponseWriter, r *http.Request) {
+	h.batch.Batch(w, r)
+}
 
-	products, err := h.producterationService.GetAll(ctx)
+// toCreatedProduct проверяет элемент пакета на создание и собирает из него продукт
+func (h *ProductHandler) toCreatedProduct(ctx context.Context, req ProductCreateRequest) (Product, error) {
+	if err := h.validate.Struct(req); err != nil {
+		return Product{}, core.NewValidationError(err, core.ValidationErrorCode, "Данные запроса не прошли проверку, подробности в details")
+	}
+	price, err := decimal.NewFromString(req.Price)
 	if err != nil {
-		core.HandleError(w, r, err)
-		return
+		return Product{}, core.NewValidationError(err, core.ProductPriceInvalidCode, "Некорректная цена продукта '%s'", req.Price)
+	}
+	if _, err := h.categoryService.GetByID(ctx, req.CategoryID); err != nil {
+		return Product{}, err
 	}
+	return Product{
+		Code:       req.Code,
+		Label:      req.Label,
+		Sku:        req.Sku,
+		Price:      price,
+		Quantity:   req.Quantity,
+		CategoryID: req.CategoryID,
+		IsVisible:  req.IsVisible,
+	}, nil
+}
 
-	dtos := make([]ProductDTO, 0, len(products))
-	for _, product := range products {
-		productStatus, err := h.enumValueService.GetByID(ctx, product.StatusID)
-		if err != nil {
-			core.HandleError(w, r, err)
-			return
+// toUpdatedProduct проверяет элемент пакета на обновление и применяет его к текущему продукту
+func (h *ProductHandler) toUpdatedProduct(ctx context.Context, req ProductUpdateRequest) (Product, error) {
+	if err := h.validate.Struct(req); err != nil {
+		return Product{}, core.NewValidationError(err, core.ValidationErrorCode, "Данные запроса не прошли проверку, подробности в details")
+	}
+	price, err := decimal.NewFromString(req.Price)
+	if err != nil {
+		return Product{}, core.NewValidationError(err, core.ProductPriceInvalidCode, "Некорректная цена продукта '%s'", req.Price)
+	}
+
+	product, err := h.producterationService.GetByID(ctx, req.ID)
+	if err != nil {
+		return Product{}, err
+	}
+	product.SetVersion(req.Version)
+	if req.CategoryID > 0 {
+		if _, err := h.categoryService.GetByID(ctx, req.CategoryID); err != nil {
+			return Product{}, err
 		}
-		dtos = append(dtos, ToProductDTO(product, enumvalue.ToEnumValueDTO(productStatus)))
+		product.CategoryID = req.CategoryID
+	}
+	if req.Code != "" {
+		product.Code = req.Code
 	}
+	if req.Label != "" {
+		product.Label = req.Label
+	}
+	product.Sku = req.Sku
+	product.Price = price
+	product.Quantity = req.Quantity
+	product.StatusID = req.StatusID
+	return product, nil
+}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(dtos)
+// GetAll возвращает все продукты
+// @Summary Получить все продукты
+// @Description Возвращает список всех продуктов в системе постранично, с фильтрацией и сортировкой из строки запроса
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param filter query string false "Условия через ';', например price>=100;category_id==3;status=in=(Available)"
+// @Param sort query string false "Поля сортировки через ',', префикс '-' - по убыванию, например -price,label"
+// @Param limit query int false "Размер страницы" default(20)
+// @Param offset query int false "Смещение"
+// @Param after query string false "Курсор следующей страницы"
+// @Param before query string false "Курсор предыдущей страницы"
+// @Success 200 {object} core.Page[ProductDTO] "Список продуктов"
+// @Failure 400 {object} core.ErrorResponse "Некорректные параметры фильтра"
+// @Failure 401 {object} core.ErrorResponse "Не авторизован"
+// @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
+// @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /products [get]
+// @Id getProductAll
+func (h *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	h.crud.GetAll(w, r)
 }
 
 // GetById возвращает продукт по ID
@@ -147,6 +241,7 @@ func (h *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Param id path int true "ID продукта"
 // @Success 200 {object} ProductDTO "Продукт"
+// @Header 200 {string} ETag "Версия записи"
 // @Failure 400 {object} core.ErrorResponse "Неверный ID"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
@@ -155,32 +250,7 @@ func (h *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 // @Router /products/{id} [get]
 // @Id getProductById
 func (h *ProductHandler) GetById(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	reqID := r.PathValue("id")
-	if reqID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, productHandlerCode, "Отсутствует ИД параметр"))
-		return
-	}
-	id, err := strconv.Atoi(reqID)
-	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, productHandlerCode, "ИД параметр должен быть числовым! "+err.Error()))
-		return
-	}
-
-	product, err := h.producterationService.GetByID(ctx, uint(id))
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-	productStatus, err := h.enumValueService.GetByID(ctx, product.StatusID)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(ToProductDTO(product, enumvalue.ToEnumValueDTO(productStatus)))
+	h.crud.GetById(w, r)
 }
 
 // GetByCode возвращает продукт по коду
@@ -204,7 +274,7 @@ func (h *ProductHandler) GetByCode(w http.ResponseWriter, r *http.Request) {
 
 	code := r.PathValue("code")
 	if code == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, productHandlerCode, "Код параметра обзятаельна"))
+		core.HandleError(w, r, core.NewLogicalError(nil, core.PathParamMissingCode, "Отсутствует параметр %s", "code"))
 		return
 	}
 
@@ -213,14 +283,8 @@ func (h *ProductHandler) GetByCode(w http.ResponseWriter, r *http.Request) {
 		core.HandleError(w, r, err)
 		return
 	}
-	productStatus, err := h.enumValueService.GetByID(ctx, product.StatusID)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(ToProductDTO(product, enumvalue.ToEnumValueDTO(productStatus)))
+	h.crud.WriteEntity(w, r, http.StatusOK, product)
 }
 
 // GetWithSearchCriteria выполняет поиск продуктов по критериям
@@ -231,7 +295,7 @@ func (h *ProductHandler) GetByCode(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Security BearerAuth
 // @Param request body core.SearchCriteria true "Критерии поиска"
-// @Success 200 {array} ProductDTO "Список найденных продуктов"
+// @Success 200 {object} core.Page[ProductDTO] "Список найденных продуктов"
 // @Failure 400 {object} core.ErrorResponse "Ошибка валидации"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
@@ -239,48 +303,17 @@ func (h *ProductHandler) GetByCode(w http.ResponseWriter, r *http.Request) {
 // @Router /products/search [post]
 // @Id searchProduct
 func (h *ProductHandler) GetWithSearchCriteria(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	var req core.SearchCriteria
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		core.HandleError(w, r, core.NewTechnicalError(err, productHandlerCode, err.Error()))
-		return
-	}
-	if err := h.validate.Struct(req); err != nil {
-		details := core.CollectValidationDetails(err)
-		core.HandleValidationError(w, r, core.NewLogicalError(err, productHandlerCode, err.Error()), details)
-		return
-	}
-
-	products, err := h.producterationService.GetWithSearchCriteria(ctx, req)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-
-	dtos := make([]ProductDTO, 0, len(products))
-	for _, product := range products {
-		productStatus, err := h.enumValueService.GetByID(ctx, product.StatusID)
-		if err != nil {
-			core.HandleError(w, r, err)
-			return
-		}
-		dtos = append(dtos, ToProductDTO(product, enumvalue.ToEnumValueDTO(productStatus)))
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(dtos)
+	h.crud.GetWithSearchCriteria(w, r)
 }
 
 // Delete удаляет продукт
 // @Summary Удалить продукт
-// @Description Удаляет продукт по указанному идентификатору
+// @Description Мягко удаляет продукт по указанному идентификатору, запись можно восстановить
 // @Tags Products
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "ID продукта"
-// @Param soft query boolean false "Флаг мягкого удаления (true/false)" default(true)
 // @Success 204 "Успешно удалено"
 // @Failure 400 {object} core.ErrorResponse "Неверный ID"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
@@ -290,43 +323,73 @@ func (h *ProductHandler) GetWithSearchCriteria(w http.ResponseWriter, r *http.Re
 // @Router /products/{id} [delete]
 // @Id deleteProduct
 func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	reqID := r.PathValue("id")
-	if reqID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, productHandlerCode, "Отсуствует ИД параметр"))
-		return
-	}
-	id, err := strconv.Atoi(reqID)
-	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, productHandlerCode, "ИД параметр должен быть числвым! "+err.Error()))
-		return
-	}
+	h.crud.Delete(w, r)
+}
 
-	// Получаем параметр soft из query string
-	softDelete := true
-	softParam := r.URL.Query().Get("soft")
-	if softParam != "" {
-		soft, err := strconv.ParseBool(softParam)
-		if err != nil {
-			core.HandleError(w, r, core.NewLogicalError(err, productHandlerCode, "Невалидный признак удаления!"+err.Error()))
-			return
-		}
-		softDelete = soft
-	}
+// Restore восстанавливает мягко удаленный продукт
+// @Summary Восстановить продукт
+// @Description Восстанавливает мягко удаленный продукт по указанному идентификатору
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID продукта"
+// @Success 200 {object} ProductDTO "Восстановленный продукт"
+// @Header 200 {string} ETag "Версия записи"
+// @Failure 400 {object} core.ErrorResponse "Неверный ID"
+// @Failure 401 {object} core.ErrorResponse "Не авторизован"
+// @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} core.ErrorResponse "Удаленный продукт не найден"
+// @Failure 409 {object} core.ErrorResponse "Продукт был изменен другим пользователем"
+// @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /products/{id}/restore [post]
+// @Id restoreProduct
+func (h *ProductHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	h.crud.Restore(w, r)
+}
 
-	product, err := h.producterationService.GetByID(ctx, uint(id))
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
-	err = h.producterationService.Delete(ctx, product, softDelete)
-	if err != nil {
-		core.HandleError(w, r, err)
-		return
-	}
+// Purge безвозвратно удаляет продукт
+// @Summary Удалить продукт безвозвратно
+// @Description Удаляет продукт из базы данных, в том числе ранее мягко удаленный
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID продукта"
+// @Success 204 "Успешно удалено"
+// @Failure 400 {object} core.ErrorResponse "Неверный ID"
+// @Failure 401 {object} core.ErrorResponse "Не авторизован"
+// @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} core.ErrorResponse "Продукт не найден"
+// @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /products/{id}/purge [delete]
+// @Id purgeProduct
+func (h *ProductHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	h.crud.Purge(w, r)
+}
 
-	w.WriteHeader(http.StatusNoContent)
+// GetDeleted возвращает мягко удаленные продукты
+// @Summary Получить удаленные продукты
+// @Description Возвращает мягко удаленные продукты постранично, с фильтрацией и сортировкой из строки запроса
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param filter query string false "Условия через ';', например price>=100;category_id==3;status=in=(Available)"
+// @Param sort query string false "Поля сортировки через ',', префикс '-' - по убыванию, например -deleted_at"
+// @Param limit query int false "Размер страницы" default(20)
+// @Param offset query int false "Смещение"
+// @Param after query string false "Курсор следующей страницы"
+// @Param before query string false "Курсор предыдущей страницы"
+// @Success 200 {object} core.Page[ProductDTO] "Список удаленных продуктов"
+// @Failure 400 {object} core.ErrorResponse "Некорректные параметры фильтра"
+// @Failure 401 {object} core.ErrorResponse "Не авторизован"
+// @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
+// @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /products/deleted [get]
+// @Id getProductDeleted
+func (h *ProductHandler) GetDeleted(w http.ResponseWriter, r *http.Request) {
+	h.crud.GetAll(w, r.WithContext(core.OnlyDeleted(r.Context())))
 }
 
 // GetByCategoryID возвращает продукты по категории
@@ -350,12 +413,12 @@ func (h *ProductHandler) GetByCategoryID(w http.ResponseWriter, r *http.Request)
 
 	reqCategoryID := r.PathValue("category_id")
 	if reqCategoryID == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, productHandlerCode, "Отсуствует ИД категории"))
+		core.HandleError(w, r, core.NewLogicalError(nil, core.PathParamMissingCode, "Отсутствует параметр %s", "category_id"))
 		return
 	}
 	categoryID, err := strconv.Atoi(reqCategoryID)
 	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, productHandlerCode, "ИД категории должен быть числовым! "+err.Error()))
+		core.HandleError(w, r, core.NewLogicalError(err, core.PathParamInvalidCode, "Параметр %s должен быть числовым", "category_id"))
 		return
 	}
 
@@ -365,18 +428,7 @@ func (h *ProductHandler) GetByCategoryID(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	dtos := make([]ProductDTO, 0, len(products))
-	for _, product := range products {
-		productStatus, err := h.enumValueService.GetByID(ctx, product.StatusID)
-		if err != nil {
-			core.HandleError(w, r, err)
-			return
-		}
-		dtos = append(dtos, ToProductDTO(product, enumvalue.ToEnumValueDTO(productStatus)))
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(dtos)
+	h.crud.WriteList(w, r, products)
 }
 
 // ChangeStatus изменяет статус продукта
@@ -387,11 +439,14 @@ func (h *ProductHandler) GetByCategoryID(w http.ResponseWriter, r *http.Request)
 // @Produce json
 // @Security BearerAuth
 // @Param request body ProductStatusChangeRequest true "Запрос на изменение статуса"
+// @Param If-Match header string false "Версия записи из ETag"
 // @Success 200 {object} ProductDTO "Продукт с обновленным статусом"
+// @Header 200 {string} ETag "Версия записи"
 // @Failure 400 {object} core.ErrorResponse "Неверный запрос"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
 // @Failure 404 {object} core.ErrorResponse "Продукт или статус не найден"
+// @Failure 409 {object} core.ErrorResponse "Запись изменена другим пользователем"
 // @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
 // @Router /products/change-status [post]
 // @Id changeProductStatus
@@ -399,12 +454,12 @@ func (h *ProductHandler) ChangeStatus(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var req ProductStatusChangeRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := core.DecodeJSON(r, &req); err != nil {
 		core.HandleError(w, r, core.NewTechnicalError(err, productHandlerCode, err.Error()))
 		return
 	}
 	if err := h.validate.Struct(req); err != nil {
-		details := core.CollectValidationDetails(err)
+		details := core.CollectValidationDetails(r.Context(), err)
 		core.HandleValidationError(w, r, core.NewLogicalError(err, productHandlerCode, err.Error()), details)
 		return
 	}
@@ -414,28 +469,25 @@ func (h *ProductHandler) ChangeStatus(w http.ResponseWriter, r *http.Request) {
 		core.HandleError(w, r, err)
 		return
 	}
-
-	status, err := h.enumValueService.GetByCodeAndEnumCode(ctx, req.StatusCode, ProductStatus)
-	if err != nil {
+	if err := core.ApplyIfMatch(r, &product); err != nil {
 		core.HandleError(w, r, err)
 		return
 	}
 
-	product.StatusID = status.ID
-	product, err = h.producterationService.Update(ctx, product)
+	status, err := h.enumValueService.GetByCodeAndEnumCode(ctx, req.StatusCode, ProductStatus)
 	if err != nil {
 		core.HandleError(w, r, err)
 		return
 	}
 
-	productStatus, err := h.enumValueService.GetByID(ctx, product.StatusID)
+	product.StatusID = status.ID
+	product, err = h.producterationService.Update(ctx, product)
 	if err != nil {
 		core.HandleError(w, r, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(ToProductDTO(product, enumvalue.ToEnumValueDTO(productStatus)))
+	h.crud.WriteEntity(w, r, http.StatusOK, product)
 }
 
 // ChangePrice изменяет цену продукта
@@ -446,11 +498,14 @@ func (h *ProductHandler) ChangeStatus(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Security BearerAuth
 // @Param request body ProductPriceChangeRequest true "Запрос на изменение цены"
+// @Param If-Match header string false "Версия записи из ETag"
 // @Success 200 {object} ProductDTO "Продукт с обновленной ценой"
+// @Header 200 {string} ETag "Версия записи"
 // @Failure 400 {object} core.ErrorResponse "Неверный запрос или цена"
 // @Failure 401 {object} core.ErrorResponse "Не авторизован"
 // @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
 // @Failure 404 {object} core.ErrorResponse "Продукт не найден"
+// @Failure 409 {object} core.ErrorResponse "Запись изменена другим пользователем"
 // @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
 // @Router /products/change-price [post]
 // @Id changeProductPrice
@@ -458,12 +513,12 @@ func (h *ProductHandler) ChangePrice(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var req ProductPriceChangeRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := core.DecodeJSON(r, &req); err != nil {
 		core.HandleError(w, r, core.NewTechnicalError(err, productHandlerCode, err.Error()))
 		return
 	}
 	if err := h.validate.Struct(req); err != nil {
-		details := core.CollectValidationDetails(err)
+		details := core.CollectValidationDetails(r.Context(), err)
 		core.HandleValidationError(w, r, core.NewLogicalError(err, productHandlerCode, err.Error()), details)
 		return
 	}
@@ -471,7 +526,7 @@ func (h *ProductHandler) ChangePrice(w http.ResponseWriter, r *http.Request) {
 	// check price
 	price, err := decimal.NewFromString(req.Price)
 	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, productHandlerCode, err.Error()))
+		core.HandleError(w, r, core.NewLogicalError(err, core.ProductPriceInvalidCode, "Некорректная цена продукта '%s'", req.Price))
 		return
 	}
 
@@ -480,20 +535,17 @@ func (h *ProductHandler) ChangePrice(w http.ResponseWriter, r *http.Request) {
 		core.HandleError(w, r, err)
 		return
 	}
-
-	product.Price = price
-	product, err = h.producterationService.Update(ctx, product)
-	if err != nil {
+	if err := core.ApplyIfMatch(r, &product); err != nil {
 		core.HandleError(w, r, err)
 		return
 	}
 
-	productStatus, err := h.enumValueService.GetByID(ctx, product.StatusID)
+	product.Price = price
+	product, err = h.producterationService.Update(ctx, product)
 	if err != nil {
 		core.HandleError(w, r, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(ToProductDTO(product, enumvalue.ToEnumValueDTO(productStatus)))
+	h.crud.WriteEntity(w, r, http.StatusOK, product)
 }
@@ -3,6 +3,7 @@ package product
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/ActuallyHello/backendstory/pkg/core"
 	"gorm.io/gorm"
@@ -31,7 +32,7 @@ func (r *productRepository) FindByCode(ctx context.Context, code string) (Produc
 	var product Product
 	if err := r.GetDB(ctx).Where("CODE = ?", code).First(&product).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return Product{}, core.NewNotFoundError("Продукт не существует по переданному коду")
+			return Product{}, core.NewNotFoundError(core.ProductNotFoundCode, "Продукт не существует по переданному коду")
 		}
 		return Product{}, err
 	}
@@ -43,7 +44,7 @@ func (r *productRepository) FindBySku(ctx context.Context, sku string) (Product,
 	var product Product
 	if err := r.GetDB(ctx).Where("SKU = ?", sku).First(&product).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return Product{}, core.NewNotFoundError("Товара с таким артикулом не существует")
+			return Product{}, core.NewNotFoundError(core.ProductNotFoundCode, "Товара с таким артикулом не существует")
 		}
 		return Product{}, err
 	}
@@ -55,9 +56,45 @@ func (r *productRepository) FindByCategoryID(ctx context.Context, categoryID uin
 	var products []Product
 	if err := r.GetDB(ctx).Where("CATEGORYID = ?", categoryID).Find(&products).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, core.NewNotFoundError("Продукт не найден по заданной категории")
+			return nil, core.NewNotFoundError(core.ProductNotFoundCode, "Продукт не найден по заданной категории")
 		}
 		return nil, err
 	}
 	return products, nil
 }
+
+// cachedProductRepository кэширует чтение продуктов, включая собственные выборки репозитория
+type cachedProductRepository struct {
+	*core.CachedRepository[Product]
+	productRepo ProductRepository
+}
+
+func NewCachedProductRepository(
+	productRepo ProductRepository,
+	backend core.CacheBackend,
+	prefix string,
+	ttl time.Duration,
+) *cachedProductRepository {
+	return &cachedProductRepository{
+		CachedRepository: core.NewCachedRepository[Product](productRepo, backend, prefix, ttl),
+		productRepo:      productRepo,
+	}
+}
+
+func (r *cachedProductRepository) FindByCode(ctx context.Context, code string) (Product, error) {
+	return core.CachedQuery(ctx, r.CachedRepository, "code", code, func(ctx context.Context) (Product, error) {
+		return r.productRepo.FindByCode(ctx, code)
+	})
+}
+
+func (r *cachedProductRepository) FindBySku(ctx context.Context, sku string) (Product, error) {
+	return core.CachedQuery(ctx, r.CachedRepository, "sku", sku, func(ctx context.Context) (Product, error) {
+		return r.productRepo.FindBySku(ctx, sku)
+	})
+}
+
+func (r *cachedProductRepository) FindByCategoryID(ctx context.Context, categoryID uint) ([]Product, error) {
+	return core.CachedQuery(ctx, r.CachedRepository, "category", categoryID, func(ctx context.Context) ([]Product, error) {
+		return r.productRepo.FindByCategoryID(ctx, categoryID)
+	})
+}
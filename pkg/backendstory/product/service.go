@@ -2,9 +2,7 @@ package product
 
 import (
 	"context"
-	"database/sql"
 	"errors"
-	"time"
 
 	"github.com/ActuallyHello/backendstory/pkg/backendstory/enum"
 	"github.com/ActuallyHello/backendstory/pkg/backendstory/enumvalue"
@@ -20,7 +18,8 @@ type ProductService interface {
 
 	Create(ctx context.Context, product Product) (Product, error)
 	Update(ctx context.Context, product Product) (Product, error)
-	Delete(ctx context.Context, product Product, soft bool) error
+	Delete(ctx context.Context, product Product) error
+	ExecuteBatch(ctx context.Context, batch *core.Batch[Product])
 
 	GetByCode(ctx context.Context, code string) (Product, error)
 	GetByCategoryID(ctx context.Context, categoryID uint) ([]Product, error)
@@ -29,21 +28,27 @@ type ProductService interface {
 type productService struct {
 	core.BaseServiceImpl[Product]
 	productRepo ProductRepository
+	txManager   core.TxManager
 
 	enumService      enum.EnumService
 	enumValueService enumvalue.EnumValueService
+	eventPublisher   core.EventPublisher
 }
 
 func NewProductService(
 	productRepo ProductRepository,
+	txManager core.TxManager,
 	enumService enum.EnumService,
 	enumValueService enumvalue.EnumValueService,
+	eventPublisher core.EventPublisher,
 ) *productService {
 	return &productService{
 		BaseServiceImpl:  *core.NewBaseServiceImpl(productRepo),
 		productRepo:      productRepo,
+		txManager:        txManager,
 		enumService:      enumService,
 		enumValueService: enumValueService,
+		eventPublisher:   eventPublisher,
 	}
 }
 
@@ -53,7 +58,7 @@ func (s *productService) Create(ctx context.Context, product Product) (Product,
 		return Product{}, err
 	}
 	if exists {
-		return Product{}, core.NewLogicalError(nil, productServiceCode, "Продукт уже существует")
+		return Product{}, core.NewConflictError(nil, core.ProductAlreadyExistsCode, "Продукт уже существует")
 	}
 
 	status, err := s.enumValueService.GetByCodeAndEnumCode(ctx, AvailableProductStatus, ProductStatus)
@@ -70,31 +75,80 @@ func (s *productService) Create(ctx context.Context, product Product) (Product,
 }
 
 func (s *productService) Update(ctx context.Context, product Product) (Product, error) {
-	updated, err := s.GetRepo().Update(ctx, product)
+	var updated Product
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		existing, err := s.GetByID(ctx, product.ID)
+		if err != nil {
+			return err
+		}
+
+		updated, err = s.GetRepo().Update(ctx, product)
+		if err != nil {
+			return core.NewTechnicalError(err, productServiceCode, "Ошибка при обновлении продукта")
+		}
+
+		return s.eventPublisher.Publish(ctx, productChangeEvents(existing, updated)...)
+	})
 	if err != nil {
-		return Product{}, core.NewTechnicalError(err, productServiceCode, "Ошибка при обновлении продукта")
+		return Product{}, err
 	}
 	return updated, nil
 }
 
-func (s *productService) Delete(ctx context.Context, product Product, soft bool) error {
-	var err error
-	if soft {
-		product.DeletedAt = sql.NullTime{
-			Time:  time.Now(),
-			Valid: true,
-		}
-		_, err = s.GetRepo().Update(ctx, product)
-	} else {
-		err = s.GetRepo().Delete(ctx, product)
-	}
-
+func (s *productService) Delete(ctx context.Context, product Product) error {
+	err := s.GetRepo().Delete(ctx, product)
 	if err != nil {
 		return core.NewTechnicalError(err, productServiceCode, "Ошибка при удалении продукта")
 	}
 	return nil
 }
 
+// ExecuteBatch применяет пакет операций над продуктами, результат записывается в элементы пакета
+func (s *productService) ExecuteBatch(ctx context.Context, batch *core.Batch[Product]) {
+	core.ExecuteBatch(ctx, s.txManager, s.GetRepo(), batch, s.prepareBatchItem)
+}
+
+// prepareBatchItem выполняет для элемента пакета те же проверки, что и одиночные операции
+func (s *productService) prepareBatchItem(ctx context.Context, operation core.BatchOperation, product Product) (Product, error) {
+	switch operation {
+	case core.BatchCreate:
+		exists, err := s.isProductExists(ctx, product)
+		if err != nil {
+			return Product{}, err
+		}
+		if exists {
+			return Product{}, core.NewConflictError(nil, core.ProductAlreadyExistsCode, "Продукт уже существует")
+		}
+		status, err := s.enumValueService.GetByCodeAndEnumCode(ctx, AvailableProductStatus, ProductStatus)
+		if err != nil {
+			return Product{}, err
+		}
+		product.StatusID = status.ID
+	case core.BatchUpdate:
+		productStatus, err := s.enumService.GetByCode(ctx, ProductStatus)
+		if err != nil {
+			return Product{}, err
+		}
+		status, err := s.enumValueService.GetByID(ctx, product.StatusID)
+		if err != nil {
+			return Product{}, err
+		}
+		if status.EnumID != productStatus.ID {
+			return Product{}, core.NewLogicalError(nil, core.ProductStatusInvalidCode, "Статус не относится к статусам продукта")
+		}
+
+		// элемент готовится внутри транзакции пакета, поэтому события откатятся вместе с ним
+		existing, err := s.GetByID(ctx, product.ID)
+		if err != nil {
+			return Product{}, err
+		}
+		if err := s.eventPublisher.Publish(ctx, productChangeEvents(existing, product)...); err != nil {
+			return Product{}, err
+		}
+	}
+	return product, nil
+}
+
 func (s *productService) GetByCode(ctx context.Context, code string) (Product, error) {
 	product, err := s.productRepo.FindByCode(ctx, code)
 	if err != nil {
@@ -117,12 +171,12 @@ func (s *productService) GetByCategoryID(ctx context.Context, categoryID uint) (
 func (s *productService) isProductExists(ctx context.Context, product Product) (bool, error) {
 	conditions := []core.SearchCondition{}
 	conditions = append(conditions, core.SearchCondition{
-		Field:     "CODE",
+		Field:     "code",
 		Operation: core.OpEqual,
 		Value:     product.Code,
 	})
 	conditions = append(conditions, core.SearchCondition{
-		Field:     "SKU",
+		Field:     "sku",
 		Operation: core.OpEqual,
 		Value:     product.Sku,
 	})
@@ -131,7 +185,8 @@ func (s *productService) isProductExists(ctx context.Context, product Product) (
 		SearchConditions: conditions,
 	}
 
-	products, err := s.GetWithSearchCriteria(ctx, criteria)
+	// код и артикул уникальны в таблице, поэтому учитываем и удаленные продукты
+	products, err := s.GetWithSearchCriteria(core.WithDeleted(ctx), criteria)
 	if err != nil && errors.Is(err, &core.TechnicalError{}) {
 		return false, err
 	}
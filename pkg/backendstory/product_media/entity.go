@@ -16,3 +16,12 @@ func (ProductMedia) TableName() string {
 func (ProductMedia) LocalTableName() string {
 	return "Картинка товара"
 }
+
+var productMediaSearchFields = core.NewSearchFields(
+	core.SearchField{Name: "link", Column: "LINK", Type: core.FieldString},
+	core.SearchField{Name: "product_id", Column: "PRODUCTID", Type: core.FieldUint, Sortable: true},
+)
+
+func (ProductMedia) SearchFields() *core.SearchFields {
+	return productMediaSearchFields
+}
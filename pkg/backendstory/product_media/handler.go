@@ -35,7 +35,7 @@ func NewProductMediaHandler(
 	staticFilesPath string,
 ) *ProductMediaHandler {
 	return &ProductMediaHandler{
-		validate:            validator.New(),
+		validate:            core.DefaultValidator(),
 		productMediaService: productMediaService,
 		productService:      productService,
 		fileService:         fileService,
@@ -67,20 +67,20 @@ func (h *ProductMediaHandler) UploadImage(w http.ResponseWriter, r *http.Request
 
 	// Парсим multipart форму
 	if err := r.ParseMultipartForm(resources.MaxFileSize); err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, productMediaHandlerCode, "Failed to parse form"))
+		core.HandleError(w, r, core.NewLogicalError(err, core.FormInvalidCode, "Некорректная multipart форма"))
 		return
 	}
 
 	// Получаем product_id из формы
 	productIDStr := r.FormValue("product_id")
 	if productIDStr == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, productMediaHandlerCode, "product_id is required"))
+		core.HandleError(w, r, core.NewLogicalError(nil, core.FormFieldMissingCode, "Отсутствует поле формы %s", "product_id"))
 		return
 	}
 
 	productID, err := strconv.Atoi(productIDStr)
 	if err != nil || productID <= 0 {
-		core.HandleError(w, r, core.NewLogicalError(err, productMediaHandlerCode, "Invalid product_id"))
+		core.HandleError(w, r, core.NewLogicalError(err, core.FormFieldInvalidCode, "Поле формы %s должно быть положительным числом", "product_id"))
 		return
 	}
 
@@ -94,7 +94,7 @@ func (h *ProductMediaHandler) UploadImage(w http.ResponseWriter, r *http.Request
 	// Получаем файл из формы
 	file, header, err := r.FormFile("file")
 	if err != nil {
-		core.HandleError(w, r, core.NewLogicalError(err, productMediaHandlerCode, "Failed to get file from form"))
+		core.HandleError(w, r, core.NewLogicalError(err, core.FormFieldMissingCode, "Отсутствует поле формы %s", "file"))
 		return
 	}
 	defer file.Close()
@@ -111,7 +111,6 @@ func (h *ProductMediaHandler) UploadImage(w http.ResponseWriter, r *http.Request
 	}
 	createdMedia, err := h.productMediaService.Create(ctx, productMedia)
 	if err != nil {
-		h.fileService.DeleteImage(productMedia.Link, h.staticFilesPath)
 		core.HandleError(w, r, err)
 		return
 	}
@@ -141,13 +140,13 @@ func (h *ProductMediaHandler) GetByProductID(w http.ResponseWriter, r *http.Requ
 
 	productIDStr := r.PathValue("product_id")
 	if productIDStr == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, productMediaHandlerCode, "product_id parameter missing"))
+		core.HandleError(w, r, core.NewLogicalError(nil, core.PathParamMissingCode, "Отсутствует параметр %s", "product_id"))
 		return
 	}
 
 	productID, err := strconv.Atoi(productIDStr)
 	if err != nil || productID <= 0 {
-		core.HandleError(w, r, core.NewLogicalError(err, productMediaHandlerCode, "Invalid product_id"))
+		core.HandleError(w, r, core.NewLogicalError(err, core.PathParamInvalidCode, "Параметр %s должен быть числовым", "product_id"))
 		return
 	}
 
@@ -194,13 +193,13 @@ func (h *ProductMediaHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	idStr := r.PathValue("id")
 	if idStr == "" {
-		core.HandleError(w, r, core.NewLogicalError(nil, productMediaHandlerCode, "id parameter missing"))
+		core.HandleError(w, r, core.NewLogicalError(nil, core.PathParamMissingCode, "Отсутствует параметр %s", "id"))
 		return
 	}
 
 	id, err := strconv.Atoi(idStr)
 	if err != nil || id <= 0 {
-		core.HandleError(w, r, core.NewLogicalError(err, productMediaHandlerCode, "Invalid id"))
+		core.HandleError(w, r, core.NewLogicalError(err, core.PathParamInvalidCode, "Параметр %s должен быть числовым", "id"))
 		return
 	}
 
@@ -211,12 +210,7 @@ func (h *ProductMediaHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Удаляем файл с диска
-	if media.Link != "" {
-		h.fileService.DeleteImage(media.Link, h.staticFilesPath)
-	}
-
-	// Удаляем запись из БД
+	// Удаляем запись из БД, файл удаляется с диска после фиксации
 	err = h.productMediaService.Delete(ctx, media)
 	if err != nil {
 		core.HandleError(w, r, err)
@@ -2,6 +2,7 @@ package productmedia
 
 import (
 	"context"
+	"time"
 
 	"github.com/ActuallyHello/backendstory/pkg/core"
 	"gorm.io/gorm"
@@ -30,3 +31,27 @@ func (r *productMediaRepository) FindByProductID(ctx context.Context, productID
 	}
 	return productMedia, nil
 }
+
+// cachedProductMediaRepository кэширует чтение картинок товаров, включая собственные выборки репозитория
+type cachedProductMediaRepository struct {
+	*core.CachedRepository[ProductMedia]
+	productMediaRepo ProductMediaRepository
+}
+
+func NewCachedProductMediaRepository(
+	productMediaRepo ProductMediaRepository,
+	backend core.CacheBackend,
+	prefix string,
+	ttl time.Duration,
+) *cachedProductMediaRepository {
+	return &cachedProductMediaRepository{
+		CachedRepository: core.NewCachedRepository[ProductMedia](productMediaRepo, backend, prefix, ttl),
+		productMediaRepo: productMediaRepo,
+	}
+}
+
+func (r *cachedProductMediaRepository) FindByProductID(ctx context.Context, productID uint) ([]ProductMedia, error) {
+	return core.CachedQuery(ctx, r.CachedRepository, "product", productID, func(ctx context.Context) ([]ProductMedia, error) {
+		return r.productMediaRepo.FindByProductID(ctx, productID)
+	})
+}
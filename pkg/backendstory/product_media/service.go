@@ -3,6 +3,7 @@ package productmedia
 import (
 	"context"
 
+	"github.com/ActuallyHello/backendstory/pkg/backendstory/resources"
 	"github.com/ActuallyHello/backendstory/pkg/core"
 )
 
@@ -23,22 +24,49 @@ type ProductMediaService interface {
 type productMediaService struct {
 	core.BaseServiceImpl[ProductMedia]
 	productMediaRepo ProductMediaRepository
+	txManager        core.TxManager
+	fileService      resources.FileService
+	staticFilesPath  string
 }
 
 func NewProductMediaService(
 	productMediaRepo ProductMediaRepository,
+	txManager core.TxManager,
+	fileService resources.FileService,
+	staticFilesPath string,
 ) *productMediaService {
 	return &productMediaService{
 		BaseServiceImpl:  *core.NewBaseServiceImpl(productMediaRepo),
 		productMediaRepo: productMediaRepo,
+		txManager:        txManager,
+		fileService:      fileService,
+		staticFilesPath:  staticFilesPath,
 	}
 }
 
-// Create создает новую ProductMedia с базовой валидацией
+// Create создает новую ProductMedia для уже сохраненного файла.
+// Если запись не создана или транзакция откатывается, файл удаляется с диска
 func (s *productMediaService) Create(ctx context.Context, productMedia ProductMedia) (ProductMedia, error) {
-	created, err := s.GetRepo().Create(ctx, productMedia)
+	var created ProductMedia
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		core.AfterRollback(ctx, func(ctx context.Context) error {
+			return s.fileService.DeleteImage(productMedia.Link, s.staticFilesPath)
+		})
+
+		var err error
+		created, err = s.GetRepo().Create(ctx, productMedia)
+		if err != nil {
+			return core.NewTechnicalError(err, productMediaServiceCode, err.Error())
+		}
+		return nil
+	})
 	if err != nil {
-		return ProductMedia{}, core.NewTechnicalError(err, productMediaServiceCode, err.Error())
+		// транзакция могла не открыться, и действие отката не было зарегистрировано.
+		// Повторное удаление безопасно: отсутствующий файл пропускается
+		if deleteErr := s.fileService.DeleteImage(productMedia.Link, s.staticFilesPath); deleteErr != nil {
+			core.LoggerFromContext(ctx).Error("Failed to delete product media file", "link", productMedia.Link, "err", deleteErr)
+		}
+		return ProductMedia{}, err
 	}
 	return created, nil
 }
@@ -57,13 +85,22 @@ func (s *productMediaService) Update(ctx context.Context, productMedia ProductMe
 	return updated, nil
 }
 
-// Delete удаляет ProductMedia (мягко или полностью)
+// Delete удаляет ProductMedia (мягко или полностью). Файл удаляется с диска только после
+// фиксации транзакции, чтобы при откате запись не ссылалась на удаленный файл
 func (s *productMediaService) Delete(ctx context.Context, productMedia ProductMedia) error {
-	err := s.GetRepo().Delete(ctx, productMedia)
-	if err != nil {
-		return core.NewTechnicalError(err, productMediaServiceCode, err.Error())
-	}
-	return nil
+	return s.txManager.Do(ctx, func(ctx context.Context) error {
+		err := s.GetRepo().Delete(ctx, productMedia)
+		if err != nil {
+			return core.NewTechnicalError(err, productMediaServiceCode, err.Error())
+		}
+
+		if productMedia.Link != "" {
+			core.AfterCommit(ctx, func(ctx context.Context) error {
+				return s.fileService.DeleteImage(productMedia.Link, s.staticFilesPath)
+			})
+		}
+		return nil
+	})
 }
 
 // FindByProductID ищет ProductMedia по product id
@@ -0,0 +1,78 @@
+package productmedia
+
+import (
+	"context"
+	"errors"
+	"mime/multipart"
+	"testing"
+
+	"github.com/ActuallyHello/backendstory/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+var errBeginFailed = errors.New("connection refused")
+
+// failingTxManager не может открыть транзакцию
+type failingTxManager struct{}
+
+func (failingTxManager) Do(context.Context, func(context.Context) error) error {
+	return errBeginFailed
+}
+
+func (failingTxManager) DoWithSettings(context.Context, core.TxSettings, func(context.Context) error) error {
+	return errBeginFailed
+}
+
+// directTxManager выполняет функцию без транзакции
+type directTxManager struct{}
+
+func (directTxManager) Do(ctx context.Context, f func(context.Context) error) error {
+	return f(ctx)
+}
+
+func (directTxManager) DoWithSettings(ctx context.Context, _ core.TxSettings, f func(context.Context) error) error {
+	return f(ctx)
+}
+
+type failingRepository struct {
+	ProductMediaRepository
+}
+
+func (failingRepository) Create(context.Context, ProductMedia) (ProductMedia, error) {
+	return ProductMedia{}, errors.New("duplicate entry")
+}
+
+// recordingFileService запоминает удаленные файлы
+type recordingFileService struct {
+	deleted []string
+}
+
+func (s *recordingFileService) CreateImage(multipart.File, *multipart.FileHeader, string) (string, error) {
+	return "", errors.New("not supported")
+}
+
+func (s *recordingFileService) DeleteImage(mediaPath, _ string) error {
+	s.deleted = append(s.deleted, mediaPath)
+	return nil
+}
+
+func TestCreateDeletesFileWhenTransactionNotStarted(t *testing.T) {
+	files := &recordingFileService{}
+	service := NewProductMediaService(failingRepository{}, failingTxManager{}, files, "/var/static")
+
+	_, err := service.Create(context.Background(), ProductMedia{Link: "/static/images/a.png"})
+
+	assert.ErrorIs(t, err, errBeginFailed)
+	assert.Equal(t, []string{"/static/images/a.png"}, files.deleted)
+}
+
+func TestCreateDeletesFileWhenRecordNotCreated(t *testing.T) {
+	files := &recordingFileService{}
+	service := NewProductMediaService(failingRepository{}, directTxManager{}, files, "/var/static")
+
+	_, err := service.Create(context.Background(), ProductMedia{Link: "/static/images/a.png"})
+
+	var technicalErr *core.TechnicalError
+	assert.ErrorAs(t, err, &technicalErr)
+	assert.Equal(t, []string{"/static/images/a.png"}, files.deleted)
+}
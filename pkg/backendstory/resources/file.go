@@ -37,7 +37,7 @@ func (fs *fileService) CreateImage(file multipart.File, header *multipart.FileHe
 
 	// Проверяем размер файла
 	if header.Size > MaxFileSize {
-		return "", core.NewLogicalError(nil, fileServiceCode, "File too large")
+		return "", core.NewLogicalError(nil, core.FileTooLargeCode, "Размер файла превышает %d МБ", MaxFileSize>>20)
 	}
 
 	// Проверяем тип файла
@@ -49,7 +49,7 @@ func (fs *fileService) CreateImage(file multipart.File, header *multipart.FileHe
 
 	fileType := http.DetectContentType(buffer)
 	if !strings.HasPrefix(fileType, "image/") {
-		return "", core.NewLogicalError(nil, fileServiceCode, "Unsupported file type")
+		return "", core.NewLogicalError(nil, core.FileTypeUnsupportedCode, "Неподдерживаемый тип файла %s, ожидается изображение", fileType)
 	}
 
 	// Возвращаем указатель на начало файла
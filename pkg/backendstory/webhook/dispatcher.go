@@ -0,0 +1,208 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/ActuallyHello/backendstory/pkg/core"
+)
+
+const (
+	// maxRetryDelay предел задержки между повторными попытками доставки
+	maxRetryDelay = time.Hour
+	// claimTimeout время, на которое выбранные доставки скрываются от других экземпляров.
+	// Если экземпляр остановится во время отправки, доставки будут повторены по его истечении
+	claimTimeout = 15 * time.Minute
+)
+
+var errSubscriptionInactive = errors.New("webhook subscription is deleted or inactive")
+
+// Dispatcher периодически отправляет доставки, время попытки которых наступило.
+// Неудачная доставка повторяется с экспоненциальной задержкой, после maxAttempts
+// попыток переводится в статус DeadDeliveryStatus
+type Dispatcher struct {
+	txManager        core.TxManager
+	deliveryRepo     WebhookDeliveryRepository
+	subscriptionRepo WebhookSubscriptionRepository
+	sender           *Sender
+	interval         time.Duration
+	batchSize        int
+	maxAttempts      uint
+	retryDelay       time.Duration
+}
+
+func NewDispatcher(
+	txManager core.TxManager,
+	deliveryRepo WebhookDeliveryRepository,
+	subscriptionRepo WebhookSubscriptionRepository,
+	sender *Sender,
+	interval time.Duration,
+	batchSize int,
+	maxAttempts uint,
+	retryDelay time.Duration,
+) *Dispatcher {
+	return &Dispatcher{
+		txManager:        txManager,
+		deliveryRepo:     deliveryRepo,
+		subscriptionRepo: subscriptionRepo,
+		sender:           sender,
+		interval:         interval,
+		batchSize:        batchSize,
+		maxAttempts:      maxAttempts,
+		retryDelay:       retryDelay,
+	}
+}
+
+// Run отправляет доставки с заданным интервалом до отмены контекста
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				processed, err := d.DispatchPending(ctx)
+				if err != nil {
+					if !errors.Is(err, context.Canceled) {
+						slog.Error("Failed to dispatch webhook deliveries", "err", err)
+					}
+					break
+				}
+				if processed < d.batchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// DispatchPending отправляет одну пачку доставок и возвращает количество обработанных.
+// Доставки резервируются в короткой транзакции, запросы получателям отправляются вне её,
+// а результат записывается второй транзакцией. Так блокировки строк не удерживаются на время
+// HTTP-запросов, а повтор транзакции после взаимной блокировки не отправляет запросы повторно
+func (d *Dispatcher) DispatchPending(ctx context.Context) (int, error) {
+	deliveries, subscriptions, err := d.claim(ctx)
+	if err != nil || len(deliveries) == 0 {
+		return 0, err
+	}
+
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if ok && subscription.Active {
+			d.Attempt(ctx, delivery, subscription)
+		} else {
+			delivery.Status = DeadDeliveryStatus
+			delivery.LastError = errSubscriptionInactive.Error()
+		}
+	}
+
+	err = d.txManager.Do(ctx, func(ctx context.Context) error {
+		for _, delivery := range deliveries {
+			if _, err := d.deliveryRepo.Update(ctx, delivery); err != nil {
+				var conflictErr *core.ConflictError
+				if errors.As(err, &conflictErr) {
+					// резерв истек, и доставку уже обработал другой экземпляр
+					slog.Warn("Webhook delivery was claimed by another dispatcher", "id", delivery.ID)
+					continue
+				}
+				return err
+			}
+		}
+		return nil
+	})
+	return len(deliveries), err
+}
+
+// claim выбирает доставки, время попытки которых наступило, и переносит их следующую попытку
+// на claimTimeout вперед, чтобы другие экземпляры приложения не взяли их, пока идет отправка.
+// Возвращает зарезервированные доставки и их подписки по ID
+func (d *Dispatcher) claim(ctx context.Context) ([]WebhookDelivery, map[uint]WebhookSubscription, error) {
+	var claimed []WebhookDelivery
+	var subscriptionsByID map[uint]WebhookSubscription
+	err := d.txManager.Do(ctx, func(ctx context.Context) error {
+		claimed = nil
+		now := time.Now()
+		deliveries, err := d.deliveryRepo.FindDue(ctx, now, d.batchSize)
+		if err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		subscriptionIDs := make([]uint, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			delivery.NextAttemptAt = now.Add(claimTimeout)
+			delivery, err := d.deliveryRepo.Update(ctx, delivery)
+			if err != nil {
+				return err
+			}
+			claimed = append(claimed, delivery)
+			subscriptionIDs = append(subscriptionIDs, delivery.SubscriptionID)
+		}
+
+		subscriptions, err := d.subscriptionRepo.FindByIDs(ctx, subscriptionIDs)
+		if err != nil {
+			return err
+		}
+		subscriptionsByID = make(map[uint]WebhookSubscription, len(subscriptions))
+		for _, subscription := range subscriptions {
+			subscriptionsByID[subscription.ID] = subscription
+		}
+		return nil
+	})
+	return claimed, subscriptionsByID, err
+}
+
+// Attempt выполняет одну попытку доставки и обновляет её состояние по результату
+func (d *Dispatcher) Attempt(ctx context.Context, delivery *WebhookDelivery, subscription WebhookSubscription) {
+	statusCode, err := d.sender.Send(ctx, subscription, *delivery)
+
+	delivery.Attempts++
+	delivery.ResponseCode = sql.NullInt32{Int32: int32(statusCode), Valid: statusCode > 0}
+	if err == nil {
+		delivery.Status = DeliveredDeliveryStatus
+		delivery.DeliveredAt = sql.NullTime{Time: time.Now(), Valid: true}
+		delivery.LastError = ""
+		return
+	}
+
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= d.maxAttempts {
+		delivery.Status = DeadDeliveryStatus
+		slog.Error("Webhook was not delivered and moved to dead status",
+			"id", delivery.ID,
+			"subscription_id", delivery.SubscriptionID,
+			"event_type", delivery.EventType,
+			"attempts", delivery.Attempts,
+			"err", err,
+		)
+		return
+	}
+	delivery.Status = PendingDeliveryStatus
+	delivery.NextAttemptAt = time.Now().Add(d.nextRetryDelay(delivery.Attempts))
+	slog.Warn("Webhook delivery failed, will retry",
+		"id", delivery.ID,
+		"subscription_id", delivery.SubscriptionID,
+		"event_type", delivery.EventType,
+		"attempts", delivery.Attempts,
+		"next_attempt_at", delivery.NextAttemptAt,
+		"err", err,
+	)
+}
+
+// nextRetryDelay экспоненциальная задержка: retryDelay * 2^(attempts-1)
+func (d *Dispatcher) nextRetryDelay(attempts uint) time.Duration {
+	delay := d.retryDelay
+	for i := uint(1); i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
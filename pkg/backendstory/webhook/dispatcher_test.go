@@ -0,0 +1,412 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ActuallyHello/backendstory/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+// memoryDeliveryRepository журнал доставок в памяти
+type memoryDeliveryRepository struct {
+	WebhookDeliveryRepository
+
+	rows map[uint]WebhookDelivery
+}
+
+func (r *memoryDeliveryRepository) FindDue(_ context.Context, now time.Time, limit int) ([]WebhookDelivery, error) {
+	var due []WebhookDelivery
+	for _, id := range slices.Sorted(maps.Keys(r.rows)) {
+		delivery := r.rows[id]
+		if delivery.Status == PendingDeliveryStatus && !delivery.NextAttemptAt.After(now) && len(due) < limit {
+			due = append(due, delivery)
+		}
+	}
+	return due, nil
+}
+
+func (r *memoryDeliveryRepository) FindByID(_ context.Context, id uint) (WebhookDelivery, error) {
+	delivery, ok := r.rows[id]
+	if !ok {
+		return delivery, core.NewNotFoundError(core.RecordNotFoundCode, "%s с ИД %d не существует", delivery.LocalTableName(), id)
+	}
+	return delivery, nil
+}
+
+func (r *memoryDeliveryRepository) Update(_ context.Context, delivery WebhookDelivery) (WebhookDelivery, error) {
+	if r.rows[delivery.ID].Version != delivery.Version {
+		return delivery, core.NewConflictError(nil, core.RecordVersionConflictCode, "version conflict")
+	}
+	delivery.Version++
+	r.rows[delivery.ID] = delivery
+	return delivery, nil
+}
+
+// memorySubscriptionRepository подписки в памяти
+type memorySubscriptionRepository struct {
+	WebhookSubscriptionRepository
+
+	rows map[uint]WebhookSubscription
+}
+
+func (r *memorySubscriptionRepository) FindByID(_ context.Context, id uint) (WebhookSubscription, error) {
+	subscription, ok := r.rows[id]
+	if !ok {
+		return subscription, core.NewNotFoundError(core.RecordNotFoundCode, "%s с ИД %d не существует", subscription.LocalTableName(), id)
+	}
+	return subscription, nil
+}
+
+func (r *memorySubscriptionRepository) FindByIDs(_ context.Context, ids []uint) ([]WebhookSubscription, error) {
+	var subscriptions []WebhookSubscription
+	for _, id := range ids {
+		if subscription, ok := r.rows[id]; ok {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	return subscriptions, nil
+}
+
+// fakeTxManager выполняет функцию над журналом доставок в памяти. Первые deadlocks попыток
+// каждой транзакции завершаются взаимной блокировкой: изменения откатываются, и функция
+// выполняется повторно, как это делает TxManager
+type fakeTxManager struct {
+	repo      *memoryDeliveryRepository
+	deadlocks int
+	active    atomic.Bool
+}
+
+func (m *fakeTxManager) Do(ctx context.Context, f func(context.Context) error) error {
+	return m.DoWithSettings(ctx, core.DefaultGormTxSettings(), f)
+}
+
+func (m *fakeTxManager) DoWithSettings(ctx context.Context, _ core.TxSettings, f func(context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		snapshot := maps.Clone(m.repo.rows)
+		m.active.Store(true)
+		err := f(ctx)
+		m.active.Store(false)
+		if err == nil && attempt <= m.deadlocks {
+			m.repo.rows = snapshot
+			continue
+		}
+		if err != nil {
+			m.repo.rows = snapshot
+		}
+		return err
+	}
+}
+
+// receivedRequest запрос, полученный тестовым получателем
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+	// inTx запрос пришел, пока была открыта транзакция
+	inTx bool
+}
+
+// receiver тестовый получатель вебхуков, отвечающий кодами из statuses по очереди
+type receiver struct {
+	mu       sync.Mutex
+	server   *httptest.Server
+	statuses []int
+	requests []receivedRequest
+}
+
+func newReceiver(t *testing.T, txManager *fakeTxManager, statuses ...int) *receiver {
+	rcv := &receiver{statuses: statuses}
+	rcv.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		rcv.mu.Lock()
+		rcv.requests = append(rcv.requests, receivedRequest{header: r.Header.Clone(), body: body, inTx: txManager.active.Load()})
+		status := http.StatusOK
+		if len(rcv.statuses) > 0 {
+			status, rcv.statuses = rcv.statuses[0], rcv.statuses[1:]
+		}
+		rcv.mu.Unlock()
+
+		w.WriteHeader(status)
+		if status >= 300 {
+			io.WriteString(w, "receiver failure")
+		}
+	}))
+	t.Cleanup(rcv.server.Close)
+	return rcv
+}
+
+func (r *receiver) received() []receivedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.requests)
+}
+
+type dispatcherFixture struct {
+	deliveries    *memoryDeliveryRepository
+	subscriptions *memorySubscriptionRepository
+	txManager     *fakeTxManager
+	dispatcher    *Dispatcher
+}
+
+func newDispatcherFixture(maxAttempts uint, retryDelay time.Duration) *dispatcherFixture {
+	deliveries := &memoryDeliveryRepository{rows: make(map[uint]WebhookDelivery)}
+	subscriptions := &memorySubscriptionRepository{rows: make(map[uint]WebhookSubscription)}
+	txManager := &fakeTxManager{repo: deliveries}
+	return &dispatcherFixture{
+		deliveries:    deliveries,
+		subscriptions: subscriptions,
+		txManager:     txManager,
+		dispatcher: NewDispatcher(
+			txManager, deliveries, subscriptions,
+			NewSender(&http.Client{Timeout: time.Second}),
+			time.Second, 10, maxAttempts, retryDelay,
+		),
+	}
+}
+
+func (f *dispatcherFixture) subscribe(id uint, url string, active bool) {
+	f.subscriptions.rows[id] = WebhookSubscription{
+		Base:       core.Base{ID: id, Version: 1},
+		URL:        url,
+		Secret:     testSecret,
+		EventTypes: AllEventTypes,
+		Active:     active,
+	}
+}
+
+func (f *dispatcherFixture) enqueue(id, subscriptionID uint) {
+	f.deliveries.rows[id] = WebhookDelivery{
+		Base:           core.Base{ID: id, Version: 1},
+		SubscriptionID: subscriptionID,
+		EventID:        100 + id,
+		EventType:      "order.created",
+		Payload:        `{"id":` + strconv.FormatUint(uint64(100+id), 10) + `}`,
+		Status:         PendingDeliveryStatus,
+		NextAttemptAt:  time.Now().Add(-time.Second),
+	}
+}
+
+// makeDue переносит следующую попытку доставки на текущий момент
+func (f *dispatcherFixture) makeDue(id uint) {
+	delivery := f.deliveries.rows[id]
+	delivery.NextAttemptAt = time.Now().Add(-time.Second)
+	f.deliveries.rows[id] = delivery
+}
+
+func TestDispatcherSendsSignedRequest(t *testing.T) {
+	fixture := newDispatcherFixture(3, time.Minute)
+	rcv := newReceiver(t, fixture.txManager)
+	fixture.subscribe(1, rcv.server.URL, true)
+	fixture.enqueue(1, 1)
+
+	processed, err := fixture.dispatcher.DispatchPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, processed)
+
+	requests := rcv.received()
+	require.Len(t, requests, 1)
+	request := requests[0]
+	assert.False(t, request.inTx, "webhook sent inside transaction")
+	assert.JSONEq(t, `{"id":101}`, string(request.body))
+	assert.Equal(t, "application/json", request.header.Get("Content-Type"))
+	assert.Equal(t, "order.created", request.header.Get(EventHeader))
+	assert.Equal(t, "1", request.header.Get(DeliveryHeader))
+	assert.Equal(t, "101", request.header.Get(IdempotencyKeyHeader))
+
+	timestamp, err := strconv.ParseInt(request.header.Get(TimestampHeader), 10, 64)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), time.Unix(timestamp, 0), 5*time.Second)
+	signature := request.header.Get(SignatureHeader)
+	assert.True(t, VerifySignature(testSecret, timestamp, request.body, signature))
+	assert.False(t, VerifySignature("another-secret-value", timestamp, request.body, signature))
+	assert.False(t, VerifySignature(testSecret, timestamp+1, request.body, signature))
+
+	delivery := fixture.deliveries.rows[1]
+	assert.Equal(t, DeliveredDeliveryStatus, delivery.Status)
+	assert.Equal(t, uint(1), delivery.Attempts)
+	assert.Equal(t, int32(http.StatusOK), delivery.ResponseCode.Int32)
+	assert.True(t, delivery.DeliveredAt.Valid)
+	assert.Empty(t, delivery.LastError)
+}
+
+func TestDispatcherRetriesWithBackoff(t *testing.T) {
+	fixture := newDispatcherFixture(5, time.Minute)
+	rcv := newReceiver(t, fixture.txManager, http.StatusInternalServerError, http.StatusServiceUnavailable)
+	fixture.subscribe(1, rcv.server.URL, true)
+	fixture.enqueue(1, 1)
+	ctx := context.Background()
+
+	before := time.Now()
+	_, err := fixture.dispatcher.DispatchPending(ctx)
+	require.NoError(t, err)
+
+	delivery := fixture.deliveries.rows[1]
+	assert.Equal(t, PendingDeliveryStatus, delivery.Status)
+	assert.Equal(t, uint(1), delivery.Attempts)
+	assert.Equal(t, int32(http.StatusInternalServerError), delivery.ResponseCode.Int32)
+	assert.Contains(t, delivery.LastError, "status 500: receiver failure")
+	assert.WithinDuration(t, before.Add(time.Minute), delivery.NextAttemptAt, time.Second)
+
+	// до наступления времени повтора доставка не отправляется
+	processed, err := fixture.dispatcher.DispatchPending(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, processed)
+
+	fixture.makeDue(1)
+	before = time.Now()
+	_, err = fixture.dispatcher.DispatchPending(ctx)
+	require.NoError(t, err)
+
+	delivery = fixture.deliveries.rows[1]
+	assert.Equal(t, uint(2), delivery.Attempts)
+	assert.Equal(t, int32(http.StatusServiceUnavailable), delivery.ResponseCode.Int32)
+	assert.WithinDuration(t, before.Add(2*time.Minute), delivery.NextAttemptAt, time.Second)
+
+	fixture.makeDue(1)
+	_, err = fixture.dispatcher.DispatchPending(ctx)
+	require.NoError(t, err)
+
+	delivery = fixture.deliveries.rows[1]
+	assert.Equal(t, DeliveredDeliveryStatus, delivery.Status)
+	assert.Equal(t, uint(3), delivery.Attempts)
+	assert.Empty(t, delivery.LastError)
+
+	// все попытки несут один ключ идемпотентности
+	requests := rcv.received()
+	require.Len(t, requests, 3)
+	for _, request := range requests {
+		assert.Equal(t, "101", request.header.Get(IdempotencyKeyHeader))
+	}
+}
+
+func TestDispatcherMarksDeliveryDeadAfterMaxAttempts(t *testing.T) {
+	fixture := newDispatcherFixture(2, time.Minute)
+	rcv := newReceiver(t, fixture.txManager, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+	fixture.subscribe(1, rcv.server.URL, true)
+	fixture.enqueue(1, 1)
+	ctx := context.Background()
+
+	_, err := fixture.dispatcher.DispatchPending(ctx)
+	require.NoError(t, err)
+	fixture.makeDue(1)
+	_, err = fixture.dispatcher.DispatchPending(ctx)
+	require.NoError(t, err)
+
+	delivery := fixture.deliveries.rows[1]
+	assert.Equal(t, DeadDeliveryStatus, delivery.Status)
+	assert.Equal(t, uint(2), delivery.Attempts)
+
+	fixture.makeDue(1)
+	processed, err := fixture.dispatcher.DispatchPending(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, processed)
+	assert.Len(t, rcv.received(), 2)
+}
+
+func TestDispatcherMarksDeliveriesOfInactiveSubscriptionsDead(t *testing.T) {
+	fixture := newDispatcherFixture(3, time.Minute)
+	rcv := newReceiver(t, fixture.txManager)
+	fixture.subscribe(1, rcv.server.URL, false)
+	fixture.enqueue(1, 1)
+	// подписка 2 удалена
+	fixture.enqueue(2, 2)
+
+	processed, err := fixture.dispatcher.DispatchPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, processed)
+	assert.Empty(t, rcv.received())
+	for _, delivery := range fixture.deliveries.rows {
+		assert.Equal(t, DeadDeliveryStatus, delivery.Status)
+		assert.Equal(t, errSubscriptionInactive.Error(), delivery.LastError)
+	}
+}
+
+func TestDispatcherDoesNotResendOnTransactionRetry(t *testing.T) {
+	fixture := newDispatcherFixture(3, time.Minute)
+	// и резервирование, и запись результата откатываются взаимной блокировкой и выполняются повторно
+	fixture.txManager.deadlocks = 1
+	rcv := newReceiver(t, fixture.txManager)
+	fixture.subscribe(1, rcv.server.URL, true)
+	fixture.enqueue(1, 1)
+	fixture.enqueue(2, 1)
+
+	processed, err := fixture.dispatcher.DispatchPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, processed)
+
+	requests := rcv.received()
+	require.Len(t, requests, 2)
+	for _, request := range requests {
+		assert.False(t, request.inTx, "webhook sent inside transaction")
+	}
+	for _, delivery := range fixture.deliveries.rows {
+		assert.Equal(t, DeliveredDeliveryStatus, delivery.Status)
+		assert.Equal(t, uint(1), delivery.Attempts)
+	}
+}
+
+func TestDispatcherNextRetryDelay(t *testing.T) {
+	dispatcher := NewDispatcher(nil, nil, nil, nil, time.Second, 10, 10, 30*time.Second)
+
+	assert.Equal(t, 30*time.Second, dispatcher.nextRetryDelay(1))
+	assert.Equal(t, time.Minute, dispatcher.nextRetryDelay(2))
+	assert.Equal(t, 4*time.Minute, dispatcher.nextRetryDelay(4))
+	assert.Equal(t, maxRetryDelay, dispatcher.nextRetryDelay(12))
+}
+
+func TestRedeliverResetsAttempts(t *testing.T) {
+	fixture := newDispatcherFixture(2, time.Minute)
+	rcv := newReceiver(t, fixture.txManager)
+	fixture.subscribe(1, rcv.server.URL, true)
+	fixture.enqueue(1, 1)
+	delivery := fixture.deliveries.rows[1]
+	delivery.Status = DeadDeliveryStatus
+	delivery.Attempts = 2
+	fixture.deliveries.rows[1] = delivery
+
+	service := NewWebhookDeliveryService(fixture.deliveries, fixture.subscriptions, fixture.txManager, fixture.dispatcher)
+	redelivered, err := service.Redeliver(context.Background(), 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, DeliveredDeliveryStatus, redelivered.Status)
+	assert.Equal(t, uint(1), redelivered.Attempts)
+	assert.Equal(t, redelivered, fixture.deliveries.rows[1])
+	requests := rcv.received()
+	require.Len(t, requests, 1)
+	assert.False(t, requests[0].inTx, "webhook sent inside transaction")
+}
+
+func TestRedeliverRejectsInactiveSubscription(t *testing.T) {
+	fixture := newDispatcherFixture(2, time.Minute)
+	rcv := newReceiver(t, fixture.txManager)
+	fixture.subscribe(1, rcv.server.URL, false)
+	fixture.enqueue(1, 1)
+
+	service := NewWebhookDeliveryService(fixture.deliveries, fixture.subscriptions, fixture.txManager, fixture.dispatcher)
+	_, err := service.Redeliver(context.Background(), 1)
+
+	var logicalErr *core.LogicalError
+	require.ErrorAs(t, err, &logicalErr)
+	assert.Equal(t, core.WebhookSubscriptionInactiveCode, logicalErr.Code)
+	assert.Empty(t, rcv.received())
+}
+
+func TestSubscriptionSecretIsNotSerialized(t *testing.T) {
+	data, err := json.Marshal(WebhookSubscription{URL: "https://example.com", Secret: testSecret})
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), testSecret)
+}
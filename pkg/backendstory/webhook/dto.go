@@ -0,0 +1,101 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookSubscriptionCreateRequest represents request for creating webhook subscription.
+// If secret is empty, it is generated and returned once in the create response
+// @Name WebhookSubscriptionCreateRequest
+type WebhookSubscriptionCreateRequest struct {
+	URL        string   `json:"url" validate:"required,url,max=2048"`
+	Secret     string   `json:"secret" validate:"omitempty,min=16,max=255"`
+	EventTypes []string `json:"event_types" validate:"required,min=1,dive,required,max=128,excludesall=0x2C"`
+	Active     *bool    `json:"active"`
+}
+
+// WebhookSubscriptionUpdateRequest represents request for updating webhook subscription
+// @Name WebhookSubscriptionUpdateRequest
+type WebhookSubscriptionUpdateRequest struct {
+	ID         uint     `json:"id" validate:"required"`
+	URL        string   `json:"url" validate:"omitempty,url,max=2048"`
+	Secret     string   `json:"secret" validate:"omitempty,min=16,max=255"`
+	EventTypes []string `json:"event_types" validate:"omitempty,min=1,dive,required,max=128,excludesall=0x2C"`
+	Active     *bool    `json:"active"`
+}
+
+// WebhookSubscriptionDTO represents webhook subscription data transfer object. Secret is never returned
+// @Name WebhookSubscriptionDTO
+type WebhookSubscriptionDTO struct {
+	ID         uint      `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Active     bool      `json:"active"`
+}
+
+// WebhookSubscriptionCreatedDTO represents created webhook subscription with its signing secret.
+// This is the only response that contains the secret
+// @Name WebhookSubscriptionCreatedDTO
+type WebhookSubscriptionCreatedDTO struct {
+	WebhookSubscriptionDTO
+	Secret string `json:"secret"`
+}
+
+func ToWebhookSubscriptionDTO(subscription WebhookSubscription) WebhookSubscriptionDTO {
+	return WebhookSubscriptionDTO{
+		ID:         subscription.ID,
+		CreatedAt:  subscription.CreatedAt,
+		UpdatedAt:  subscription.UpdatedAt,
+		URL:        subscription.URL,
+		EventTypes: subscription.EventTypeList(),
+		Active:     subscription.Active,
+	}
+}
+
+// WebhookDeliveryDTO represents webhook delivery log entry
+// @Name WebhookDeliveryDTO
+type WebhookDeliveryDTO struct {
+	ID             uint            `json:"id"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	SubscriptionID uint            `json:"subscription_id"`
+	EventID        uint            `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	Status         string          `json:"status"`
+	Attempts       uint            `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	ResponseCode   *int32          `json:"response_code"`
+	LastError      string          `json:"last_error"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+}
+
+func ToWebhookDeliveryDTO(delivery WebhookDelivery) WebhookDeliveryDTO {
+	var responseCode *int32
+	if delivery.ResponseCode.Valid {
+		responseCode = &delivery.ResponseCode.Int32
+	}
+	var deliveredAt *time.Time
+	if delivery.DeliveredAt.Valid {
+		deliveredAt = &delivery.DeliveredAt.Time
+	}
+
+	return WebhookDeliveryDTO{
+		ID:             delivery.ID,
+		CreatedAt:      delivery.CreatedAt,
+		UpdatedAt:      delivery.UpdatedAt,
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        json.RawMessage(delivery.Payload),
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		ResponseCode:   responseCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    deliveredAt,
+	}
+}
@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ActuallyHello/backendstory/pkg/core"
+)
+
+const (
+	// AllEventTypes подписка на все типы событий
+	AllEventTypes = "*"
+
+	eventTypesSeparator = ","
+
+	// secretSize длина генерируемого секрета подписи в байтах
+	secretSize = 32
+)
+
+const (
+	// PendingDeliveryStatus доставка ожидает отправки или повторной попытки
+	PendingDeliveryStatus = "pending"
+	// DeliveredDeliveryStatus получатель подтвердил доставку ответом 2xx
+	DeliveredDeliveryStatus = "delivered"
+	// DeadDeliveryStatus доставка не удалась за отведенное число попыток
+	DeadDeliveryStatus = "dead"
+)
+
+type WebhookSubscription struct {
+	core.Base
+
+	URL string `gorm:"column:URL"`
+	// Secret ключ подписи HMAC-SHA256 тела запроса. Не сериализуется в JSON,
+	// чтобы не попасть в журнал аудита и ответы API
+	Secret string `gorm:"column:SECRET" json:"-"`
+	// EventTypes типы событий через запятую либо AllEventTypes
+	EventTypes string `gorm:"column:EVENTTYPES"`
+	Active     bool   `gorm:"column:ACTIVE"`
+}
+
+func (WebhookSubscription) TableName() string {
+	return "WEBHOOKSUBSCRIPTION"
+}
+
+func (WebhookSubscription) LocalTableName() string {
+	return "Подписка на события"
+}
+
+// Subscribes проверяет, подписан ли получатель на тип события
+func (s WebhookSubscription) Subscribes(eventType string) bool {
+	eventTypes := s.EventTypeList()
+	return slices.Contains(eventTypes, AllEventTypes) || slices.Contains(eventTypes, eventType)
+}
+
+func (s WebhookSubscription) EventTypeList() []string {
+	var eventTypes []string
+	for _, eventType := range strings.Split(s.EventTypes, eventTypesSeparator) {
+		if eventType = strings.TrimSpace(eventType); eventType != "" {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+	return eventTypes
+}
+
+func JoinEventTypes(eventTypes []string) string {
+	return strings.Join(eventTypes, eventTypesSeparator)
+}
+
+// NewSecret генерирует случайный секрет подписи для подписки, созданной без секрета
+func NewSecret() (string, error) {
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+var webhookSubscriptionSearchFields = core.NewSearchFields(
+	core.SearchField{Name: "url", Column: "URL", Type: core.FieldString, Sortable: true},
+	core.SearchField{Name: "event_types", Column: "EVENTTYPES", Type: core.FieldString},
+	core.SearchField{Name: "active", Column: "ACTIVE", Type: core.FieldBool, Sortable: true},
+)
+
+func (WebhookSubscription) SearchFields() *core.SearchFields {
+	return webhookSubscriptionSearchFields
+}
+
+type WebhookDelivery struct {
+	core.Base
+
+	SubscriptionID uint `gorm:"column:SUBSCRIPTIONID"`
+	// EventID ID события в OUTBOX, передается получателю как ключ идемпотентности
+	EventID   uint   `gorm:"column:EVENTID"`
+	EventType string `gorm:"column:EVENTTYPE"`
+	// Payload тело запроса, фиксируется при постановке в очередь
+	Payload       string        `gorm:"column:PAYLOAD"`
+	Status        string        `gorm:"column:STATUS"`
+	Attempts      uint          `gorm:"column:ATTEMPTS"`
+	NextAttemptAt time.Time     `gorm:"column:NEXTATTEMPTAT"`
+	ResponseCode  sql.NullInt32 `gorm:"column:RESPONSECODE"`
+	LastError     string        `gorm:"column:LASTERROR"`
+	DeliveredAt   sql.NullTime  `gorm:"column:DELIVEREDAT"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "WEBHOOKDELIVERY"
+}
+
+func (WebhookDelivery) LocalTableName() string {
+	return "Доставка события"
+}
+
+// Unaudited журнал доставок сам является журналом и не попадает в аудит
+func (WebhookDelivery) Unaudited() {}
+
+var webhookDeliverySearchFields = core.NewSearchFields(
+	core.SearchField{Name: "subscription_id", Column: "SUBSCRIPTIONID", Type: core.FieldUint, Sortable: true},
+	core.SearchField{Name: "event_id", Column: "EVENTID", Type: core.FieldUint, Sortable: true},
+	core.SearchField{Name: "event_type", Column: "EVENTTYPE", Type: core.FieldString, Sortable: true},
+	core.SearchField{Name: "status", Column: "STATUS", Type: core.FieldString, Sortable: true},
+	core.SearchField{Name: "attempts", Column: "ATTEMPTS", Type: core.FieldUint, Sortable: true},
+	core.SearchField{Name: "response_code", Column: "RESPONSECODE", Type: core.FieldUint, Sortable: true, Nullable: true},
+	core.SearchField{Name: "created_at", Column: "CREATEDAT", Type: core.FieldTime, Sortable: true},
+)
+
+func (WebhookDelivery) SearchFields() *core.SearchFields {
+	return webhookDeliverySearchFields
+}
@@ -0,0 +1,357 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ActuallyHello/backendstory/pkg/core"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	webhookHandlerCode = "WEBHOOK_HANDLER"
+)
+
+type WebhookHandler struct {
+	validate            *validator.Validate
+	subscriptionService WebhookSubscriptionService
+	deliveryService     WebhookDeliveryService
+	crud                *core.CrudHandler[WebhookSubscription, WebhookSubscriptionDTO]
+}
+
+func NewWebhookHandler(
+	subscriptionService WebhookSubscriptionService,
+	deliveryService WebhookDeliveryService,
+) *WebhookHandler {
+	validate := core.DefaultValidator()
+	return &WebhookHandler{
+		validate:            validate,
+		subscriptionService: subscriptionService,
+		deliveryService:     deliveryService,
+		crud:                core.NewCrudHandler(webhookHandlerCode, validate, subscriptionService, core.MapWith(ToWebhookSubscriptionDTO)),
+	}
+}
+
+// Create создает подписку на события
+// @Summary Создать подписку на события
+// @Description Регистрирует адрес, на который POST-запросом отправляются события указанных типов ("*" - все типы). Тело запроса подписывается секретом подписки: заголовок X-Webhook-Signature содержит sha256=HMAC-SHA256 от строки "<X-Webhook-Timestamp>.<тело>"
+// @Description Если секрет не указан, он генерируется. Секрет возвращается только в ответе на создание и больше не выдается
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body WebhookSubscriptionCreateRequest true "Данные для создания подписки"
+// @Success 201 {object} WebhookSubscriptionCreatedDTO "Созданная подписка с секретом подписи"
+// @Header 201 {string} ETag "Версия записи"
+// @Failure 400 {object} core.ErrorResponse "Ошибка валидации"
+// @Failure 401 {object} core.ErrorResponse "Не авторизован"
+// @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
+// @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /webhooks [post]
+// @Id createWebhook
+func (h *WebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req WebhookSubscriptionCreateRequest
+	if err := core.DecodeJSON(r, &req); err != nil {
+		core.HandleError(w, r, core.NewTechnicalError(err, webhookHandlerCode, err.Error()))
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		details := core.CollectValidationDetails(r.Context(), err)
+		core.HandleValidationError(w, r, core.NewLogicalError(err, webhookHandlerCode, err.Error()), details)
+		return
+	}
+
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		if secret, err = NewSecret(); err != nil {
+			core.HandleError(w, r, core.NewTechnicalError(err, webhookHandlerCode, "Ошибка при генерации секрета подписки"))
+			return
+		}
+	}
+
+	subscription := WebhookSubscription{
+		URL:        req.URL,
+		Secret:     secret,
+		EventTypes: JoinEventTypes(req.EventTypes),
+		Active:     active,
+	}
+	subscription, err := h.subscriptionService.Create(ctx, subscription)
+	if err != nil {
+		core.HandleError(w, r, err)
+		return
+	}
+
+	core.SetETag(w, subscription)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(WebhookSubscriptionCreatedDTO{
+		WebhookSubscriptionDTO: ToWebhookSubscriptionDTO(subscription),
+		Secret:                 subscription.Secret,
+	})
+}
+
+// Update обновляет подписку на события
+// @Summary Обновить подписку на события
+// @Description Обновляет адрес, секрет, типы событий или признак активности подписки. Незаполненные поля не изменяются
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body WebhookSubscriptionUpdateRequest true "Данные для обновления подписки"
+// @Param If-Match header string false "Версия записи из ETag"
+// @Success 200 {object} WebhookSubscriptionDTO "Обновленная подписка"
+// @Header 200 {string} ETag "Версия записи"
+// @Failure 400 {object} core.ErrorResponse "Ошибка валидации"
+// @Failure 401 {object} core.ErrorResponse "Не авторизован"
+// @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} core.ErrorResponse "Подписка не найдена"
+// @Failure 409 {object} core.ErrorResponse "Подписка изменена другим пользователем"
+// @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /webhooks [patch]
+// @Id updateWebhook
+func (h *WebhookHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req WebhookSubscriptionUpdateRequest
+	if err := core.DecodeJSON(r, &req); err != nil {
+		core.HandleError(w, r, core.NewTechnicalError(err, webhookHandlerCode, err.Error()))
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		details := core.CollectValidationDetails(r.Context(), err)
+		core.HandleValidationError(w, r, core.NewLogicalError(err, webhookHandlerCode, err.Error()), details)
+		return
+	}
+
+	subscription, err := h.subscriptionService.GetByID(ctx, req.ID)
+	if err != nil {
+		core.HandleError(w, r, err)
+		return
+	}
+	if err := core.ApplyIfMatch(r, &subscription); err != nil {
+		core.HandleError(w, r, err)
+		return
+	}
+
+	if req.URL != "" {
+		subscription.URL = req.URL
+	}
+	if req.Secret != "" {
+		subscription.Secret = req.Secret
+	}
+	if len(req.EventTypes) > 0 {
+		subscription.EventTypes = JoinEventTypes(req.EventTypes)
+	}
+	if req.Active != nil {
+		subscription.Active = *req.Active
+	}
+	subscription, err = h.subscriptionService.Update(ctx, subscription)
+	if err != nil {
+		core.HandleError(w, r, err)
+		return
+	}
+
+	h.crud.WriteEntity(w, r, http.StatusOK, subscription)
+}
+
+// GetAll возвращает подписки на события
+// @Summary Получить подписки на события
+// @Description Возвращает подписки на события постранично, с фильтрацией и сортировкой из строки запроса
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param filter query string false "Условия через ';', например active==true"
+// @Param sort query string false "Поля сортировки через ',', префикс '-' - по убыванию, например url"
+// @Param limit query int false "Размер страницы" default(20)
+// @Param offset query int false "Смещение"
+// @Param after query string false "Курсор следующей страницы"
+// @Param before query string false "Курсор предыдущей страницы"
+// @Success 200 {object} core.Page[WebhookSubscriptionDTO] "Список подписок"
+// @Failure 400 {object} core.ErrorResponse "Некорректные параметры фильтра"
+// @Failure 401 {object} core.ErrorResponse "Не авторизован"
+// @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
+// @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /webhooks [get]
+// @Id getWebhookAll
+func (h *WebhookHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	h.crud.GetAll(w, r)
+}
+
+// GetById возвращает подписку на события по ID
+// @Summary Получить подписку на события по ID
+// @Description Возвращает подписку на события по указанному идентификатору
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID подписки"
+// @Success 200 {object} WebhookSubscriptionDTO "Подписка"
+// @Header 200 {string} ETag "Версия записи"
+// @Failure 400 {object} core.ErrorResponse "Неверный ID"
+// @Failure 401 {object} core.ErrorResponse "Не авторизован"
+// @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} core.ErrorResponse "Подписка не найдена"
+// @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /webhooks/{id} [get]
+// @Id getWebhookById
+func (h *WebhookHandler) GetById(w http.ResponseWriter, r *http.Request) {
+	h.crud.GetById(w, r)
+}
+
+// GetWithSearchCriteria выполняет поиск подписок на события по критериям
+// @Summary Поиск подписок на события
+// @Description Выполняет поиск подписок на события по заданным критериям
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body core.SearchCriteria true "Критерии поиска"
+// @Success 200 {object} core.Page[WebhookSubscriptionDTO] "Список найденных подписок"
+// @Failure 400 {object} core.ErrorResponse "Ошибка валидации"
+// @Failure 401 {object} core.ErrorResponse "Не авторизован"
+// @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
+// @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /webhooks/search [post]
+// @Id searchWebhook
+func (h *WebhookHandler) GetWithSearchCriteria(w http.ResponseWriter, r *http.Request) {
+	h.crud.GetWithSearchCriteria(w, r)
+}
+
+// Delete удаляет подписку на события
+// @Summary Удалить подписку на события
+// @Description Удаляет подписку на события по указанному идентификатору. Ожидающие доставки по ней больше не отправляются
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID подписки"
+// @Success 204 "Успешно удалено"
+// @Failure 400 {object} core.ErrorResponse "Неверный ID"
+// @Failure 401 {object} core.ErrorResponse "Не авторизован"
+// @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} core.ErrorResponse "Подписка не найдена"
+// @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /webhooks/{id} [delete]
+// @Id deleteWebhook
+func (h *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	h.crud.Delete(w, r)
+}
+
+// GetDeliveries возвращает журнал доставок
+// @Summary Получить журнал доставок
+// @Description Возвращает доставки событий подпискам постранично, с фильтрацией и сортировкой из строки запроса
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param filter query string false "Условия через ';', например subscription_id==3;status==dead"
+// @Param sort query string false "Поля сортировки через ',', префикс '-' - по убыванию, например -created_at"
+// @Param limit query int false "Размер страницы" default(20)
+// @Param offset query int false "Смещение"
+// @Param after query string false "Курсор следующей страницы"
+// @Param before query string false "Курсор предыдущей страницы"
+// @Success 200 {object} core.Page[WebhookDeliveryDTO] "Журнал доставок"
+// @Failure 400 {object} core.ErrorResponse "Некорректные параметры фильтра"
+// @Failure 401 {object} core.ErrorResponse "Не авторизован"
+// @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
+// @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /webhooks/deliveries [get]
+// @Id getWebhookDeliveries
+func (h *WebhookHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	criteria, err := core.ParseSearchQuery(r.URL.RawQuery)
+	if err != nil {
+		core.HandleError(w, r, err)
+		return
+	}
+
+	page, err := h.deliveryService.GetPageWithSearchCriteria(r.Context(), criteria)
+	if err != nil {
+		core.HandleError(w, r, err)
+		return
+	}
+
+	dtos := make([]WebhookDeliveryDTO, 0, len(page.Items))
+	for _, delivery := range page.Items {
+		dtos = append(dtos, ToWebhookDeliveryDTO(delivery))
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(core.MapPage(page, dtos))
+}
+
+// GetDeliveryById возвращает доставку по ID
+// @Summary Получить доставку по ID
+// @Description Возвращает запись журнала доставок по указанному идентификатору
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID доставки"
+// @Success 200 {object} WebhookDeliveryDTO "Доставка"
+// @Failure 400 {object} core.ErrorResponse "Неверный ID"
+// @Failure 401 {object} core.ErrorResponse "Не авторизован"
+// @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} core.ErrorResponse "Доставка не найдена"
+// @Failure 500 {object} core.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /webhooks/deliveries/{id} [get]
+// @Id getWebhookDeliveryById
+func (h *WebhookHandler) GetDeliveryById(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := core.PathID(r, "id")
+	if err != nil {
+		core.HandleError(w, r, err)
+		return
+	}
+
+	delivery, err := h.deliveryService.GetByID(ctx, id)
+	if err != nil {
+		core.HandleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(ToWebhookDeliveryDTO(delivery))
+}
+
+// Redeliver повторяет доставку
+// @Summary Повторить доставку
+// @Description Сразу повторяет доставку независимо от её статуса. При неудаче доставка снова проходит полное расписание повторов
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID доставки"
+// @Success 200 {object} WebhookDeliveryDTO "Доставка с результатом попытки"
+// @Failure 400 {object} core.ErrorResponse "Неверный ID"
+// @Failure 401 {object} core.ErrorResponse "Не авторизован"
+// @Failure 403 {object} core.ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} core.ErrorResponse "Доставка не найдена"
+// @Failure 500 {object} core.ErrorResponse "Подписка удалена или отключена"
+// @Router /webhooks/deliveries/{id}/redeliver [post]
+// @Id redeliverWebhookDelivery
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := core.PathID(r, "id")
+	if err != nil {
+		core.HandleError(w, r, err)
+		return
+	}
+
+	delivery, err := h.deliveryService.Redeliver(ctx, id)
+	if err != nil {
+		core.HandleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(ToWebhookDeliveryDTO(delivery))
+}
@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/ActuallyHello/backendstory/pkg/core"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookSubscriptionRepository interface {
+	core.BaseRepository[WebhookSubscription]
+
+	FindActive(ctx context.Context) ([]WebhookSubscription, error)
+}
+
+type webhookSubscriptionRepository struct {
+	core.BaseRepositoryImpl[WebhookSubscription]
+}
+
+func NewWebhookSubscriptionRepository(db *gorm.DB) *webhookSubscriptionRepository {
+	return &webhookSubscriptionRepository{
+		BaseRepositoryImpl: *core.NewBaseRepositoryImpl[WebhookSubscription](db),
+	}
+}
+
+// FindActive ищет включенные подписки
+func (r *webhookSubscriptionRepository) FindActive(ctx context.Context) ([]WebhookSubscription, error) {
+	var subscriptions []WebhookSubscription
+	if err := r.GetDB(ctx).Where("ACTIVE = ?", true).Order("ID").Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+type WebhookDeliveryRepository interface {
+	core.BaseRepository[WebhookDelivery]
+
+	FindDue(ctx context.Context, now time.Time, limit int) ([]WebhookDelivery, error)
+	FindByEventID(ctx context.Context, eventID uint) ([]WebhookDelivery, error)
+}
+
+type webhookDeliveryRepository struct {
+	core.BaseRepositoryImpl[WebhookDelivery]
+}
+
+func NewWebhookDeliveryRepository(db *gorm.DB) *webhookDeliveryRepository {
+	return &webhookDeliveryRepository{
+		BaseRepositoryImpl: *core.NewBaseRepositoryImpl[WebhookDelivery](db),
+	}
+}
+
+// FindDue ищет доставки, время попытки которых наступило, и блокирует их до конца транзакции.
+// Заблокированные другим экземпляром приложения доставки пропускаются
+func (r *webhookDeliveryRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+	err := r.GetDB(ctx).
+		Where("STATUS = ? AND NEXTATTEMPTAT <= ?", PendingDeliveryStatus, now).
+		Order("ID").
+		Limit(limit).
+		Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate, Options: clause.LockingOptionsSkipLocked}).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// FindByEventID ищет доставки события по всем подпискам
+func (r *webhookDeliveryRepository) FindByEventID(ctx context.Context, eventID uint) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+	if err := r.GetDB(ctx).Where("EVENTID = ?", eventID).Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
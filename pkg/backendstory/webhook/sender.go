@@ -0,0 +1,87 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// SignatureHeader подпись тела запроса в виде sha256=<hex>
+	SignatureHeader = "X-Webhook-Signature"
+	// TimestampHeader время отправки в секундах Unix, входит в подпись для защиты от повторов
+	TimestampHeader = "X-Webhook-Timestamp"
+	// EventHeader тип события
+	EventHeader = "X-Webhook-Event"
+	// DeliveryHeader ID доставки в журнале доставок
+	DeliveryHeader = "X-Webhook-Delivery"
+	// IdempotencyKeyHeader ID события, одинаковый для всех попыток доставки
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	signaturePrefix = "sha256="
+
+	// maxResponseErrorSize сколько байт ответа получателя сохраняется в ошибке доставки
+	maxResponseErrorSize = 512
+)
+
+// Sign вычисляет подпись HMAC-SHA256 от строки "<timestamp>.<body>" на секрете подписки
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature проверяет подпись запроса на стороне получателя
+func VerifySignature(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// Sender отправляет подписанные запросы получателям
+type Sender struct {
+	client *http.Client
+}
+
+func NewSender(client *http.Client) *Sender {
+	return &Sender{
+		client: client,
+	}
+}
+
+// Send отправляет доставку получателю подписки и возвращает код ответа.
+// Код 0 означает, что ответ не получен. Ответ вне диапазона 2xx считается ошибкой
+func (s *Sender) Send(ctx context.Context, subscription WebhookSubscription, delivery WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(subscription.Secret, timestamp, body))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(IdempotencyKeyHeader, strconv.FormatUint(uint64(delivery.EventID), 10))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseErrorSize))
+		return resp.StatusCode, fmt.Errorf("webhook %s responded with status %d: %s", subscription.URL, resp.StatusCode, message)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,190 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/ActuallyHello/backendstory/pkg/backendstory/outbox"
+	"github.com/ActuallyHello/backendstory/pkg/core"
+)
+
+const (
+	webhookSubscriptionServiceCode = "WEBHOOKSUBSCRIPTION_SERVICE"
+	webhookDeliveryServiceCode     = "WEBHOOKDELIVERY_SERVICE"
+)
+
+type WebhookSubscriptionService interface {
+	core.BaseService[WebhookSubscription]
+
+	Create(ctx context.Context, subscription WebhookSubscription) (WebhookSubscription, error)
+	Update(ctx context.Context, subscription WebhookSubscription) (WebhookSubscription, error)
+	Delete(ctx context.Context, subscription WebhookSubscription) error
+}
+
+type webhookSubscriptionService struct {
+	core.BaseServiceImpl[WebhookSubscription]
+	subscriptionRepo WebhookSubscriptionRepository
+}
+
+func NewWebhookSubscriptionService(
+	subscriptionRepo WebhookSubscriptionRepository,
+) *webhookSubscriptionService {
+	return &webhookSubscriptionService{
+		BaseServiceImpl:  *core.NewBaseServiceImpl(subscriptionRepo),
+		subscriptionRepo: subscriptionRepo,
+	}
+}
+
+// Create создает подписку на события
+func (s *webhookSubscriptionService) Create(ctx context.Context, subscription WebhookSubscription) (WebhookSubscription, error) {
+	created, err := s.subscriptionRepo.Create(ctx, subscription)
+	if err != nil {
+		return WebhookSubscription{}, core.NewTechnicalError(err, webhookSubscriptionServiceCode, "Ошибка при создании подписки на события")
+	}
+	return created, nil
+}
+
+// Update обновляет подписку на события
+func (s *webhookSubscriptionService) Update(ctx context.Context, subscription WebhookSubscription) (WebhookSubscription, error) {
+	updated, err := s.subscriptionRepo.Update(ctx, subscription)
+	if err != nil {
+		return WebhookSubscription{}, core.NewTechnicalError(err, webhookSubscriptionServiceCode, "Ошибка при обновлении подписки на события")
+	}
+	return updated, nil
+}
+
+// Delete удаляет подписку. Ожидающие доставки по ней переводятся в статус DeadDeliveryStatus при следующей попытке
+func (s *webhookSubscriptionService) Delete(ctx context.Context, subscription WebhookSubscription) error {
+	if err := s.subscriptionRepo.Delete(ctx, subscription); err != nil {
+		return core.NewTechnicalError(err, webhookSubscriptionServiceCode, "Ошибка при удалении подписки на события")
+	}
+	return nil
+}
+
+type WebhookDeliveryService interface {
+	core.BaseService[WebhookDelivery]
+
+	Enqueue(ctx context.Context, envelope outbox.Envelope) error
+	Redeliver(ctx context.Context, id uint) (WebhookDelivery, error)
+}
+
+type webhookDeliveryService struct {
+	core.BaseServiceImpl[WebhookDelivery]
+	deliveryRepo     WebhookDeliveryRepository
+	subscriptionRepo WebhookSubscriptionRepository
+	txManager        core.TxManager
+	dispatcher       *Dispatcher
+}
+
+func NewWebhookDeliveryService(
+	deliveryRepo WebhookDeliveryRepository,
+	subscriptionRepo WebhookSubscriptionRepository,
+	txManager core.TxManager,
+	dispatcher *Dispatcher,
+) *webhookDeliveryService {
+	return &webhookDeliveryService{
+		BaseServiceImpl:  *core.NewBaseServiceImpl(deliveryRepo),
+		deliveryRepo:     deliveryRepo,
+		subscriptionRepo: subscriptionRepo,
+		txManager:        txManager,
+		dispatcher:       dispatcher,
+	}
+}
+
+// Enqueue ставит событие в очередь доставки каждой подписке на его тип.
+// Событие может прийти из OUTBOX повторно, поэтому уже созданные доставки не дублируются
+func (s *webhookDeliveryService) Enqueue(ctx context.Context, envelope outbox.Envelope) error {
+	subscriptions, err := s.subscriptionRepo.FindActive(ctx)
+	if err != nil {
+		return core.NewTechnicalError(err, webhookDeliveryServiceCode, "Ошибка при получении подписок на события")
+	}
+	existing, err := s.deliveryRepo.FindByEventID(ctx, envelope.ID)
+	if err != nil {
+		return core.NewTechnicalError(err, webhookDeliveryServiceCode, "Ошибка при получении доставок события")
+	}
+	enqueued := make(map[uint]struct{}, len(existing))
+	for _, delivery := range existing {
+		enqueued[delivery.SubscriptionID] = struct{}{}
+	}
+
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return core.NewTechnicalError(err, webhookDeliveryServiceCode, "Ошибка при сериализации события "+envelope.Type)
+	}
+
+	now := time.Now()
+	var deliveries []WebhookDelivery
+	for _, subscription := range subscriptions {
+		if _, ok := enqueued[subscription.ID]; ok || !subscription.Subscribes(envelope.Type) {
+			continue
+		}
+		deliveries = append(deliveries, WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        envelope.ID,
+			EventType:      envelope.Type,
+			Payload:        string(payload),
+			Status:         PendingDeliveryStatus,
+			NextAttemptAt:  now,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	if _, err := s.deliveryRepo.CreateMany(ctx, deliveries); err != nil {
+		return core.NewTechnicalError(err, webhookDeliveryServiceCode, "Ошибка при постановке событий в очередь доставки")
+	}
+	return nil
+}
+
+// Redeliver сразу повторяет доставку независимо от её статуса. Счетчик попыток сбрасывается,
+// поэтому при неудаче доставка снова проходит полное расписание повторов.
+// Как и в диспетчере, запрос получателю отправляется вне транзакции
+func (s *webhookDeliveryService) Redeliver(ctx context.Context, id uint) (WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	var subscription WebhookSubscription
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		delivery, err = s.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		subscription, err = s.subscriptionRepo.FindByID(ctx, delivery.SubscriptionID)
+		if err != nil {
+			if errors.Is(err, &core.NotFoundError{}) {
+				return core.NewLogicalError(nil, core.WebhookSubscriptionDeletedCode, "Подписка доставки с ИД %d удалена", delivery.SubscriptionID)
+			}
+			return core.NewTechnicalError(err, webhookDeliveryServiceCode, "Ошибка при получении подписки доставки")
+		}
+		if !subscription.Active {
+			return core.NewLogicalError(nil, core.WebhookSubscriptionInactiveCode, "Подписка доставки с ИД %d отключена", subscription.ID)
+		}
+
+		// резервируем доставку, чтобы диспетчер не отправил её одновременно с нами
+		delivery.NextAttemptAt = time.Now().Add(claimTimeout)
+		delivery, err = s.deliveryRepo.Update(ctx, delivery)
+		if err != nil {
+			return core.NewTechnicalError(err, webhookDeliveryServiceCode, "Ошибка при обновлении доставки")
+		}
+		return nil
+	})
+	if err != nil {
+		return WebhookDelivery{}, err
+	}
+
+	delivery.Attempts = 0
+	s.dispatcher.Attempt(ctx, &delivery, subscription)
+
+	var redelivered WebhookDelivery
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		redelivered, err = s.deliveryRepo.Update(ctx, delivery)
+		return err
+	})
+	if err != nil {
+		return WebhookDelivery{}, core.NewTechnicalError(err, webhookDeliveryServiceCode, "Ошибка при обновлении доставки")
+	}
+	return redelivered, nil
+}
@@ -0,0 +1,28 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/ActuallyHello/backendstory/pkg/backendstory/outbox"
+)
+
+// Sink получатель событий OUTBOX, который ставит их в очередь доставки подпискам.
+// Событие может прийти из OUTBOX повторно: Enqueue не дублирует уже созданные доставки,
+// а уникальный ключ (SUBSCRIPTIONID, EVENTID) отклоняет одновременную постановку в очередь
+type Sink struct {
+	deliveryService WebhookDeliveryService
+}
+
+func NewSink(deliveryService WebhookDeliveryService) *Sink {
+	return &Sink{
+		deliveryService: deliveryService,
+	}
+}
+
+func (s *Sink) Name() string {
+	return "webhook"
+}
+
+func (s *Sink) Deliver(ctx context.Context, envelope outbox.Envelope) error {
+	return s.deliveryService.Enqueue(ctx, envelope)
+}
@@ -16,6 +16,9 @@ type ApplicationConfig struct {
 	DatabaseConfig *DatabaseConfig `mapstructure:"database"`
 	ServerConfig   *ServerConfig   `mapstructure:"server"`
 	KeycloakConfig *KeycloakConfig `mapstructure:"keycloak"`
+	CacheConfig    *CacheConfig    `mapstructure:"cache"`
+	OutboxConfig   *OutboxConfig   `mapstructure:"outbox"`
+	WebhookConfig  *WebhookConfig  `mapstructure:"webhook"`
 }
 
 func MustLoadConfig(path string) *ApplicationConfig {
@@ -0,0 +1,33 @@
+package config
+
+import "time"
+
+type CacheConfig struct {
+	EnumCacheConfig       EnumCacheConfig       `mapstructure:"enum"`
+	RepositoryCacheConfig RepositoryCacheConfig `mapstructure:"repository"`
+}
+
+type EnumCacheConfig struct {
+	TTL    time.Duration `mapstructure:"ttl"`
+	WarmUp bool          `mapstructure:"warm-up"`
+}
+
+type RepositoryCacheConfig struct {
+	// Backend хранилище кэша: memory или redis
+	Backend     string                       `mapstructure:"backend"`
+	Prefix      string                       `mapstructure:"prefix"`
+	LRUSize     int                          `mapstructure:"lru-size"`
+	RedisConfig RedisConfig                  `mapstructure:"redis"`
+	Entities    map[string]EntityCacheConfig `mapstructure:"entities"`
+}
+
+type RedisConfig struct {
+	Addr     string `mapstructure:"addr"`
+	Password string `mapstructure:"password"`
+	DB       int    `mapstructure:"db"`
+}
+
+type EntityCacheConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	TTL     time.Duration `mapstructure:"ttl"`
+}
@@ -3,12 +3,13 @@ package config
 import "time"
 
 type DatabaseConfig struct {
-	Host             string           `mapstructure:"host"`
-	Port             string           `mapstructure:"port"`
-	Username         string           `mapstructure:"username"`
-	Password         string           `mapstructure:"password"`
-	Database         string           `mapstructure:"database"`
-	ConnectionConfig ConnectionConfig `mapstructure:"connection"`
+	Host              string            `mapstructure:"host"`
+	Port              string            `mapstructure:"port"`
+	Username          string            `mapstructure:"username"`
+	Password          string            `mapstructure:"password"`
+	Database          string            `mapstructure:"database"`
+	ConnectionConfig  ConnectionConfig  `mapstructure:"connection"`
+	TransactionConfig TransactionConfig `mapstructure:"transaction"`
 }
 
 type ConnectionConfig struct {
@@ -16,4 +17,17 @@ type ConnectionConfig struct {
 	MaxIdle     int           `mapstructure:"max-idle"`
 	MaxLifetime time.Duration `mapstructure:"max-life-time"`
 	MaxIdleTime time.Duration `mapstructure:"max-idle-time"`
+	RetryConfig RetryConfig   `mapstructure:"retry"`
+}
+
+// TransactionConfig настройки повтора транзакций, откаченных из-за взаимной блокировки
+type TransactionConfig struct {
+	RetryConfig RetryConfig `mapstructure:"retry"`
+}
+
+// RetryConfig настройки повторов при временных сбоях
+type RetryConfig struct {
+	MaxAttempts int           `mapstructure:"max-attempts"`
+	BaseDelay   time.Duration `mapstructure:"base-delay"`
+	MaxDelay    time.Duration `mapstructure:"max-delay"`
 }
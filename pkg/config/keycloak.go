@@ -1,9 +1,19 @@
 package config
 
+import "time"
+
 type KeycloakConfig struct {
-	Host         string `mapstructure:"host"`
-	Realm        string `mapstructure:"realm"`
-	ClientID     string `mapstructure:"client-id"`
-	ClientSecret string `mapstructure:"client-secret"`
-	RedirectURI  string `mapstructure:"redirect-url"`
+	Host                 string               `mapstructure:"host"`
+	Realm                string               `mapstructure:"realm"`
+	ClientID             string               `mapstructure:"client-id"`
+	ClientSecret         string               `mapstructure:"client-secret"`
+	RedirectURI          string               `mapstructure:"redirect-url"`
+	CircuitBreakerConfig CircuitBreakerConfig `mapstructure:"circuit-breaker"`
+}
+
+type CircuitBreakerConfig struct {
+	FailureThreshold int           `mapstructure:"failure-threshold"`
+	Cooldown         time.Duration `mapstructure:"cooldown"`
+	HalfOpenCalls    int           `mapstructure:"half-open-calls"`
+	CallTimeout      time.Duration `mapstructure:"call-timeout"`
 }
@@ -0,0 +1,20 @@
+package config
+
+import "time"
+
+type OutboxConfig struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	Interval    time.Duration `mapstructure:"interval"`
+	BatchSize   int           `mapstructure:"batch-size"`
+	MaxAttempts uint          `mapstructure:"max-attempts"`
+	SinksConfig SinksConfig   `mapstructure:"sinks"`
+}
+
+type SinksConfig struct {
+	Log bool `mapstructure:"log"`
+	// File путь к файлу, в который события дописываются построчно в формате JSON
+	File string `mapstructure:"file"`
+	// HTTP адрес, на который события отправляются POST-запросом
+	HTTP        string        `mapstructure:"http"`
+	HTTPTimeout time.Duration `mapstructure:"http-timeout"`
+}
@@ -0,0 +1,13 @@
+package config
+
+import "time"
+
+type WebhookConfig struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	Interval    time.Duration `mapstructure:"interval"`
+	BatchSize   int           `mapstructure:"batch-size"`
+	MaxAttempts uint          `mapstructure:"max-attempts"`
+	// RetryDelay задержка перед первым повтором, каждый следующий повтор откладывается вдвое дольше
+	RetryDelay time.Duration `mapstructure:"retry-delay"`
+	Timeout    time.Duration `mapstructure:"timeout"`
+}
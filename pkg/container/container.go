@@ -3,9 +3,13 @@ package container
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"log/slog"
+	"net/http"
+	"time"
 
+	"github.com/ActuallyHello/backendstory/pkg/backendstory/audit"
 	"github.com/ActuallyHello/backendstory/pkg/backendstory/auth"
 	"github.com/ActuallyHello/backendstory/pkg/backendstory/cart"
 	cartitem "github.com/ActuallyHello/backendstory/pkg/backendstory/cart_item"
@@ -14,17 +18,53 @@ import (
 	"github.com/ActuallyHello/backendstory/pkg/backendstory/enumvalue"
 	"github.com/ActuallyHello/backendstory/pkg/backendstory/order"
 	orderitem "github.com/ActuallyHello/backendstory/pkg/backendstory/order_item"
+	"github.com/ActuallyHello/backendstory/pkg/backendstory/outbox"
 	"github.com/ActuallyHello/backendstory/pkg/backendstory/person"
 	"github.com/ActuallyHello/backendstory/pkg/backendstory/product"
 	productmedia "github.com/ActuallyHello/backendstory/pkg/backendstory/product_media"
+	"github.com/ActuallyHello/backendstory/pkg/backendstory/webhook"
 
 	"github.com/ActuallyHello/backendstory/pkg/backendstory/resources"
 	"github.com/ActuallyHello/backendstory/pkg/config"
 	"github.com/ActuallyHello/backendstory/pkg/core"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
 
+const (
+	defaultDatabaseConnectMaxAttempts = 10
+	defaultDatabaseConnectBaseDelay   = 500 * time.Millisecond
+	defaultDatabaseConnectMaxDelay    = 10 * time.Second
+	defaultTxMaxAttempts              = 3
+	defaultTxBaseDelay                = 50 * time.Millisecond
+	defaultTxMaxDelay                 = time.Second
+
+	defaultKeycloakFailureThreshold = 5
+	defaultKeycloakCooldown         = 30 * time.Second
+	defaultKeycloakHalfOpenCalls    = 1
+	defaultKeycloakCallTimeout      = 5 * time.Second
+
+	defaultEnumCacheTTL = 10 * time.Minute
+
+	memoryCacheBackend        = "memory"
+	redisCacheBackend         = "redis"
+	defaultCachePrefix        = "backendstory"
+	defaultLRUCacheSize       = 10000
+	defaultRepositoryCacheTTL = 5 * time.Minute
+
+	defaultOutboxInterval    = 5 * time.Second
+	defaultOutboxBatchSize   = 100
+	defaultOutboxMaxAttempts = 10
+	defaultOutboxHTTPTimeout = 5 * time.Second
+
+	defaultWebhookInterval    = 5 * time.Second
+	defaultWebhookBatchSize   = 50
+	defaultWebhookMaxAttempts = 8
+	defaultWebhookRetryDelay  = 30 * time.Second
+	defaultWebhookTimeout     = 10 * time.Second
+)
+
 type AppContainer struct {
 	// application
 	ctx    context.Context
@@ -33,6 +73,10 @@ type AppContainer struct {
 	// database
 	db        *gorm.DB
 	txManager core.TxManager
+	txMetrics *core.TxMetrics
+
+	// cache
+	cacheBackend core.CacheBackend
 
 	// repositoriest
 	enumRepo         enum.EnumRepository
@@ -45,6 +89,11 @@ type AppContainer struct {
 	cartItemRepo     cartitem.CartItemRepository
 	orderRepo        order.OrderRepository
 	orderItemRepo    orderitem.OrderItemRepository
+	auditLogRepo     audit.AuditLogRepository
+	outboxRepo       outbox.OutboxRepository
+
+	webhookSubscriptionRepo webhook.WebhookSubscriptionRepository
+	webhookDeliveryRepo     webhook.WebhookDeliveryRepository
 
 	// services
 	enumService         enum.EnumService
@@ -57,6 +106,11 @@ type AppContainer struct {
 	cartItemService     cartitem.CartItemService
 	orderItemService    orderitem.OrderItemService
 	orderService        order.OrderService
+	auditLogService     audit.AuditLogService
+	outboxService       outbox.OutboxService
+
+	webhookSubscriptionService webhook.WebhookSubscriptionService
+	webhookDeliveryService     webhook.WebhookDeliveryService
 
 	// resources
 	fileService resources.FileService
@@ -73,9 +127,12 @@ type AppContainer struct {
 	cartItemHandler     *cartitem.CartItemHandler
 	orderHandler        *order.OrderHandler
 	orderItemHandler    *orderitem.OrderItemHandler
+	auditLogHandler     *audit.AuditLogHandler
+	webhookHandler      *webhook.WebhookHandler
 
 	// auth
-	authService auth.AuthService
+	authService     auth.AuthService
+	keycloakBreaker *core.CircuitBreaker
 }
 
 func NewAppContainer(ctx context.Context, appConfig *config.ApplicationConfig) (*AppContainer, error) {
@@ -84,36 +141,115 @@ func NewAppContainer(ctx context.Context, appConfig *config.ApplicationConfig) (
 
 	// database
 	dsn := constructDSN(appConfig.DatabaseConfig)
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	retryConfig := databaseRetryConfig(appConfig.DatabaseConfig.ConnectionConfig.RetryConfig)
+	db, err := core.Retry(
+		appCtx,
+		"Connect to database",
+		func(ctx context.Context) (*gorm.DB, error) {
+			return gorm.Open(mysql.Open(dsn), &gorm.Config{})
+		},
+		core.SetMaxRetriesOpt(retryConfig.MaxAttempts),
+		core.SetBaseDelayOpt(retryConfig.BaseDelay),
+		core.SetMaxDelayOpt(retryConfig.MaxDelay),
+	)
 	if err != nil {
 		slog.Error("Error while establish database connection", "err", err)
 		log.Fatal(err)
 	}
-	txManager := core.NewGormTxManager(db)
+	txRetryConfig := transactionRetryConfig(appConfig.DatabaseConfig.TransactionConfig.RetryConfig)
+	txMetrics := core.NewTxMetrics()
+	txManager := core.NewGormTxManager(
+		db,
+		core.SetTxRetryOpt(
+			core.SetMaxRetriesOpt(txRetryConfig.MaxAttempts),
+			core.SetBaseDelayOpt(txRetryConfig.BaseDelay),
+			core.SetMaxDelayOpt(txRetryConfig.MaxDelay),
+		),
+		core.SetTxMetricsOpt(txMetrics),
+	)
 
 	// repositories
 	enumRepo := enum.NewEnumRepository(db)
 	enumValueRepo := enumvalue.NewEnumValueRepository(db)
 	personRepo := person.NewPersonRepository(db)
-	categoryRepo := category.NewCategoryRepository(db)
-	productRepo := product.NewProductRepository(db)
-	productMediaRepo := productmedia.NewProductMediaRepository(db)
+	var categoryRepo category.CategoryRepository = category.NewCategoryRepository(db)
+	var productRepo product.ProductRepository = product.NewProductRepository(db)
+	var productMediaRepo productmedia.ProductMediaRepository = productmedia.NewProductMediaRepository(db)
 	cartRepo := cart.NewCartRepository(db)
 	cartItemRepo := cartitem.NewCartItemRepository(db)
 	orderRepo := order.NewOrderRepository(db)
 	orderItemRepo := orderitem.NewOrderItemRepository(db)
+	auditLogRepo := audit.NewAuditLogRepository(db)
+	outboxRepo := outbox.NewOutboxRepository(db)
+	webhookSubscriptionRepo := webhook.NewWebhookSubscriptionRepository(db)
+	webhookDeliveryRepo := webhook.NewWebhookDeliveryRepository(db)
+
+	// cache
+	repositoryCacheConfig := repositoryCacheConfig(appConfig.CacheConfig)
+	cacheBackend := newCacheBackend(repositoryCacheConfig)
+	if cfg, ok := entityCacheConfig(repositoryCacheConfig, "product"); ok {
+		productRepo = product.NewCachedProductRepository(productRepo, cacheBackend, repositoryCacheConfig.Prefix, cfg.TTL)
+	}
+	if cfg, ok := entityCacheConfig(repositoryCacheConfig, "category"); ok {
+		categoryRepo = category.NewCachedCategoryRepository(categoryRepo, cacheBackend, repositoryCacheConfig.Prefix, cfg.TTL)
+	}
+	if cfg, ok := entityCacheConfig(repositoryCacheConfig, "product-media"); ok {
+		productMediaRepo = productmedia.NewCachedProductMediaRepository(productMediaRepo, cacheBackend, repositoryCacheConfig.Prefix, cfg.TTL)
+	}
+
+	// resources
+	fileService := resources.NewFileService()
 
 	// services
-	enumService := enum.NewEnumService(enumRepo)
-	enumValueService := enumvalue.NewEnumValueService(enumValueRepo, enumService)
+	auditLogService := audit.NewAuditLogService(auditLogRepo)
+	if err := core.RegisterAuditRecorder(db, auditLogService); err != nil {
+		slog.Error("Error while registering audit log", "err", err)
+		log.Fatal(err)
+	}
+	outboxService := outbox.NewOutboxService(outboxRepo)
+	enumCacheConfig := enumCacheConfig(appConfig.CacheConfig)
+	enumService := enum.NewEnumService(enumRepo, enumCacheConfig.TTL)
+	enumValueService := enumvalue.NewEnumValueService(enumValueRepo, enumService, enumCacheConfig.TTL)
+	if enumCacheConfig.WarmUp {
+		warmUpEnumCache(appCtx, enumService, enumValueService)
+	}
 	personService := person.NewPersonService(personRepo)
-	categoryService := category.NewCategoryService(categoryRepo)
-	productService := product.NewProductService(productRepo, enumService, enumValueService)
-	productMediaService := productmedia.NewProductMediaService(productMediaRepo)
+	categoryService := category.NewCategoryService(categoryRepo, txManager)
+	productService := product.NewProductService(productRepo, txManager, enumService, enumValueService, outboxService)
+	productMediaService := productmedia.NewProductMediaService(productMediaRepo, txManager, fileService, appConfig.ServerConfig.StaticFilesPath)
 	cartServices := cart.NewCartService(cartRepo)
 	cartItemService := cartitem.NewCartItemService(cartItemRepo, enumService, enumValueService, productService)
 	orderItemService := orderitem.NewOrderItemService(orderItemRepo, txManager, enumService, enumValueService, productService, cartItemService)
-	orderService := order.NewOrderService(orderRepo, txManager, enumService, enumValueService, orderItemService)
+	orderService := order.NewOrderService(orderRepo, txManager, enumService, enumValueService, orderItemService, outboxService)
+
+	webhookConfig := webhookConfig(appConfig.WebhookConfig)
+	webhookDispatcher := webhook.NewDispatcher(
+		txManager,
+		webhookDeliveryRepo,
+		webhookSubscriptionRepo,
+		webhook.NewSender(&http.Client{Timeout: webhookConfig.Timeout}),
+		webhookConfig.Interval,
+		webhookConfig.BatchSize,
+		webhookConfig.MaxAttempts,
+		webhookConfig.RetryDelay,
+	)
+	webhookSubscriptionService := webhook.NewWebhookSubscriptionService(webhookSubscriptionRepo)
+	webhookDeliveryService := webhook.NewWebhookDeliveryService(webhookDeliveryRepo, webhookSubscriptionRepo, txManager, webhookDispatcher)
+
+	// events
+	outboxConfig := outboxConfig(appConfig.OutboxConfig)
+	sinks := newOutboxSinks(outboxConfig.SinksConfig)
+	if webhookConfig.Enabled {
+		sinks = append(sinks, webhook.NewSink(webhookDeliveryService))
+		go webhookDispatcher.Run(appCtx)
+	}
+	if outboxConfig.Enabled {
+		if len(sinks) == 0 {
+			slog.Warn("No outbox sinks configured, events will be marked as sent without delivery")
+		}
+		dispatcher := outbox.NewDispatcher(txManager, outboxRepo, sinks, outboxConfig.Interval, outboxConfig.BatchSize, outboxConfig.MaxAttempts)
+		go dispatcher.Run(appCtx)
+	}
 
 	// auth
 	keycloakService, err := auth.NewKeycloakService(appCtx, appConfig.KeycloakConfig)
@@ -121,21 +257,28 @@ func NewAppContainer(ctx context.Context, appConfig *config.ApplicationConfig) (
 		slog.Error("Error while creating keycloak connection", "err", err)
 		log.Fatal(err)
 	}
-
-	// resources
-	fileService := resources.NewFileService()
+	breakerConfig := keycloakCircuitBreakerConfig(appConfig.KeycloakConfig.CircuitBreakerConfig)
+	keycloakBreaker := auth.NewKeycloakCircuitBreaker(
+		core.SetFailureThresholdOpt(breakerConfig.FailureThreshold),
+		core.SetCooldownOpt(breakerConfig.Cooldown),
+		core.SetHalfOpenCallsOpt(breakerConfig.HalfOpenCalls),
+		core.SetCallTimeoutOpt(breakerConfig.CallTimeout),
+	)
+	authService := auth.NewCircuitBreakerAuthService(keycloakService, keycloakBreaker)
 
 	// handlers
 	enumHandler := enum.NewEnumHandler(enumService)
 	enumValueHandler := enumvalue.NewEnumValueHandler(enumValueService)
 	personHandler := person.NewPersonHandler(personService)
-	authHandler := auth.NewAuthHandler(keycloakService)
+	authHandler := auth.NewAuthHandler(authService)
 	categoryHandler := category.NewCategoryHandler(categoryService)
 	productHandler := product.NewProductHandler(productService, enumValueService, categoryService)
 	cartHandler := cart.NewCartHandler(cartServices)
 	cartItemHandler := cartitem.NewCartItemHandler(cartItemService)
 	orderItemHandler := orderitem.NewOrderItemHandler(orderItemService, enumValueService)
 	orderHandler := order.NewOrderHandler(orderService, personService, enumValueService)
+	auditLogHandler := audit.NewAuditLogHandler(auditLogService)
+	webhookHandler := webhook.NewWebhookHandler(webhookSubscriptionService, webhookDeliveryService)
 	productMediaHandler := productmedia.NewProductMediaHandler(productMediaService, productService, fileService, appConfig.ServerConfig.StaticFilesPath)
 
 	return &AppContainer{
@@ -146,6 +289,10 @@ func NewAppContainer(ctx context.Context, appConfig *config.ApplicationConfig) (
 		// database
 		db:        db,
 		txManager: txManager,
+		txMetrics: txMetrics,
+
+		// cache
+		cacheBackend: cacheBackend,
 
 		// repositoriest
 		enumRepo:         enumRepo,
@@ -158,6 +305,11 @@ func NewAppContainer(ctx context.Context, appConfig *config.ApplicationConfig) (
 		cartItemRepo:     cartItemRepo,
 		orderRepo:        orderRepo,
 		orderItemRepo:    orderItemRepo,
+		auditLogRepo:     auditLogRepo,
+		outboxRepo:       outboxRepo,
+
+		webhookSubscriptionRepo: webhookSubscriptionRepo,
+		webhookDeliveryRepo:     webhookDeliveryRepo,
 
 		// services
 		enumService:         enumService,
@@ -170,6 +322,11 @@ func NewAppContainer(ctx context.Context, appConfig *config.ApplicationConfig) (
 		cartItemService:     cartItemService,
 		orderItemService:    orderItemService,
 		orderService:        orderService,
+		auditLogService:     auditLogService,
+		outboxService:       outboxService,
+
+		webhookSubscriptionService: webhookSubscriptionService,
+		webhookDeliveryService:     webhookDeliveryService,
 
 		// resources
 		fileService: fileService,
@@ -186,9 +343,12 @@ func NewAppContainer(ctx context.Context, appConfig *config.ApplicationConfig) (
 		cartItemHandler:     cartItemHandler,
 		orderHandler:        orderHandler,
 		orderItemHandler:    orderItemHandler,
+		auditLogHandler:     auditLogHandler,
+		webhookHandler:      webhookHandler,
 
 		// auth
-		authService: keycloakService,
+		authService:     authService,
+		keycloakBreaker: keycloakBreaker,
 	}, nil
 }
 
@@ -202,6 +362,197 @@ func constructDSN(databaseConfig *config.DatabaseConfig) string {
 	)
 }
 
+// databaseRetryConfig возвращает настройки повторов подключения к базе, подставляя значения по умолчанию
+func databaseRetryConfig(retryConfig config.RetryConfig) config.RetryConfig {
+	if retryConfig.MaxAttempts <= 0 {
+		retryConfig.MaxAttempts = defaultDatabaseConnectMaxAttempts
+	}
+	if retryConfig.BaseDelay <= 0 {
+		retryConfig.BaseDelay = defaultDatabaseConnectBaseDelay
+	}
+	if retryConfig.MaxDelay <= 0 {
+		retryConfig.MaxDelay = defaultDatabaseConnectMaxDelay
+	}
+	return retryConfig
+}
+
+// transactionRetryConfig возвращает настройки повтора транзакций, подставляя значения по умолчанию
+func transactionRetryConfig(retryConfig config.RetryConfig) config.RetryConfig {
+	if retryConfig.MaxAttempts <= 0 {
+		retryConfig.MaxAttempts = defaultTxMaxAttempts
+	}
+	if retryConfig.BaseDelay <= 0 {
+		retryConfig.BaseDelay = defaultTxBaseDelay
+	}
+	if retryConfig.MaxDelay <= 0 {
+		retryConfig.MaxDelay = defaultTxMaxDelay
+	}
+	return retryConfig
+}
+
+// keycloakCircuitBreakerConfig возвращает настройки размыкателя keycloak, подставляя значения по умолчанию
+func keycloakCircuitBreakerConfig(breakerConfig config.CircuitBreakerConfig) config.CircuitBreakerConfig {
+	if breakerConfig.FailureThreshold <= 0 {
+		breakerConfig.FailureThreshold = defaultKeycloakFailureThreshold
+	}
+	if breakerConfig.Cooldown <= 0 {
+		breakerConfig.Cooldown = defaultKeycloakCooldown
+	}
+	if breakerConfig.HalfOpenCalls <= 0 {
+		breakerConfig.HalfOpenCalls = defaultKeycloakHalfOpenCalls
+	}
+	if breakerConfig.CallTimeout <= 0 {
+		breakerConfig.CallTimeout = defaultKeycloakCallTimeout
+	}
+	return breakerConfig
+}
+
+// enumCacheConfig возвращает настройки кэша перечислений, подставляя значения по умолчанию
+func enumCacheConfig(cacheConfig *config.CacheConfig) config.EnumCacheConfig {
+	if cacheConfig == nil {
+		return config.EnumCacheConfig{TTL: defaultEnumCacheTTL, WarmUp: true}
+	}
+	enumCacheConfig := cacheConfig.EnumCacheConfig
+	if enumCacheConfig.TTL <= 0 {
+		enumCacheConfig.TTL = defaultEnumCacheTTL
+	}
+	return enumCacheConfig
+}
+
+// warmUpEnumCache заполняет кэши перечислений при старте. Ошибка не мешает запуску:
+// кэш заполнится при первых обращениях
+func warmUpEnumCache(ctx context.Context, enumService enum.EnumService, enumValueService enumvalue.EnumValueService) {
+	if err := enumService.WarmUp(ctx); err != nil {
+		slog.Warn("Failed to warm up enumeration cache", "err", err)
+		return
+	}
+	if err := enumValueService.WarmUp(ctx); err != nil {
+		slog.Warn("Failed to warm up enumeration value cache", "err", err)
+		return
+	}
+	slog.Info("Enumeration cache warmed up",
+		"enumerations", enumService.CacheStats().Size,
+		"values", enumValueService.CacheStats().ByID.Size,
+	)
+}
+
+// repositoryCacheConfig возвращает настройки кэша репозиториев, подставляя значения по умолчанию
+func repositoryCacheConfig(cacheConfig *config.CacheConfig) config.RepositoryCacheConfig {
+	var repositoryCacheConfig config.RepositoryCacheConfig
+	if cacheConfig != nil {
+		repositoryCacheConfig = cacheConfig.RepositoryCacheConfig
+	}
+	if repositoryCacheConfig.Backend == "" {
+		repositoryCacheConfig.Backend = memoryCacheBackend
+	}
+	if repositoryCacheConfig.Prefix == "" {
+		repositoryCacheConfig.Prefix = defaultCachePrefix
+	}
+	if repositoryCacheConfig.LRUSize <= 0 {
+		repositoryCacheConfig.LRUSize = defaultLRUCacheSize
+	}
+	return repositoryCacheConfig
+}
+
+// newCacheBackend создает хранилище кэша репозиториев
+func newCacheBackend(repositoryCacheConfig config.RepositoryCacheConfig) core.CacheBackend {
+	switch repositoryCacheConfig.Backend {
+	case redisCacheBackend:
+		client := redis.NewClient(&redis.Options{
+			Addr:     repositoryCacheConfig.RedisConfig.Addr,
+			Password: repositoryCacheConfig.RedisConfig.Password,
+			DB:       repositoryCacheConfig.RedisConfig.DB,
+		})
+		slog.Info("Repository cache uses redis", "addr", repositoryCacheConfig.RedisConfig.Addr)
+		return core.NewRedisCache(client)
+	case memoryCacheBackend:
+		return core.NewLRUCache(repositoryCacheConfig.LRUSize)
+	default:
+		log.Fatalf("unknown repository cache backend: %s", repositoryCacheConfig.Backend)
+		return nil
+	}
+}
+
+// entityCacheConfig возвращает настройки кэша сущности, если кэш для неё включен
+func entityCacheConfig(repositoryCacheConfig config.RepositoryCacheConfig, entity string) (config.EntityCacheConfig, bool) {
+	entityCacheConfig, ok := repositoryCacheConfig.Entities[entity]
+	if !ok || !entityCacheConfig.Enabled {
+		return config.EntityCacheConfig{}, false
+	}
+	if entityCacheConfig.TTL <= 0 {
+		entityCacheConfig.TTL = defaultRepositoryCacheTTL
+	}
+	return entityCacheConfig, true
+}
+
+// outboxConfig возвращает настройки доставки событий, подставляя значения по умолчанию
+func outboxConfig(cfg *config.OutboxConfig) config.OutboxConfig {
+	if cfg == nil {
+		return config.OutboxConfig{
+			Enabled:     true,
+			Interval:    defaultOutboxInterval,
+			BatchSize:   defaultOutboxBatchSize,
+			MaxAttempts: defaultOutboxMaxAttempts,
+			SinksConfig: config.SinksConfig{Log: true, HTTPTimeout: defaultOutboxHTTPTimeout},
+		}
+	}
+	outboxConfig := *cfg
+	if outboxConfig.Interval <= 0 {
+		outboxConfig.Interval = defaultOutboxInterval
+	}
+	if outboxConfig.BatchSize <= 0 {
+		outboxConfig.BatchSize = defaultOutboxBatchSize
+	}
+	if outboxConfig.MaxAttempts == 0 {
+		outboxConfig.MaxAttempts = defaultOutboxMaxAttempts
+	}
+	if outboxConfig.SinksConfig.HTTPTimeout <= 0 {
+		outboxConfig.SinksConfig.HTTPTimeout = defaultOutboxHTTPTimeout
+	}
+	return outboxConfig
+}
+
+// newOutboxSinks создает получателей событий по настройкам
+func newOutboxSinks(sinksConfig config.SinksConfig) []outbox.Sink {
+	var sinks []outbox.Sink
+	if sinksConfig.Log {
+		sinks = append(sinks, outbox.NewLogSink())
+	}
+	if sinksConfig.File != "" {
+		sinks = append(sinks, outbox.NewFileSink(sinksConfig.File))
+	}
+	if sinksConfig.HTTP != "" {
+		sinks = append(sinks, outbox.NewHTTPSink(sinksConfig.HTTP, sinksConfig.HTTPTimeout))
+	}
+	return sinks
+}
+
+// webhookConfig возвращает настройки доставки событий подпискам, подставляя значения по умолчанию
+func webhookConfig(cfg *config.WebhookConfig) config.WebhookConfig {
+	var webhookConfig config.WebhookConfig
+	if cfg == nil {
+		webhookConfig.Enabled = true
+	} else {
+		webhookConfig = *cfg
+	}
+	if webhookConfig.Interval <= 0 {
+		webhookConfig.Interval = defaultWebhookInterval
+	}
+	if webhookConfig.BatchSize <= 0 {
+		webhookConfig.BatchSize = defaultWebhookBatchSize
+	}
+	if webhookConfig.MaxAttempts == 0 {
+		webhookConfig.MaxAttempts = defaultWebhookMaxAttempts
+	}
+	if webhookConfig.RetryDelay <= 0 {
+		webhookConfig.RetryDelay = defaultWebhookRetryDelay
+	}
+	if webhookConfig.Timeout <= 0 {
+		webhookConfig.Timeout = defaultWebhookTimeout
+	}
+	return webhookConfig
+}
+
 // Close освобождает ресурсы
 func (c *AppContainer) Close() {
 	slog.Info("Closing application resources")
@@ -218,6 +569,12 @@ func (c *AppContainer) Close() {
 		}
 	}
 
+	if closer, ok := c.cacheBackend.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			slog.Error("failed to close cache backend", "error", err)
+		}
+	}
+
 	slog.Info("All resources closed")
 }
 
@@ -284,6 +641,22 @@ func (c *AppContainer) GetOrderItemRepository() orderitem.OrderItemRepository {
 	return c.orderItemRepo
 }
 
+func (c *AppContainer) GetAuditLogRepository() audit.AuditLogRepository {
+	return c.auditLogRepo
+}
+
+func (c *AppContainer) GetOutboxRepository() outbox.OutboxRepository {
+	return c.outboxRepo
+}
+
+func (c *AppContainer) GetWebhookSubscriptionRepository() webhook.WebhookSubscriptionRepository {
+	return c.webhookSubscriptionRepo
+}
+
+func (c *AppContainer) GetWebhookDeliveryRepository() webhook.WebhookDeliveryRepository {
+	return c.webhookDeliveryRepo
+}
+
 // Services
 func (c *AppContainer) GetEnumService() enum.EnumService {
 	return c.enumService
@@ -329,6 +702,22 @@ func (c *AppContainer) GetOrderItemService() orderitem.OrderItemService {
 	return c.orderItemService
 }
 
+func (c *AppContainer) GetAuditLogService() audit.AuditLogService {
+	return c.auditLogService
+}
+
+func (c *AppContainer) GetOutboxService() outbox.OutboxService {
+	return c.outboxService
+}
+
+func (c *AppContainer) GetWebhookSubscriptionService() webhook.WebhookSubscriptionService {
+	return c.webhookSubscriptionService
+}
+
+func (c *AppContainer) GetWebhookDeliveryService() webhook.WebhookDeliveryService {
+	return c.webhookDeliveryService
+}
+
 // Handlers
 func (c *AppContainer) GetAuthHandler() *auth.AuthHandler {
 	return c.authHandler
@@ -374,7 +763,25 @@ func (c *AppContainer) GetOrderItemHandler() *orderitem.OrderItemHandler {
 	return c.orderItemHandler
 }
 
+func (c *AppContainer) GetAuditLogHandler() *audit.AuditLogHandler {
+	return c.auditLogHandler
+}
+
+func (c *AppContainer) GetWebhookHandler() *webhook.WebhookHandler {
+	return c.webhookHandler
+}
+
 // Auth
 func (c *AppContainer) GetAuthService() auth.AuthService {
 	return c.authService
 }
+
+// GetTxStats возвращает счетчики повторов транзакций
+func (c *AppContainer) GetTxStats() core.TxStats {
+	return c.txMetrics.Stats()
+}
+
+// GetCircuitBreakers возвращает размыкатели внешних сервисов
+func (c *AppContainer) GetCircuitBreakers() []*core.CircuitBreaker {
+	return []*core.CircuitBreaker{c.keycloakBreaker}
+}
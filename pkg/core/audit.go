@@ -0,0 +1,161 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+type AuditOperation string
+
+const (
+	auditPluginName = "core:audit"
+
+	AuditCreate  AuditOperation = "create"
+	AuditUpdate  AuditOperation = "update"
+	AuditDelete  AuditOperation = "delete"
+	AuditRestore AuditOperation = "restore"
+	AuditPurge   AuditOperation = "purge"
+)
+
+// AuditChange значение поля до и после изменения
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// AuditEntry изменение сущности, которое репозиторий передает в журнал аудита
+type AuditEntry struct {
+	Entity    string
+	EntityID  uint
+	Operation AuditOperation
+	// Changes измененные поля сущности
+	Changes map[string]AuditChange
+}
+
+// AuditRecorder сохраняет записи журнала аудита. Вызывается в контексте изменения,
+// поэтому внутри транзакции запись попадает в ту же транзакцию
+type AuditRecorder interface {
+	Record(ctx context.Context, entry AuditEntry) error
+}
+
+// Unaudited сущность, изменения которой не записываются в журнал аудита
+type Unaudited interface {
+	Unaudited()
+}
+
+// auditPlugin хранит AuditRecorder в настройках gorm, откуда его берут все репозитории
+type auditPlugin struct {
+	recorder AuditRecorder
+}
+
+func (p *auditPlugin) Name() string {
+	return auditPluginName
+}
+
+func (p *auditPlugin) Initialize(*gorm.DB) error {
+	return nil
+}
+
+// RegisterAuditRecorder включает журнал аудита для всех репозиториев, работающих с db
+func RegisterAuditRecorder(db *gorm.DB, recorder AuditRecorder) error {
+	return db.Use(&auditPlugin{recorder: recorder})
+}
+
+func auditRecorderFromDB(db *gorm.DB) (AuditRecorder, bool) {
+	plugin, ok := db.Config.Plugins[auditPluginName].(*auditPlugin)
+	if !ok {
+		return nil, false
+	}
+	return plugin.recorder, true
+}
+
+// auditChanges сравнивает JSON-представления сущностей до и после изменения.
+// Для создания before равен nil, для безвозвратного удаления after равен nil
+func auditChanges(before, after any) (map[string]AuditChange, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]AuditChange)
+	for name, value := range beforeFields {
+		if afterValue, ok := afterFields[name]; !ok || !reflect.DeepEqual(value, afterValue) {
+			changes[name] = AuditChange{Before: value, After: afterFields[name]}
+		}
+	}
+	for name, value := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			changes[name] = AuditChange{Before: nil, After: value}
+		}
+	}
+	return changes, nil
+}
+
+func auditFields(entity any) (map[string]any, error) {
+	if value := reflect.ValueOf(entity); !value.IsValid() || (value.Kind() == reflect.Pointer && value.IsNil()) {
+		return nil, nil
+	}
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// auditRecorder возвращает журнал аудита, если он включен и сущность не исключена из аудита
+func (r *BaseRepositoryImpl[T]) auditRecorder() (AuditRecorder, bool) {
+	var entity T
+	if _, ok := any(&entity).(Unaudited); ok {
+		return nil, false
+	}
+	return auditRecorderFromDB(r.db)
+}
+
+// auditBefore читает состояние записи перед изменением, если журнал аудита включен
+func (r *BaseRepositoryImpl[T]) auditBefore(ctx context.Context, id uint) *T {
+	if _, ok := r.auditRecorder(); !ok {
+		return nil
+	}
+	var before T
+	if err := r.GetDB(WithDeleted(ctx)).First(&before, id).Error; err != nil {
+		return nil
+	}
+	return &before
+}
+
+// audit записывает изменение в журнал аудита. Запись выполняется в контексте изменения,
+// поэтому ошибка журнала возвращается вызывающему, чтобы транзакция откатила и само изменение
+func (r *BaseRepositoryImpl[T]) audit(ctx context.Context, operation AuditOperation, id uint, before, after *T) error {
+	recorder, ok := r.auditRecorder()
+	if !ok {
+		return nil
+	}
+
+	var entity T
+	changes, err := auditChanges(before, after)
+	if err != nil {
+		return fmt.Errorf("audit %s %s %d: %w", operation, entity.TableName(), id, err)
+	}
+	err = recorder.Record(ctx, AuditEntry{
+		Entity:    entity.TableName(),
+		EntityID:  id,
+		Operation: operation,
+		Changes:   changes,
+	})
+	if err != nil {
+		return fmt.Errorf("audit %s %s %d: %w", operation, entity.TableName(), id, err)
+	}
+	return nil
+}
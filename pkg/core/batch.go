@@ -0,0 +1,334 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type BatchMode string
+
+type BatchOperation string
+
+const (
+	// BatchAllOrNothing все элементы пакета применяются в одной транзакции, любая ошибка откатывает пакет
+	BatchAllOrNothing BatchMode = "all_or_nothing"
+	// BatchBestEffort каждый элемент применяется в своей транзакции, ошибки не влияют на остальные
+	BatchBestEffort BatchMode = "best_effort"
+
+	BatchCreate BatchOperation = "create"
+	BatchUpdate BatchOperation = "update"
+	BatchDelete BatchOperation = "delete"
+
+	// MaxBatchSize ограничивает общее количество элементов в пакетном запросе
+	MaxBatchSize = 500
+)
+
+// BatchRequest пакетный запрос на создание, обновление и удаление записей
+type BatchRequest[C any, U any] struct {
+	Mode   BatchMode         `json:"mode" validate:"omitempty,oneof=all_or_nothing best_effort"`
+	Create []C               `json:"create"`
+	Update []U               `json:"update"`
+	Delete []BatchDeleteItem `json:"delete"`
+}
+
+// BatchDeleteItem элемент пакета на удаление. Version - версия записи из ETag, которую видел клиент:
+// если запись с тех пор изменилась, удаление отклоняется с конфликтом
+// @Name BatchDeleteItem
+type BatchDeleteItem struct {
+	ID      uint `json:"id" validate:"required"`
+	Version uint `json:"version" validate:"required,gt=0"`
+}
+
+// Size общее количество элементов в запросе
+func (r BatchRequest[C, U]) Size() int {
+	return len(r.Create) + len(r.Update) + len(r.Delete)
+}
+
+// Validate проверяет режим и размер пакета. Элементы проверяются по отдельности,
+// чтобы ошибка в одном из них попала в его результат, а не отклонила весь запрос
+func (r BatchRequest[C, U]) Validate() error {
+	switch r.Mode {
+	case "", BatchAllOrNothing, BatchBestEffort:
+	default:
+		return NewValidationError(nil, BatchModeInvalidCode, "Режим пакета должен быть %s или %s", BatchAllOrNothing, BatchBestEffort)
+	}
+	if r.Size() == 0 {
+		return NewValidationError(nil, BatchEmptyCode, "Пакет не содержит элементов")
+	}
+	if r.Size() > MaxBatchSize {
+		return NewValidationError(nil, BatchTooLargeCode, "Пакет может содержать не более %d элементов", MaxBatchSize)
+	}
+	return nil
+}
+
+// BatchItem элемент пакета: операция над сущностью и её результат
+type BatchItem[T BaseEntity] struct {
+	// Index позиция элемента в списке своей операции в запросе
+	Index     int
+	Operation BatchOperation
+	Entity    T
+	Err       error
+}
+
+// Batch набор операций над сущностями одного типа
+type Batch[T BaseEntity] struct {
+	Mode  BatchMode
+	Items []BatchItem[T]
+}
+
+func NewBatch[T BaseEntity](mode BatchMode) *Batch[T] {
+	if mode == "" {
+		mode = BatchAllOrNothing
+	}
+	return &Batch[T]{Mode: mode}
+}
+
+// Add добавляет операцию над сущностью
+func (b *Batch[T]) Add(operation BatchOperation, index int, entity T) {
+	b.Items = append(b.Items, BatchItem[T]{Index: index, Operation: operation, Entity: entity})
+}
+
+// Fail добавляет элемент, не прошедший проверку до выполнения пакета
+func (b *Batch[T]) Fail(operation BatchOperation, index int, err error) {
+	b.Items = append(b.Items, BatchItem[T]{Index: index, Operation: operation, Err: err})
+}
+
+// Failed количество элементов с ошибкой
+func (b *Batch[T]) Failed() int {
+	failed := 0
+	for _, item := range b.Items {
+		if item.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// BatchPrepareFunc выполняет бизнес-проверки элемента внутри транзакции пакета
+// и возвращает сущность, готовую к сохранению
+type BatchPrepareFunc[T BaseEntity] func(ctx context.Context, operation BatchOperation, entity T) (T, error)
+
+// ExecuteBatch применяет пакет. В режиме all_or_nothing элементы сохраняются пакетными запросами
+// в одной транзакции, в режиме best_effort каждый элемент сохраняется в собственной транзакции
+func ExecuteBatch[T BaseEntity](
+	ctx context.Context,
+	txManager TxManager,
+	repo BaseRepository[T],
+	batch *Batch[T],
+	prepare BatchPrepareFunc[T],
+) {
+	failDuplicates(batch)
+
+	if batch.Mode == BatchBestEffort {
+		for i := range batch.Items {
+			item := &batch.Items[i]
+			if item.Err != nil {
+				continue
+			}
+			// внутри внешней транзакции неудачный элемент откатывается к точке сохранения
+			txSettings := DefaultGormTxSettings(SetPropagationOpt(TxNested))
+			item.Err = txManager.DoWithSettings(ctx, txSettings, func(ctx context.Context) error {
+				return executeBatchItem(ctx, repo, item, prepare)
+			})
+		}
+		return
+	}
+
+	if batch.Failed() > 0 {
+		abortBatch(batch)
+		return
+	}
+	items := slices.Clone(batch.Items)
+	err := txManager.Do(ctx, func(ctx context.Context) error {
+		// повтор после взаимной блокировки начинается с исходных сущностей, а не сохраненных
+		// откаченной попыткой
+		copy(batch.Items, items)
+		return executeBatchGroups(ctx, repo, batch, prepare)
+	})
+	if err != nil {
+		abortBatch(batch)
+	}
+}
+
+// failDuplicates отклоняет повторные операции над одной и той же записью
+func failDuplicates[T BaseEntity](batch *Batch[T]) {
+	seen := make(map[uint]struct{})
+	for i := range batch.Items {
+		item := &batch.Items[i]
+		if item.Err != nil || item.Operation == BatchCreate {
+			continue
+		}
+		id := item.Entity.GetID()
+		if _, ok := seen[id]; ok {
+			item.Err = NewValidationError(nil, BatchDuplicateItemCode, "Запись с ИД %d встречается в пакете несколько раз", id)
+			continue
+		}
+		seen[id] = struct{}{}
+	}
+}
+
+// abortBatch помечает элементы без собственной ошибки как отмененные из-за отката пакета
+func abortBatch[T BaseEntity](batch *Batch[T]) {
+	for i := range batch.Items {
+		if batch.Items[i].Err == nil {
+			batch.Items[i].Err = NewLogicalError(nil, BatchAbortedCode, "Элемент не применен: пакет отменен из-за ошибок в других элементах")
+		}
+	}
+}
+
+func executeBatchItem[T BaseEntity](ctx context.Context, repo BaseRepository[T], item *BatchItem[T], prepare BatchPrepareFunc[T]) error {
+	entity := item.Entity
+	if prepare != nil {
+		var err error
+		if entity, err = prepare(ctx, item.Operation, entity); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	switch item.Operation {
+	case BatchCreate:
+		entity, err = repo.Create(ctx, entity)
+	case BatchUpdate:
+		entity, err = repo.Update(ctx, entity)
+	case BatchDelete:
+		err = repo.Delete(ctx, entity)
+	}
+	if err != nil {
+		return batchRepositoryError(err, entity)
+	}
+	item.Entity = entity
+	return nil
+}
+
+func executeBatchGroups[T BaseEntity](ctx context.Context, repo BaseRepository[T], batch *Batch[T], prepare BatchPrepareFunc[T]) error {
+	groups := map[BatchOperation][]int{}
+	for i := range batch.Items {
+		item := &batch.Items[i]
+		if prepare != nil {
+			entity, err := prepare(ctx, item.Operation, item.Entity)
+			if err != nil {
+				item.Err = err
+				return err
+			}
+			item.Entity = entity
+		}
+		groups[item.Operation] = append(groups[item.Operation], i)
+	}
+
+	for _, operation := range []BatchOperation{BatchCreate, BatchUpdate, BatchDelete} {
+		indexes := groups[operation]
+		if len(indexes) == 0 {
+			continue
+		}
+		entities := make([]T, 0, len(indexes))
+		for _, i := range indexes {
+			entities = append(entities, batch.Items[i].Entity)
+		}
+
+		var saved []T
+		var err error
+		switch operation {
+		case BatchCreate:
+			saved, err = repo.CreateMany(ctx, entities)
+		case BatchUpdate:
+			saved, err = repo.UpdateMany(ctx, entities)
+		case BatchDelete:
+			saved, err = entities, repo.DeleteMany(ctx, entities)
+		}
+		if err != nil {
+			// пакетный запрос не указывает на конкретную запись, ошибка относится ко всей группе
+			var entity T
+			err = batchRepositoryError(err, entity)
+			for _, i := range indexes {
+				batch.Items[i].Err = err
+			}
+			return err
+		}
+		for n, i := range indexes {
+			batch.Items[i].Entity = saved[n]
+		}
+	}
+	return nil
+}
+
+// batchRepositoryError оборачивает ошибки репозитория, сохраняя конфликт версий
+func batchRepositoryError[T BaseEntity](err error, entity T) error {
+	var conflictErr *ConflictError
+	if errors.As(err, &conflictErr) {
+		return err
+	}
+	return NewTechnicalError(err, entity.TableName()+serviceCodeSuffix, "Ошибка при сохранении пакета записей "+entity.LocalTableName())
+}
+
+// BatchItemError ошибка отдельного элемента пакета
+type BatchItemError struct {
+	// Status HTTP статус, который вернул бы запрос с одним этим элементом
+	Status int `json:"status"`
+	// Code код ошибки из реестра кодов ошибок
+	Code    string            `json:"code"`
+	Message string            `json:"message"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// BatchItemResult результат операции над элементом пакета
+type BatchItemResult[D any] struct {
+	Index     int             `json:"index"`
+	Operation BatchOperation  `json:"operation"`
+	Success   bool            `json:"success"`
+	Item      *D              `json:"item,omitempty"`
+	Error     *BatchItemError `json:"error,omitempty"`
+}
+
+// BatchResponse ответ на пакетный запрос
+// @Name BatchResponse
+type BatchResponse[D any] struct {
+	Mode      BatchMode            `json:"mode"`
+	Succeeded int                  `json:"succeeded"`
+	Failed    int                  `json:"failed"`
+	Items     []BatchItemResult[D] `json:"items"`
+}
+
+// NewBatchResponse формирует ответ по выполненному пакету, преобразуя успешные сущности в DTO.
+// Сообщения об ошибках элементов формируются на языке запроса
+func NewBatchResponse[T BaseEntity, D any](ctx context.Context, batch *Batch[T], toDTO func(T) (D, error)) BatchResponse[D] {
+	response := BatchResponse[D]{
+		Mode:  batch.Mode,
+		Items: make([]BatchItemResult[D], 0, len(batch.Items)),
+	}
+	for _, item := range batch.Items {
+		result := BatchItemResult[D]{Index: item.Index, Operation: item.Operation}
+		if item.Err != nil {
+			result.Error = toBatchItemError(ctx, item.Err)
+			response.Failed++
+			response.Items = append(response.Items, result)
+			continue
+		}
+
+		result.Success = true
+		response.Succeeded++
+		// изменения уже сохранены, поэтому ошибка построения DTO не делает элемент неуспешным
+		if item.Operation != BatchDelete {
+			if dto, err := toDTO(item.Entity); err == nil {
+				result.Item = &dto
+			}
+		}
+		response.Items = append(response.Items, result)
+	}
+	return response
+}
+
+func toBatchItemError(ctx context.Context, err error) *BatchItemError {
+	problem := ResolveProblem(err)
+	_, message := problem.Localize(ctx)
+	itemErr := &BatchItemError{Status: problem.Problem.Status, Code: problem.Code, Message: message}
+
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		itemErr.Details = CollectValidationDetails(ctx, validationErrors)
+	}
+	return itemErr
+}
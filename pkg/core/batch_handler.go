@@ -0,0 +1,135 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// BatchService сервис, применяющий пакеты операций над сущностями
+type BatchService[T BaseEntity] interface {
+	CrudService[T]
+
+	ExecuteBatch(ctx context.Context, batch *Batch[T])
+}
+
+// BatchItemMapper проверяет элемент пакетного запроса и собирает из него сущность
+type BatchItemMapper[T BaseEntity, R any] func(ctx context.Context, req R) (T, error)
+
+// BatchHandler реализует эндпоинт POST /{entity}/batch: разбирает пакетный запрос,
+// применяет его через сервис и отвечает результатом по каждому элементу.
+// Пакетные эндпоинты есть у каталога (продукты и категории), который загружается массово.
+// Заказы и корзины создаются только через бизнес-сценарии, а перечисления и персоны
+// меняются поштучно, поэтому пакетных эндпоинтов у них нет
+type BatchHandler[T BaseEntity, D any, C any, U any] struct {
+	crud      *CrudHandler[T, D]
+	service   BatchService[T]
+	toCreated BatchItemMapper[T, C]
+	toUpdated BatchItemMapper[T, U]
+}
+
+func NewBatchHandler[T BaseEntity, D any, C any, U any](
+	crud *CrudHandler[T, D],
+	service BatchService[T],
+	toCreated BatchItemMapper[T, C],
+	toUpdated BatchItemMapper[T, U],
+) *BatchHandler[T, D, C, U] {
+	return &BatchHandler[T, D, C, U]{
+		crud:      crud,
+		service:   service,
+		toCreated: toCreated,
+		toUpdated: toUpdated,
+	}
+}
+
+// Batch применяет пакет операций. Отвечает 200, если все элементы применены, и 207, если часть завершилась ошибкой
+func (h *BatchHandler[T, D, C, U]) Batch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req BatchRequest[C, U]
+	if err := DecodeJSON(r, &req); err != nil {
+		HandleError(w, r, NewTechnicalError(err, h.crud.code, err.Error()))
+		return
+	}
+	if err := req.Validate(); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	batch := NewBatch[T](req.Mode)
+	for i, item := range req.Create {
+		entity, err := h.toCreated(ctx, item)
+		if err != nil {
+			batch.Fail(BatchCreate, i, err)
+			continue
+		}
+		batch.Add(BatchCreate, i, entity)
+	}
+	for i, item := range req.Update {
+		entity, err := h.toUpdated(ctx, item)
+		if err != nil {
+			batch.Fail(BatchUpdate, i, err)
+			continue
+		}
+		batch.Add(BatchUpdate, i, entity)
+	}
+	for i, item := range req.Delete {
+		entity, err := h.toDeleted(ctx, item)
+		if err != nil {
+			batch.Fail(BatchDelete, i, err)
+			continue
+		}
+		batch.Add(BatchDelete, i, entity)
+	}
+
+	h.service.ExecuteBatch(ctx, batch)
+
+	response := NewBatchResponse(ctx, batch, h.batchDTOMapper(ctx, batch))
+
+	status := http.StatusOK
+	if response.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// toDeleted находит удаляемую запись и выставляет ей версию из запроса,
+// чтобы удаление не прошло, если запись изменилась после того, как клиент её прочитал
+func (h *BatchHandler[T, D, C, U]) toDeleted(ctx context.Context, item BatchDeleteItem) (T, error) {
+	var entity T
+	if item.ID == 0 || item.Version == 0 {
+		return entity, NewValidationError(nil, BatchDeleteItemInvalidCode, "Для удаления укажите ИД и версию записи")
+	}
+	entity, err := h.service.GetByID(ctx, item.ID)
+	if err != nil {
+		return entity, err
+	}
+	any(&entity).(Versioned).SetVersion(item.Version)
+	return entity, nil
+}
+
+// batchDTOMapper преобразует сохраненные сущности пакета в DTO одним вызовом ToDTOs.
+// Изменения уже применены, поэтому при ошибке не прерываем ответ, а строим DTO поэлементно
+func (h *BatchHandler[T, D, C, U]) batchDTOMapper(ctx context.Context, batch *Batch[T]) func(T) (D, error) {
+	saved := make([]T, 0, len(batch.Items))
+	for _, item := range batch.Items {
+		if item.Err == nil && item.Operation != BatchDelete {
+			saved = append(saved, item.Entity)
+		}
+	}
+
+	dtos := make(map[uint]D, len(saved))
+	if list, err := h.crud.ToDTOs(ctx, saved); err == nil {
+		for i, entity := range saved {
+			dtos[entity.GetID()] = list[i]
+		}
+	}
+
+	return func(entity T) (D, error) {
+		if dto, ok := dtos[entity.GetID()]; ok {
+			return dto, nil
+		}
+		return h.crud.toDTO(ctx, entity)
+	}
+}
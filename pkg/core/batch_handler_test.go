@@ -0,0 +1,121 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// directTxManager выполняет функцию без транзакции
+type directTxManager struct{}
+
+func (directTxManager) Do(ctx context.Context, f func(context.Context) error) error {
+	return f(ctx)
+}
+
+func (directTxManager) DoWithSettings(ctx context.Context, _ TxSettings, f func(context.Context) error) error {
+	return f(ctx)
+}
+
+// batchTestRepository репозиторий в памяти, удаляющий записи пакетом с проверкой версий
+type batchTestRepository struct {
+	*memoryRepository
+}
+
+func (r batchTestRepository) DeleteMany(_ context.Context, entities []testEntity) error {
+	for _, entity := range entities {
+		if stored, ok := r.rows[entity.ID]; !ok || stored.Version != entity.Version {
+			return NewConflictError(nil, RecordsVersionConflictCode, "Часть записей %s была изменена или удалена другим пользователем, обновите данные", entity.LocalTableName())
+		}
+	}
+	for _, entity := range entities {
+		delete(r.rows, entity.ID)
+	}
+	return nil
+}
+
+type batchTestService struct {
+	*BaseServiceImpl[testEntity]
+}
+
+func (s batchTestService) Delete(ctx context.Context, entity testEntity) error {
+	return s.GetRepo().Delete(ctx, entity)
+}
+
+func (s batchTestService) ExecuteBatch(ctx context.Context, batch *Batch[testEntity]) {
+	ExecuteBatch(ctx, directTxManager{}, s.GetRepo(), batch, nil)
+}
+
+type batchTestDTO struct {
+	ID uint `json:"id"`
+}
+
+func newBatchTestHandler(repo *memoryRepository) *BatchHandler[testEntity, batchTestDTO, batchTestDTO, batchTestDTO] {
+	service := batchTestService{NewBaseServiceImpl[testEntity](batchTestRepository{repo})}
+	crud := NewCrudHandler[testEntity, batchTestDTO]("TESTENTITY", nil, service, MapWith(func(entity testEntity) batchTestDTO {
+		return batchTestDTO{ID: entity.ID}
+	}))
+	unsupported := func(context.Context, batchTestDTO) (testEntity, error) {
+		return testEntity{}, NewValidationError(nil, ValidationErrorCode, "not supported")
+	}
+	return NewBatchHandler(crud, service, unsupported, unsupported)
+}
+
+func postBatch(t *testing.T, handler *BatchHandler[testEntity, batchTestDTO, batchTestDTO, batchTestDTO], body string) (int, BatchResponse[batchTestDTO]) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	handler.Batch(recorder, httptest.NewRequest(http.MethodPost, "/batch", bytes.NewBufferString(body)))
+
+	var response BatchResponse[batchTestDTO]
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	return recorder.Code, response
+}
+
+func TestBatchHandlerDeletesWithClientVersion(t *testing.T) {
+	first, second := newTestEntity(1, "first"), newTestEntity(2, "second")
+	second.Version = 3
+	repo := newMemoryRepository(first, second)
+
+	status, response := postBatch(t, newBatchTestHandler(repo), `{"delete":[{"id":1,"version":1},{"id":2,"version":3}]}`)
+
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 2, response.Succeeded)
+	assert.Empty(t, repo.rows)
+}
+
+func TestBatchHandlerRejectsDeleteOfStaleVersion(t *testing.T) {
+	entity := newTestEntity(1, "first")
+	entity.Version = 2
+	repo := newMemoryRepository(entity)
+
+	// клиент прочитал запись до последнего изменения
+	status, response := postBatch(t, newBatchTestHandler(repo), `{"delete":[{"id":1,"version":1}]}`)
+
+	assert.Equal(t, http.StatusMultiStatus, status)
+	require.Len(t, response.Items, 1)
+	require.NotNil(t, response.Items[0].Error)
+	assert.Equal(t, http.StatusConflict, response.Items[0].Error.Status)
+	assert.Equal(t, RecordsVersionConflictCode, response.Items[0].Error.Code)
+	assert.Contains(t, repo.rows, uint(1))
+}
+
+func TestBatchHandlerRequiresDeleteVersion(t *testing.T) {
+	repo := newMemoryRepository(newTestEntity(1, "first"), newTestEntity(2, "second"))
+
+	status, response := postBatch(t, newBatchTestHandler(repo), `{"mode":"best_effort","delete":[{"id":1},{"id":2,"version":1}]}`)
+
+	assert.Equal(t, http.StatusMultiStatus, status)
+	require.Len(t, response.Items, 2)
+	require.NotNil(t, response.Items[0].Error)
+	assert.Equal(t, http.StatusBadRequest, response.Items[0].Error.Status)
+	assert.Equal(t, BatchDeleteItemInvalidCode, response.Items[0].Error.Code)
+	assert.True(t, response.Items[1].Success)
+	assert.Contains(t, repo.rows, uint(1))
+	assert.NotContains(t, repo.rows, uint(2))
+}
@@ -0,0 +1,34 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteBatchRetriesFromOriginalEntities(t *testing.T) {
+	txm, recorder := newTestTxManager(t)
+	repo := NewBaseRepositoryImpl[testEntity](txm.db)
+	// обновление выполнено, удаление попало во взаимную блокировку, пакет повторяется целиком
+	recorder.fail("DELETE", errDeadlock)
+
+	batch := &Batch[testEntity]{
+		Mode: BatchAllOrNothing,
+		Items: []BatchItem[testEntity]{
+			{Index: 0, Operation: BatchUpdate, Entity: newTestEntity(1, "first")},
+			{Index: 1, Operation: BatchDelete, Entity: newTestEntity(2, "second")},
+		},
+	}
+	ExecuteBatch(context.Background(), txm, repo, batch, nil)
+
+	require.Zero(t, batch.Failed())
+	assert.Equal(t, uint(2), batch.Items[0].Entity.Version)
+
+	updates := recorder.execArgs("UPDATE")
+	require.Len(t, updates, 2)
+	for _, args := range updates {
+		assert.Equal(t, int64(1), args[len(args)-1], "повтор должен проверять версию клиента")
+	}
+}
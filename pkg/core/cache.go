@@ -0,0 +1,101 @@
+package core
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CacheStats статистика обращений к кэшу
+type CacheStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+	Size   int   `json:"size"`
+}
+
+type cacheItem[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// TTLCache потокобезопасный кэш в памяти процесса с временем жизни записей.
+// Нулевой ttl означает, что записи не устаревают
+type TTLCache[K comparable, V any] struct {
+	mu    sync.RWMutex
+	ttl   time.Duration
+	items map[K]cacheItem[V]
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+func NewTTLCache[K comparable, V any](ttl time.Duration) *TTLCache[K, V] {
+	return &TTLCache[K, V]{
+		ttl:   ttl,
+		items: make(map[K]cacheItem[V]),
+	}
+}
+
+// Get возвращает значение, если оно есть в кэше и не устарело
+func (c *TTLCache[K, V]) Get(key K) (V, bool) {
+	c.mu.RLock()
+	item, ok := c.items[key]
+	c.mu.RUnlock()
+
+	if ok && c.expired(item) {
+		c.deleteExpired(key)
+		ok = false
+	}
+	if !ok {
+		c.misses.Add(1)
+		var empty V
+		return empty, false
+	}
+	c.hits.Add(1)
+	return item.value, true
+}
+
+// Set сохраняет значение в кэше
+func (c *TTLCache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[key] = cacheItem[V]{value: value, expiresAt: time.Now().Add(c.ttl)}
+}
+
+// Delete удаляет значение из кэша
+func (c *TTLCache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.items, key)
+}
+
+func (c *TTLCache[K, V]) expired(item cacheItem[V]) bool {
+	return c.ttl > 0 && time.Now().After(item.expiresAt)
+}
+
+// deleteExpired удаляет запись, только если её не успели обновить после проверки
+func (c *TTLCache[K, V]) deleteExpired(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if item, ok := c.items[key]; ok && c.expired(item) {
+		delete(c.items, key)
+	}
+}
+
+// Clear удаляет все значения из кэша
+func (c *TTLCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = make(map[K]cacheItem[V])
+}
+
+// Stats возвращает количество попаданий, промахов и записей в кэше
+func (c *TTLCache[K, V]) Stats() CacheStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return CacheStats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+		Size:   len(c.items),
+	}
+}
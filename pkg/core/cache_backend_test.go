@@ -0,0 +1,139 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRedisCache поднимает miniredis на время теста
+func newTestRedisCache(t *testing.T) (*RedisCache, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	cache := NewRedisCache(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+	t.Cleanup(func() { cache.Close() })
+	return cache, server
+}
+
+// testCacheBackends возвращает реализации CacheBackend, для которых проверяется общее поведение
+func testCacheBackends(t *testing.T) map[string]CacheBackend {
+	t.Helper()
+	redisCache, _ := newTestRedisCache(t)
+	return map[string]CacheBackend{
+		"lru":   NewLRUCache(100),
+		"redis": redisCache,
+	}
+}
+
+func TestCacheBackendGetSetDelete(t *testing.T) {
+	for name, backend := range testCacheBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			_, ok, err := backend.Get(ctx, "key")
+			require.NoError(t, err)
+			assert.False(t, ok)
+
+			require.NoError(t, backend.Set(ctx, "key", []byte("value"), time.Minute))
+			value, ok, err := backend.Get(ctx, "key")
+			require.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, []byte("value"), value)
+
+			require.NoError(t, backend.Delete(ctx, "key", "missing"))
+			_, ok, err = backend.Get(ctx, "key")
+			require.NoError(t, err)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestCacheBackendIncr(t *testing.T) {
+	for name, backend := range testCacheBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			for want := int64(1); want <= 3; want++ {
+				got, err := backend.Incr(ctx, "gen")
+				require.NoError(t, err)
+				assert.Equal(t, want, got)
+			}
+
+			value, ok, err := backend.Get(ctx, "gen")
+			require.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, "3", string(value))
+		})
+	}
+}
+
+func TestLRUCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLRUCache(2)
+
+	require.NoError(t, cache.Set(ctx, "a", []byte("1"), 0))
+	require.NoError(t, cache.Set(ctx, "b", []byte("2"), 0))
+	// чтение делает "a" недавно использованной, поэтому вытесняется "b"
+	_, _, _ = cache.Get(ctx, "a")
+	require.NoError(t, cache.Set(ctx, "c", []byte("3"), 0))
+
+	_, ok, _ := cache.Get(ctx, "a")
+	assert.True(t, ok)
+	_, ok, _ = cache.Get(ctx, "b")
+	assert.False(t, ok)
+	_, ok, _ = cache.Get(ctx, "c")
+	assert.True(t, ok)
+}
+
+func TestLRUCacheCountersAreNotEvicted(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLRUCache(1)
+
+	_, err := cache.Incr(ctx, "gen")
+	require.NoError(t, err)
+	require.NoError(t, cache.Set(ctx, "a", []byte("1"), 0))
+	require.NoError(t, cache.Set(ctx, "b", []byte("2"), 0))
+
+	value, ok, err := cache.Get(ctx, "gen")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "1", string(value))
+}
+
+func TestLRUCacheExpiresEntries(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLRUCache(10)
+
+	require.NoError(t, cache.Set(ctx, "key", []byte("value"), time.Millisecond))
+	time.Sleep(5 * time.Millisecond)
+
+	_, ok, err := cache.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestRedisCacheExpiresEntries(t *testing.T) {
+	ctx := context.Background()
+	cache, server := newTestRedisCache(t)
+
+	require.NoError(t, cache.Set(ctx, "key", []byte("value"), time.Minute))
+	server.FastForward(2 * time.Minute)
+
+	_, ok, err := cache.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestRedisCacheReturnsConnectionErrors(t *testing.T) {
+	ctx := context.Background()
+	cache, server := newTestRedisCache(t)
+	server.Close()
+
+	_, _, err := cache.Get(ctx, "key")
+	assert.Error(t, err)
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// expireCacheItem переносит срок жизни записи в прошлое
+func expireCacheItem[K comparable, V any](c *TTLCache[K, V], key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item := c.items[key]
+	item.expiresAt = time.Now().Add(-time.Second)
+	c.items[key] = item
+}
+
+func TestTTLCacheGetSet(t *testing.T) {
+	cache := NewTTLCache[uint, string](time.Hour)
+
+	_, ok := cache.Get(1)
+	assert.False(t, ok)
+
+	cache.Set(1, "first")
+	value, ok := cache.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, "first", value)
+
+	cache.Set(1, "second")
+	value, _ = cache.Get(1)
+	assert.Equal(t, "second", value)
+
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 1, Size: 1}, cache.Stats())
+}
+
+func TestTTLCacheExpires(t *testing.T) {
+	cache := NewTTLCache[uint, string](time.Hour)
+	cache.Set(1, "first")
+	expireCacheItem(cache, 1)
+
+	_, ok := cache.Get(1)
+	assert.False(t, ok)
+	// устаревшая запись удаляется при чтении
+	assert.Equal(t, CacheStats{Misses: 1}, cache.Stats())
+}
+
+func TestTTLCacheZeroTTLNeverExpires(t *testing.T) {
+	cache := NewTTLCache[uint, string](0)
+	cache.Set(1, "first")
+	expireCacheItem(cache, 1)
+
+	value, ok := cache.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, "first", value)
+}
+
+func TestTTLCacheDeleteAndClear(t *testing.T) {
+	cache := NewTTLCache[string, int](time.Hour)
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+
+	cache.Delete("a")
+	_, ok := cache.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 2, cache.Stats().Size)
+
+	cache.Clear()
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+	assert.Zero(t, cache.Stats().Size)
+}
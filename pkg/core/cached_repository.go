@@ -0,0 +1,321 @@
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CachedRepository декоратор репозитория с кэшированием чтения (read-through).
+// Сущности кэшируются по ID, выборки - по хэшу операции и критериев поиска.
+// Любое изменение сбрасывает записи измененных ID и увеличивает поколение сущности,
+// после чего ранее закэшированные выборки больше не используются.
+// Внутри транзакции и при выборке с мягко удаленными записями кэш не используется
+type CachedRepository[T BaseEntity] struct {
+	BaseRepository[T]
+
+	backend CacheBackend
+	prefix  string
+	ttl     time.Duration
+}
+
+func NewCachedRepository[T BaseEntity](
+	repo BaseRepository[T],
+	backend CacheBackend,
+	prefix string,
+	ttl time.Duration,
+) *CachedRepository[T] {
+	var entity T
+	return &CachedRepository[T]{
+		BaseRepository: repo,
+		backend:        backend,
+		prefix:         prefix + ":" + entity.TableName(),
+		ttl:            ttl,
+	}
+}
+
+// Create создает запись и сбрасывает кэш выборок
+func (r *CachedRepository[T]) Create(ctx context.Context, entity T) (T, error) {
+	created, err := r.BaseRepository.Create(ctx, entity)
+	if err != nil {
+		return created, err
+	}
+	r.invalidate(ctx)
+	return created, nil
+}
+
+// Update обновляет запись и сбрасывает её кэш
+func (r *CachedRepository[T]) Update(ctx context.Context, entity T) (T, error) {
+	updated, err := r.BaseRepository.Update(ctx, entity)
+	if err != nil {
+		return updated, err
+	}
+	r.invalidate(ctx, entity.GetID())
+	return updated, nil
+}
+
+// Delete удаляет запись и сбрасывает её кэш
+func (r *CachedRepository[T]) Delete(ctx context.Context, entity T) error {
+	if err := r.BaseRepository.Delete(ctx, entity); err != nil {
+		return err
+	}
+	r.invalidate(ctx, entity.GetID())
+	return nil
+}
+
+// Restore восстанавливает запись и сбрасывает кэш выборок
+func (r *CachedRepository[T]) Restore(ctx context.Context, entity T) (T, error) {
+	restored, err := r.BaseRepository.Restore(ctx, entity)
+	if err != nil {
+		return restored, err
+	}
+	r.invalidate(ctx, entity.GetID())
+	return restored, nil
+}
+
+// Purge безвозвратно удаляет запись и сбрасывает её кэш
+func (r *CachedRepository[T]) Purge(ctx context.Context, entity T) error {
+	if err := r.BaseRepository.Purge(ctx, entity); err != nil {
+		return err
+	}
+	r.invalidate(ctx, entity.GetID())
+	return nil
+}
+
+// CreateMany создает записи и сбрасывает кэш выборок
+func (r *CachedRepository[T]) CreateMany(ctx context.Context, entities []T) ([]T, error) {
+	created, err := r.BaseRepository.CreateMany(ctx, entities)
+	if err != nil {
+		return created, err
+	}
+	r.invalidate(ctx)
+	return created, nil
+}
+
+// UpdateMany обновляет записи и сбрасывает их кэш
+func (r *CachedRepository[T]) UpdateMany(ctx context.Context, entities []T) ([]T, error) {
+	updated, err := r.BaseRepository.UpdateMany(ctx, entities)
+	if err != nil {
+		return updated, err
+	}
+	r.invalidate(ctx, entityIDs(entities)...)
+	return updated, nil
+}
+
+// DeleteMany удаляет записи и сбрасывает их кэш
+func (r *CachedRepository[T]) DeleteMany(ctx context.Context, entities []T) error {
+	if err := r.BaseRepository.DeleteMany(ctx, entities); err != nil {
+		return err
+	}
+	r.invalidate(ctx, entityIDs(entities)...)
+	return nil
+}
+
+// FindByID ищет запись по ID в кэше, при промахе - в базе
+func (r *CachedRepository[T]) FindByID(ctx context.Context, id uint) (T, error) {
+	if !isCacheable(ctx) {
+		return r.BaseRepository.FindByID(ctx, id)
+	}
+
+	key := r.idKey(id)
+	var entity T
+	if r.read(ctx, key, &entity) {
+		return entity, nil
+	}
+
+	entity, err := r.BaseRepository.FindByID(ctx, id)
+	if err != nil {
+		return entity, err
+	}
+	r.write(ctx, key, entity)
+	return entity, nil
+}
+
+// FindByIDs берет из кэша найденные записи, остальные запрашивает одним запросом
+func (r *CachedRepository[T]) FindByIDs(ctx context.Context, ids []uint) ([]T, error) {
+	if !isCacheable(ctx) {
+		return r.BaseRepository.FindByIDs(ctx, ids)
+	}
+
+	entities := make([]T, 0, len(ids))
+	var missing []uint
+	for _, id := range ids {
+		var entity T
+		if r.read(ctx, r.idKey(id), &entity) {
+			entities = append(entities, entity)
+			continue
+		}
+		missing = append(missing, id)
+	}
+	if len(missing) == 0 {
+		return entities, nil
+	}
+
+	found, err := r.BaseRepository.FindByIDs(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for _, entity := range found {
+		r.write(ctx, r.idKey(entity.GetID()), entity)
+	}
+	return append(entities, found...), nil
+}
+
+// FindAll ищет все записи через кэш выборок
+func (r *CachedRepository[T]) FindAll(ctx context.Context) ([]T, error) {
+	return CachedQuery(ctx, r, "all", nil, r.BaseRepository.FindAll)
+}
+
+// FindWithSearchCriteria ищет записи по критериям через кэш выборок
+func (r *CachedRepository[T]) FindWithSearchCriteria(ctx context.Context, criteria SearchCriteria) ([]T, error) {
+	return CachedQuery(ctx, r, "search", criteria, func(ctx context.Context) ([]T, error) {
+		return r.BaseRepository.FindWithSearchCriteria(ctx, criteria)
+	})
+}
+
+// Count считает записи по критериям через кэш выборок
+func (r *CachedRepository[T]) Count(ctx context.Context, criteria SearchCriteria) (int64, error) {
+	return CachedQuery(ctx, r, "count", criteria, func(ctx context.Context) (int64, error) {
+		return r.BaseRepository.Count(ctx, criteria)
+	})
+}
+
+// CachedQuery выполняет выборку через кэш выборок репозитория. Ключ строится из имени
+// операции и хэша аргументов. Используется и для собственных выборок репозиториев сущностей
+func CachedQuery[T BaseEntity, R any](
+	ctx context.Context,
+	r *CachedRepository[T],
+	operation string,
+	args any,
+	load func(ctx context.Context) (R, error),
+) (R, error) {
+	if !isCacheable(ctx) {
+		return load(ctx)
+	}
+
+	key, err := r.queryKey(ctx, operation, args)
+	if err != nil {
+		slog.Warn("Failed to build repository cache key", "prefix", r.prefix, "operation", operation, "err", err)
+		return load(ctx)
+	}
+
+	var result R
+	if r.read(ctx, key, &result) {
+		return result, nil
+	}
+
+	result, err = load(ctx)
+	if err != nil {
+		return result, err
+	}
+	r.write(ctx, key, result)
+	return result, nil
+}
+
+// isCacheable кэш не используется внутри транзакции (возможны незафиксированные данные)
+// и при выборках, отличных от выборки по умолчанию без мягко удаленных записей
+func isCacheable(ctx context.Context) bool {
+	if _, ok := ctx.Value(TxCtxKeyCode).(*gorm.DB); ok {
+		return false
+	}
+	return deletedScopeFromCtx(ctx) == DeletedExclude
+}
+
+func (r *CachedRepository[T]) idKey(id uint) string {
+	return r.prefix + ":id:" + strconv.FormatUint(uint64(id), 10)
+}
+
+func (r *CachedRepository[T]) generationKey() string {
+	return r.prefix + ":gen"
+}
+
+func (r *CachedRepository[T]) queryKey(ctx context.Context, operation string, args any) (string, error) {
+	generation := int64(0)
+	raw, ok, err := r.backend.Get(ctx, r.generationKey())
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		if generation, err = strconv.ParseInt(string(raw), 10, 64); err != nil {
+			return "", err
+		}
+	}
+
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(append([]byte(operation+":"), data...))
+	return fmt.Sprintf("%s:q:%d:%s", r.prefix, generation, hex.EncodeToString(hash[:16])), nil
+}
+
+// read читает значение из кэша. Ошибки хранилища не прерывают запрос, а приводят к чтению из базы
+func (r *CachedRepository[T]) read(ctx context.Context, key string, dest any) bool {
+	raw, ok, err := r.backend.Get(ctx, key)
+	if err != nil {
+		slog.Warn("Failed to read repository cache", "key", key, "err", err)
+		return false
+	}
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal(raw, dest); err != nil {
+		slog.Warn("Failed to decode repository cache value", "key", key, "err", err)
+		return false
+	}
+	return true
+}
+
+func (r *CachedRepository[T]) write(ctx context.Context, key string, value any) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		slog.Warn("Failed to encode repository cache value", "key", key, "err", err)
+		return
+	}
+	if err := r.backend.Set(ctx, key, raw, r.ttl); err != nil {
+		slog.Warn("Failed to write repository cache", "key", key, "err", err)
+	}
+}
+
+// invalidate сбрасывает кэш переданных ID и все выборки сущности. Если изменение выполнено
+// в транзакции, сброс откладывается до её фиксации, иначе параллельный запрос мог бы
+// закэшировать прежние данные уже после сброса
+func (r *CachedRepository[T]) invalidate(ctx context.Context, ids ...uint) {
+	AfterCommit(ctx, func(ctx context.Context) error {
+		r.evict(ctx, ids...)
+		return nil
+	})
+}
+
+func (r *CachedRepository[T]) evict(ctx context.Context, ids ...uint) {
+	// изменение уже выполнено, поэтому сброс кэша не должен зависеть от отмены запроса
+	ctx = context.WithoutCancel(ctx)
+
+	if len(ids) > 0 {
+		keys := make([]string, 0, len(ids))
+		for _, id := range ids {
+			keys = append(keys, r.idKey(id))
+		}
+		if err := r.backend.Delete(ctx, keys...); err != nil {
+			slog.Error("Failed to invalidate repository cache", "prefix", r.prefix, "ids", ids, "err", err)
+		}
+	}
+	if _, err := r.backend.Incr(ctx, r.generationKey()); err != nil {
+		slog.Error("Failed to invalidate repository cache queries", "prefix", r.prefix, "err", err)
+	}
+}
+
+func entityIDs[T BaseEntity](entities []T) []uint {
+	ids := make([]uint, 0, len(entities))
+	for _, entity := range entities {
+		ids = append(ids, entity.GetID())
+	}
+	return ids
+}
@@ -0,0 +1,266 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// testTxContext имитирует контекст открытой транзакции TxManager. Возвращает функции
+// фиксации и отката, которые выполняют зарегистрированные действия AfterCommit и AfterRollback
+func testTxContext(ctx context.Context) (context.Context, func(), func()) {
+	hooks := &txHooks{}
+	ctx = context.WithValue(ctx, TxCtxKeyCode, &gorm.DB{})
+	ctx = context.WithValue(ctx, txStateCtxKey, &txState{hooks: hooks})
+	commit := func() { hooks.runAfterCommit(context.Background()) }
+	rollback := func() { hooks.runAfterRollback(context.Background()) }
+	return ctx, commit, rollback
+}
+
+func TestCachedRepositoryFindByIDReadsThrough(t *testing.T) {
+	for name, backend := range testCacheBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newMemoryRepository(newTestEntity(1, "first"))
+			cached := NewCachedRepository[testEntity](repo, backend, "test", time.Minute)
+
+			entity, err := cached.FindByID(ctx, 1)
+			require.NoError(t, err)
+			assert.Equal(t, "first", entity.Name)
+			assert.Equal(t, 1, repo.findByIDCalls, "промах должен читать из базы")
+
+			entity, err = cached.FindByID(ctx, 1)
+			require.NoError(t, err)
+			assert.Equal(t, "first", entity.Name)
+			assert.Equal(t, 1, repo.findByIDCalls, "попадание не должно читать из базы")
+		})
+	}
+}
+
+func TestCachedRepositoryDoesNotCacheMisses(t *testing.T) {
+	for name, backend := range testCacheBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newMemoryRepository()
+			cached := NewCachedRepository[testEntity](repo, backend, "test", time.Minute)
+
+			_, err := cached.FindByID(ctx, 7)
+			assert.ErrorIs(t, err, &NotFoundError{})
+
+			repo.rows[7] = newTestEntity(7, "created")
+			entity, err := cached.FindByID(ctx, 7)
+			require.NoError(t, err)
+			assert.Equal(t, "created", entity.Name)
+		})
+	}
+}
+
+func TestCachedRepositoryFindByIDsLoadsOnlyMissing(t *testing.T) {
+	for name, backend := range testCacheBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newMemoryRepository(newTestEntity(1, "first"), newTestEntity(2, "second"))
+			cached := NewCachedRepository[testEntity](repo, backend, "test", time.Minute)
+
+			_, err := cached.FindByID(ctx, 1)
+			require.NoError(t, err)
+
+			entities, err := cached.FindByIDs(ctx, []uint{1, 2})
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []uint{1, 2}, entityIDs(entities))
+			assert.Equal(t, 2, repo.findByIDCalls)
+
+			_, err = cached.FindByIDs(ctx, []uint{1, 2})
+			require.NoError(t, err)
+			assert.Equal(t, 2, repo.findByIDCalls, "обе записи уже должны быть в кэше")
+		})
+	}
+}
+
+func TestCachedRepositoryUpdateEvictsEntity(t *testing.T) {
+	for name, backend := range testCacheBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newMemoryRepository(newTestEntity(1, "first"))
+			cached := NewCachedRepository[testEntity](repo, backend, "test", time.Minute)
+
+			entity, err := cached.FindByID(ctx, 1)
+			require.NoError(t, err)
+
+			entity.Name = "renamed"
+			_, err = cached.Update(ctx, entity)
+			require.NoError(t, err)
+
+			entity, err = cached.FindByID(ctx, 1)
+			require.NoError(t, err)
+			assert.Equal(t, "renamed", entity.Name)
+			assert.Equal(t, 2, repo.findByIDCalls)
+		})
+	}
+}
+
+func TestCachedRepositoryWriteBumpsGeneration(t *testing.T) {
+	for name, backend := range testCacheBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newMemoryRepository(newTestEntity(1, "first"))
+			cached := NewCachedRepository[testEntity](repo, backend, "test", time.Minute)
+			criteria := SearchCriteria{}
+
+			for range 2 {
+				entities, err := cached.FindWithSearchCriteria(ctx, criteria)
+				require.NoError(t, err)
+				assert.Len(t, entities, 1)
+				count, err := cached.Count(ctx, criteria)
+				require.NoError(t, err)
+				assert.Equal(t, int64(1), count)
+			}
+			assert.Equal(t, 1, repo.searchCalls)
+			assert.Equal(t, 1, repo.countCalls)
+
+			_, err := cached.Create(ctx, testEntity{Name: "second"})
+			require.NoError(t, err)
+
+			generation, ok, err := backend.Get(ctx, cached.generationKey())
+			require.NoError(t, err)
+			require.True(t, ok)
+			assert.Equal(t, "1", string(generation))
+
+			entities, err := cached.FindWithSearchCriteria(ctx, criteria)
+			require.NoError(t, err)
+			assert.Len(t, entities, 2, "выборка прошлого поколения не должна использоваться")
+			count, err := cached.Count(ctx, criteria)
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), count)
+			assert.Equal(t, 2, repo.searchCalls)
+			assert.Equal(t, 2, repo.countCalls)
+		})
+	}
+}
+
+func TestCachedRepositoryBypassesCacheInTransaction(t *testing.T) {
+	for name, backend := range testCacheBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			repo := newMemoryRepository(newTestEntity(1, "first"))
+			cached := NewCachedRepository[testEntity](repo, backend, "test", time.Minute)
+			txCtx, _, _ := testTxContext(context.Background())
+
+			for range 2 {
+				_, err := cached.FindByID(txCtx, 1)
+				require.NoError(t, err)
+				_, err = cached.FindWithSearchCriteria(txCtx, SearchCriteria{})
+				require.NoError(t, err)
+			}
+			assert.Equal(t, 2, repo.findByIDCalls)
+			assert.Equal(t, 2, repo.searchCalls)
+
+			_, ok, err := backend.Get(context.Background(), cached.idKey(1))
+			require.NoError(t, err)
+			assert.False(t, ok, "данные, прочитанные в транзакции, не должны попадать в кэш")
+		})
+	}
+}
+
+func TestCachedRepositoryBypassesCacheForDeletedScope(t *testing.T) {
+	for name, backend := range testCacheBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			repo := newMemoryRepository(newTestEntity(1, "first"))
+			cached := NewCachedRepository[testEntity](repo, backend, "test", time.Minute)
+
+			for _, ctx := range []context.Context{WithDeleted(context.Background()), OnlyDeleted(context.Background())} {
+				for range 2 {
+					_, err := cached.FindByID(ctx, 1)
+					require.NoError(t, err)
+					_, err = cached.FindWithSearchCriteria(ctx, SearchCriteria{})
+					require.NoError(t, err)
+				}
+			}
+			assert.Equal(t, 4, repo.findByIDCalls)
+			assert.Equal(t, 4, repo.searchCalls)
+		})
+	}
+}
+
+func TestCachedRepositoryInvalidatesAfterCommit(t *testing.T) {
+	for name, backend := range testCacheBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newMemoryRepository(newTestEntity(1, "first"))
+			cached := NewCachedRepository[testEntity](repo, backend, "test", time.Minute)
+
+			entity, err := cached.FindByID(ctx, 1)
+			require.NoError(t, err)
+
+			txCtx, commit, _ := testTxContext(ctx)
+			entity.Name = "renamed"
+			_, err = cached.Update(txCtx, entity)
+			require.NoError(t, err)
+
+			entity, err = cached.FindByID(ctx, 1)
+			require.NoError(t, err)
+			assert.Equal(t, "first", entity.Name, "до фиксации кэш не сбрасывается")
+
+			commit()
+			entity, err = cached.FindByID(ctx, 1)
+			require.NoError(t, err)
+			assert.Equal(t, "renamed", entity.Name)
+		})
+	}
+}
+
+func TestCachedRepositoryKeepsCacheAfterRollback(t *testing.T) {
+	for name, backend := range testCacheBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newMemoryRepository(newTestEntity(1, "first"))
+			cached := NewCachedRepository[testEntity](repo, backend, "test", time.Minute)
+
+			_, err := cached.FindWithSearchCriteria(ctx, SearchCriteria{})
+			require.NoError(t, err)
+
+			txCtx, _, rollback := testTxContext(ctx)
+			_, err = cached.Create(txCtx, testEntity{Name: "second"})
+			require.NoError(t, err)
+			rollback()
+
+			_, ok, err := backend.Get(ctx, cached.generationKey())
+			require.NoError(t, err)
+			assert.False(t, ok, "при откате поколение не увеличивается")
+		})
+	}
+}
+
+func TestCachedRepositoryFallsBackToRepositoryWhenRedisIsDown(t *testing.T) {
+	ctx := context.Background()
+	backend, server := newTestRedisCache(t)
+	repo := newMemoryRepository(newTestEntity(1, "first"))
+	cached := NewCachedRepository[testEntity](repo, backend, "test", time.Minute)
+	server.Close()
+
+	entity, err := cached.FindByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "first", entity.Name)
+
+	entities, err := cached.FindWithSearchCriteria(ctx, SearchCriteria{})
+	require.NoError(t, err)
+	assert.Len(t, entities, 1)
+}
+
+func TestCachedRepositoryEntriesExpireInRedis(t *testing.T) {
+	ctx := context.Background()
+	backend, server := newTestRedisCache(t)
+	repo := newMemoryRepository(newTestEntity(1, "first"))
+	cached := NewCachedRepository[testEntity](repo, backend, "test", time.Minute)
+
+	_, err := cached.FindByID(ctx, 1)
+	require.NoError(t, err)
+	server.FastForward(2 * time.Minute)
+
+	_, err = cached.FindByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, repo.findByIDCalls)
+}
@@ -0,0 +1,230 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+type CircuitState string
+
+const (
+	// CircuitClosed вызовы проходят, неудачи подряд подсчитываются
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen вызовы сразу отклоняются до окончания cooldown
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen пропускается ограниченное число пробных вызовов
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// ErrCircuitOpen вызов отклонен без обращения к сервису, потому что размыкатель разомкнут
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitBreakerStats состояние размыкателя и счетчики вызовов
+type CircuitBreakerStats struct {
+	Name                string       `json:"name"`
+	State               CircuitState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	Successes           int64        `json:"successes"`
+	Failures            int64        `json:"failures"`
+	Rejected            int64        `json:"rejected"`
+	OpenedAt            *time.Time   `json:"opened_at,omitempty"`
+}
+
+// CircuitBreaker размыкатель цепи вокруг внешнего сервиса. После failureThreshold неудач подряд
+// размыкается и отклоняет вызовы в течение cooldown, затем пропускает halfOpenCalls пробных
+// вызовов: их успех замыкает цепь, любая неудача снова размыкает
+type CircuitBreaker struct {
+	mu sync.Mutex
+
+	name             string
+	failureThreshold int
+	cooldown         time.Duration
+	halfOpenCalls    int
+	callTimeout      time.Duration
+	isFailure        func(err error) bool
+
+	state               CircuitState
+	generation          uint64
+	consecutiveFailures int
+	halfOpenInFlight    int
+	halfOpenSuccesses   int
+	openedAt            time.Time
+
+	successes int64
+	failures  int64
+	rejected  int64
+}
+
+func NewCircuitBreaker(name string, options ...CircuitBreakerOption) *CircuitBreaker {
+	cb := &CircuitBreaker{
+		name:             name,
+		failureThreshold: 5,
+		cooldown:         30 * time.Second,
+		halfOpenCalls:    1,
+		isFailure:        isCircuitFailure,
+		state:            CircuitClosed,
+	}
+	for _, option := range options {
+		option(cb)
+	}
+	return cb
+}
+
+// CallWithBreaker выполняет fn через размыкатель. Если цепь разомкнута, возвращает ErrCircuitOpen
+// без вызова fn. Вызов ограничивается callTimeout, превышение считается неудачей
+func CallWithBreaker[T any](ctx context.Context, cb *CircuitBreaker, fn func(ctx context.Context) (T, error)) (T, error) {
+	var empty T
+	generation, err := cb.allow()
+	if err != nil {
+		return empty, err
+	}
+
+	callCtx := ctx
+	if cb.callTimeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, cb.callTimeout)
+		defer cancel()
+	}
+
+	res, err := fn(callCtx)
+	// отмена запроса вызывающей стороной не говорит о состоянии сервиса
+	cb.record(generation, err, ctx.Err() != nil)
+	return res, err
+}
+
+// Stats возвращает текущее состояние размыкателя
+func (cb *CircuitBreaker) Stats() CircuitBreakerStats {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	stats := CircuitBreakerStats{
+		Name:                cb.name,
+		State:               cb.state,
+		ConsecutiveFailures: cb.consecutiveFailures,
+		Successes:           cb.successes,
+		Failures:            cb.failures,
+		Rejected:            cb.rejected,
+	}
+	if cb.state != CircuitClosed {
+		openedAt := cb.openedAt
+		stats.OpenedAt = &openedAt
+	}
+	return stats
+}
+
+func (cb *CircuitBreaker) allow() (uint64, error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == CircuitOpen && time.Since(cb.openedAt) >= cb.cooldown {
+		cb.setState(CircuitHalfOpen)
+	}
+
+	switch cb.state {
+	case CircuitOpen:
+		cb.rejected++
+		return 0, ErrCircuitOpen
+	case CircuitHalfOpen:
+		if cb.halfOpenInFlight >= cb.halfOpenCalls {
+			cb.rejected++
+			return 0, ErrCircuitOpen
+		}
+		cb.halfOpenInFlight++
+	}
+	return cb.generation, nil
+}
+
+// record учитывает результат вызова. Результаты вызовов, начатых до смены состояния, не учитываются
+func (cb *CircuitBreaker) record(generation uint64, err error, cancelled bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if generation != cb.generation {
+		return
+	}
+	if cb.state == CircuitHalfOpen {
+		cb.halfOpenInFlight--
+	}
+	if cancelled {
+		return
+	}
+
+	if err != nil && cb.isFailure(err) {
+		cb.failures++
+		cb.consecutiveFailures++
+		if cb.state == CircuitHalfOpen || cb.consecutiveFailures >= cb.failureThreshold {
+			cb.setState(CircuitOpen)
+			slog.Warn("Circuit breaker opened", "name", cb.name, "consecutive_failures", cb.consecutiveFailures, "cooldown", cb.cooldown, "err", err)
+		}
+		return
+	}
+
+	cb.successes++
+	cb.consecutiveFailures = 0
+	if cb.state == CircuitHalfOpen {
+		cb.halfOpenSuccesses++
+		if cb.halfOpenSuccesses >= cb.halfOpenCalls {
+			cb.setState(CircuitClosed)
+			slog.Info("Circuit breaker closed", "name", cb.name)
+		}
+	}
+}
+
+func (cb *CircuitBreaker) setState(state CircuitState) {
+	cb.state = state
+	cb.generation++
+	cb.halfOpenInFlight = 0
+	cb.halfOpenSuccesses = 0
+	switch state {
+	case CircuitOpen:
+		cb.openedAt = time.Now()
+	case CircuitClosed:
+		cb.consecutiveFailures = 0
+	}
+}
+
+// isCircuitFailure классификатор по умолчанию: сбоем считаются временные ошибки и превышение времени вызова
+func isCircuitFailure(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) || IsRetryable(err)
+}
+
+type CircuitBreakerOption func(*CircuitBreaker)
+
+// SetFailureThresholdOpt количество неудач подряд, после которого цепь размыкается
+func SetFailureThresholdOpt(failureThreshold int) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.failureThreshold = max(failureThreshold, 1)
+	}
+}
+
+// SetCooldownOpt время, в течение которого разомкнутая цепь отклоняет вызовы
+func SetCooldownOpt(cooldown time.Duration) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.cooldown = cooldown
+	}
+}
+
+// SetHalfOpenCallsOpt количество пробных вызовов, успех которых замыкает цепь
+func SetHalfOpenCallsOpt(halfOpenCalls int) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.halfOpenCalls = max(halfOpenCalls, 1)
+	}
+}
+
+// SetCallTimeoutOpt предел длительности одного вызова. 0 - без ограничения
+func SetCallTimeoutOpt(callTimeout time.Duration) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.callTimeout = callTimeout
+	}
+}
+
+// SetFailureClassifierOpt классификатор ошибок: true - ошибка говорит о сбое сервиса.
+// Остальные ошибки (неверные данные, отказ в доступе) считаются успешным ответом
+func SetFailureClassifierOpt(isFailure func(err error) bool) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.isFailure = isFailure
+	}
+}
@@ -0,0 +1,193 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func callBreaker(cb *CircuitBreaker, err error) error {
+	_, callErr := CallWithBreaker(context.Background(), cb, func(context.Context) (struct{}, error) {
+		return struct{}{}, err
+	})
+	return callErr
+}
+
+// expireCooldown переносит момент размыкания так, будто cooldown уже истек
+func expireCooldown(cb *CircuitBreaker) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.openedAt = time.Now().Add(-cb.cooldown)
+}
+
+func openBreaker(t *testing.T, cb *CircuitBreaker) {
+	t.Helper()
+	for range cb.failureThreshold {
+		_ = callBreaker(cb, errTemporary)
+	}
+	require.Equal(t, CircuitOpen, cb.Stats().State)
+}
+
+func TestCircuitBreakerOpensAfterThreshold(t *testing.T) {
+	cb := NewCircuitBreaker("test", SetFailureThresholdOpt(3), SetCooldownOpt(time.Hour))
+
+	assert.ErrorIs(t, callBreaker(cb, errTemporary), errTemporary)
+	assert.ErrorIs(t, callBreaker(cb, errTemporary), errTemporary)
+	assert.Equal(t, CircuitClosed, cb.Stats().State)
+	assert.Equal(t, 2, cb.Stats().ConsecutiveFailures)
+
+	assert.ErrorIs(t, callBreaker(cb, errTemporary), errTemporary)
+	stats := cb.Stats()
+	assert.Equal(t, CircuitOpen, stats.State)
+	assert.Equal(t, int64(3), stats.Failures)
+	require.NotNil(t, stats.OpenedAt)
+
+	called := false
+	_, err := CallWithBreaker(context.Background(), cb, func(context.Context) (int, error) {
+		called = true
+		return 0, nil
+	})
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.False(t, called)
+	assert.Equal(t, int64(1), cb.Stats().Rejected)
+}
+
+func TestCircuitBreakerSuccessResetsFailures(t *testing.T) {
+	cb := NewCircuitBreaker("test", SetFailureThresholdOpt(2))
+
+	_ = callBreaker(cb, errTemporary)
+	assert.NoError(t, callBreaker(cb, nil))
+	_ = callBreaker(cb, errTemporary)
+
+	stats := cb.Stats()
+	assert.Equal(t, CircuitClosed, stats.State)
+	assert.Equal(t, 1, stats.ConsecutiveFailures)
+	assert.Equal(t, int64(1), stats.Successes)
+}
+
+func TestCircuitBreakerIgnoresNonFailureErrors(t *testing.T) {
+	cb := NewCircuitBreaker("test", SetFailureThresholdOpt(1))
+
+	validationErr := NewValidationError(nil, ValidationErrorCode, "invalid")
+	assert.ErrorIs(t, callBreaker(cb, validationErr), validationErr)
+
+	stats := cb.Stats()
+	assert.Equal(t, CircuitClosed, stats.State)
+	assert.Equal(t, int64(1), stats.Successes)
+	assert.Zero(t, stats.Failures)
+}
+
+func TestCircuitBreakerHalfOpenAfterCooldown(t *testing.T) {
+	cb := NewCircuitBreaker("test", SetFailureThresholdOpt(1), SetCooldownOpt(time.Hour))
+	openBreaker(t, cb)
+	assert.ErrorIs(t, callBreaker(cb, nil), ErrCircuitOpen)
+
+	expireCooldown(cb)
+	assert.NoError(t, callBreaker(cb, nil))
+
+	stats := cb.Stats()
+	assert.Equal(t, CircuitClosed, stats.State)
+	assert.Nil(t, stats.OpenedAt)
+}
+
+func TestCircuitBreakerHalfOpenFailureReopens(t *testing.T) {
+	cb := NewCircuitBreaker("test", SetFailureThresholdOpt(3), SetCooldownOpt(time.Hour))
+	openBreaker(t, cb)
+
+	expireCooldown(cb)
+	// в полуоткрытом состоянии цепь размыкает первая же неудача, без учета порога
+	assert.ErrorIs(t, callBreaker(cb, errTemporary), errTemporary)
+	assert.Equal(t, CircuitOpen, cb.Stats().State)
+	assert.ErrorIs(t, callBreaker(cb, nil), ErrCircuitOpen)
+}
+
+func TestCircuitBreakerHalfOpenProbeLimit(t *testing.T) {
+	cb := NewCircuitBreaker("test", SetFailureThresholdOpt(1), SetCooldownOpt(time.Hour), SetHalfOpenCallsOpt(2))
+	openBreaker(t, cb)
+	expireCooldown(cb)
+
+	first, err := cb.allow()
+	require.NoError(t, err)
+	second, err := cb.allow()
+	require.NoError(t, err)
+	assert.Equal(t, CircuitHalfOpen, cb.Stats().State)
+
+	// пока пробные вызовы не завершились, остальные отклоняются
+	_, err = cb.allow()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	cb.record(first, nil, false)
+	assert.Equal(t, CircuitHalfOpen, cb.Stats().State)
+	cb.record(second, nil, false)
+	assert.Equal(t, CircuitClosed, cb.Stats().State)
+}
+
+func TestCircuitBreakerDiscardsOlderGenerationResults(t *testing.T) {
+	cb := NewCircuitBreaker("test", SetFailureThresholdOpt(1), SetCooldownOpt(time.Hour))
+
+	// вызов начат, пока цепь была замкнута, и завершился после ее размыкания
+	slow, err := cb.allow()
+	require.NoError(t, err)
+	openBreaker(t, cb)
+	cb.record(slow, nil, false)
+	assert.Equal(t, CircuitOpen, cb.Stats().State)
+
+	// пробный вызов начат до повторного размыкания, его успех не замыкает новую цепь
+	expireCooldown(cb)
+	probe, err := cb.allow()
+	require.NoError(t, err)
+	cb.mu.Lock()
+	cb.setState(CircuitOpen)
+	cb.mu.Unlock()
+	cb.record(probe, nil, false)
+
+	stats := cb.Stats()
+	assert.Equal(t, CircuitOpen, stats.State)
+	assert.Zero(t, stats.Successes)
+}
+
+func TestCircuitBreakerIgnoresCallerCancellation(t *testing.T) {
+	cb := NewCircuitBreaker("test", SetFailureThresholdOpt(1), SetCooldownOpt(time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := CallWithBreaker(ctx, cb, func(ctx context.Context) (int, error) {
+		cancel()
+		return 0, errTemporary
+	})
+	assert.ErrorIs(t, err, errTemporary)
+
+	stats := cb.Stats()
+	assert.Equal(t, CircuitClosed, stats.State)
+	assert.Zero(t, stats.Failures)
+	assert.Zero(t, stats.ConsecutiveFailures)
+}
+
+func TestCircuitBreakerCancelledProbeFreesSlot(t *testing.T) {
+	cb := NewCircuitBreaker("test", SetFailureThresholdOpt(1), SetCooldownOpt(time.Hour))
+	openBreaker(t, cb)
+	expireCooldown(cb)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, _ = CallWithBreaker(ctx, cb, func(ctx context.Context) (int, error) {
+		cancel()
+		return 0, ctx.Err()
+	})
+	assert.Equal(t, CircuitHalfOpen, cb.Stats().State)
+
+	assert.NoError(t, callBreaker(cb, nil))
+	assert.Equal(t, CircuitClosed, cb.Stats().State)
+}
+
+func TestCircuitBreakerCallTimeoutIsFailure(t *testing.T) {
+	cb := NewCircuitBreaker("test", SetFailureThresholdOpt(1), SetCallTimeoutOpt(10*time.Millisecond))
+
+	_, err := CallWithBreaker(context.Background(), cb, func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, CircuitOpen, cb.Stats().State)
+}
@@ -0,0 +1,242 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// DTOMapper преобразует сущность в DTO ответа
+type DTOMapper[T BaseEntity, D any] func(ctx context.Context, entity T) (D, error)
+
+// MapWith оборачивает преобразование, не требующее обращений к сервисам, в DTOMapper
+func MapWith[T BaseEntity, D any](toDTO func(T) D) DTOMapper[T, D] {
+	return func(_ context.Context, entity T) (D, error) {
+		return toDTO(entity), nil
+	}
+}
+
+// ListMapper преобразует список сущностей в DTO целиком, позволяя получить
+// связанные данные одним запросом на весь список
+type ListMapper[T BaseEntity, D any] func(ctx context.Context, entities []T) ([]D, error)
+
+// CrudService сервис со стандартными операциями, которые обслуживает CrudHandler
+type CrudService[T BaseEntity] interface {
+	BaseService[T]
+
+	Delete(ctx context.Context, entity T) error
+}
+
+// CrudHandler реализует стандартные эндпоинты сущности: получение по ID, список, поиск,
+// удаление, восстановление и безвозвратное удаление. Пакеты сущностей пишут только
+// нестандартные эндпоинты, а стандартные делегируют этому обработчику
+type CrudHandler[T BaseEntity, D any] struct {
+	code     string
+	validate *validator.Validate
+	service  CrudService[T]
+	toDTO    DTOMapper[T, D]
+	toDTOs   ListMapper[T, D]
+}
+
+func NewCrudHandler[T BaseEntity, D any](
+	code string,
+	validate *validator.Validate,
+	service CrudService[T],
+	toDTO DTOMapper[T, D],
+) *CrudHandler[T, D] {
+	return &CrudHandler[T, D]{
+		code:     code,
+		validate: validate,
+		service:  service,
+		toDTO:    toDTO,
+	}
+}
+
+// WithListMapper задает преобразование списков, которое используется вместо
+// поэлементного вызова DTOMapper
+func (h *CrudHandler[T, D]) WithListMapper(toDTOs ListMapper[T, D]) *CrudHandler[T, D] {
+	h.toDTOs = toDTOs
+	return h
+}
+
+// GetById возвращает сущность по ID из пути вместе с ETag версии
+func (h *CrudHandler[T, D]) GetById(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := PathID(r, "id")
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	entity, err := h.service.GetByID(ctx, id)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	h.WriteEntity(w, r, http.StatusOK, entity)
+}
+
+// GetAll возвращает страницу сущностей по фильтру и сортировке из строки запроса
+func (h *CrudHandler[T, D]) GetAll(w http.ResponseWriter, r *http.Request) {
+	criteria, err := ParseSearchQuery(r.URL.RawQuery)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	h.writePage(w, r, criteria)
+}
+
+// GetWithSearchCriteria возвращает страницу сущностей по критериям поиска из тела запроса
+func (h *CrudHandler[T, D]) GetWithSearchCriteria(w http.ResponseWriter, r *http.Request) {
+	var req SearchCriteria
+	if err := DecodeJSON(r, &req); err != nil {
+		HandleError(w, r, NewTechnicalError(err, h.code, err.Error()))
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		details := CollectValidationDetails(r.Context(), err)
+		HandleValidationError(w, r, NewLogicalError(err, h.code, err.Error()), details)
+		return
+	}
+
+	h.writePage(w, r, req)
+}
+
+// Delete удаляет сущность по ID из пути
+func (h *CrudHandler[T, D]) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := PathID(r, "id")
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	entity, err := h.service.GetByID(ctx, id)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	if err := h.service.Delete(ctx, entity); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Restore восстанавливает мягко удаленную сущность по ID из пути
+func (h *CrudHandler[T, D]) Restore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := PathID(r, "id")
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	entity, err := h.service.Restore(ctx, id)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	h.WriteEntity(w, r, http.StatusOK, entity)
+}
+
+// Purge безвозвратно удаляет сущность по ID из пути
+func (h *CrudHandler[T, D]) Purge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := PathID(r, "id")
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	if err := h.service.Purge(ctx, id); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// WriteEntity отвечает DTO сущности с ETag версии
+func (h *CrudHandler[T, D]) WriteEntity(w http.ResponseWriter, r *http.Request, status int, entity T) {
+	dto, err := h.toDTO(r.Context(), entity)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	SetETag(w, entity)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(dto)
+}
+
+// WriteList отвечает списком DTO сущностей
+func (h *CrudHandler[T, D]) WriteList(w http.ResponseWriter, r *http.Request, entities []T) {
+	dtos, err := h.ToDTOs(r.Context(), entities)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dtos)
+}
+
+// ToDTOs преобразует список сущностей в DTO
+func (h *CrudHandler[T, D]) ToDTOs(ctx context.Context, entities []T) ([]D, error) {
+	if h.toDTOs != nil {
+		return h.toDTOs(ctx, entities)
+	}
+	dtos := make([]D, 0, len(entities))
+	for _, entity := range entities {
+		dto, err := h.toDTO(ctx, entity)
+		if err != nil {
+			return nil, err
+		}
+		dtos = append(dtos, dto)
+	}
+	return dtos, nil
+}
+
+func (h *CrudHandler[T, D]) writePage(w http.ResponseWriter, r *http.Request, criteria SearchCriteria) {
+	ctx := r.Context()
+
+	page, err := h.service.GetPageWithSearchCriteria(ctx, criteria)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	dtos, err := h.ToDTOs(ctx, page.Items)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(MapPage(page, dtos))
+}
+
+// PathID читает числовой идентификатор из параметра пути
+func PathID(r *http.Request, name string) (uint, error) {
+	raw := r.PathValue(name)
+	if raw == "" {
+		return 0, NewLogicalError(nil, PathParamMissingCode, "Отсутствует параметр %s", name)
+	}
+	id, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return 0, NewLogicalError(err, PathParamInvalidCode, "Параметр %s должен быть числовым", name)
+	}
+	return uint(id), nil
+}
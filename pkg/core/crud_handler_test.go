@@ -0,0 +1,221 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// crudTestRepository репозиторий в памяти с корзиной мягко удаленных записей
+type crudTestRepository struct {
+	*memoryRepository
+
+	deleted map[uint]testEntity
+}
+
+func (r crudTestRepository) FindByID(ctx context.Context, id uint) (testEntity, error) {
+	if deletedScopeFromCtx(ctx) != DeletedExclude {
+		if entity, ok := r.deleted[id]; ok {
+			return entity, nil
+		}
+	}
+	if deletedScopeFromCtx(ctx) == DeletedOnly {
+		var entity testEntity
+		return entity, NewNotFoundError(RecordNotFoundCode, "%s с ИД %d не существует", entity.LocalTableName(), id)
+	}
+	return r.memoryRepository.FindByID(ctx, id)
+}
+
+func (r crudTestRepository) Restore(_ context.Context, entity testEntity) (testEntity, error) {
+	delete(r.deleted, entity.ID)
+	entity.Version++
+	r.rows[entity.ID] = entity
+	return entity, nil
+}
+
+func (r crudTestRepository) Purge(_ context.Context, entity testEntity) error {
+	delete(r.deleted, entity.ID)
+	delete(r.rows, entity.ID)
+	return nil
+}
+
+type crudTestDTO struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+func newCrudTestHandler(repo *memoryRepository, deleted ...testEntity) *CrudHandler[testEntity, crudTestDTO] {
+	crudRepo := crudTestRepository{memoryRepository: repo, deleted: make(map[uint]testEntity)}
+	for _, entity := range deleted {
+		crudRepo.deleted[entity.ID] = entity
+	}
+	service := batchTestService{NewBaseServiceImpl[testEntity](crudRepo)}
+	return NewCrudHandler[testEntity, crudTestDTO]("TESTENTITY_HANDLER", DefaultValidator(), service, MapWith(func(entity testEntity) crudTestDTO {
+		return crudTestDTO{ID: entity.ID, Name: entity.Name}
+	}))
+}
+
+func serveCrud(handle http.HandlerFunc, method, target, id string, body []byte) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, bytes.NewReader(body))
+	if id != "" {
+		r.SetPathValue("id", id)
+	}
+	w := httptest.NewRecorder()
+	handle(w, r)
+	return w
+}
+
+func decodeErrorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var response ErrorResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, w.Code, response.Status)
+	return response.Code
+}
+
+func TestCrudHandlerGetById(t *testing.T) {
+	entity := newTestEntity(1, "first")
+	entity.Version = 4
+	handler := newCrudTestHandler(newMemoryRepository(entity))
+
+	w := serveCrud(handler.GetById, http.MethodGet, "/1", "1", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"4"`, w.Header().Get(HeaderETag))
+	var dto crudTestDTO
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&dto))
+	assert.Equal(t, crudTestDTO{ID: 1, Name: "first"}, dto)
+}
+
+func TestCrudHandlerGetByIdErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		id     string
+		status int
+		code   string
+	}{
+		{name: "missing", id: "", status: http.StatusBadRequest, code: PathParamMissingCode},
+		{name: "not a number", id: "one", status: http.StatusBadRequest, code: PathParamInvalidCode},
+		{name: "not found", id: "2", status: http.StatusNotFound, code: RecordNotFoundCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newCrudTestHandler(newMemoryRepository(newTestEntity(1, "first")))
+
+			w := serveCrud(handler.GetById, http.MethodGet, "/", tt.id, nil)
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.code, decodeErrorCode(t, w))
+		})
+	}
+}
+
+func TestCrudHandlerGetAll(t *testing.T) {
+	repo := newMemoryRepository(newTestEntity(1, "first"), newTestEntity(2, "second"), newTestEntity(3, "third"))
+	handler := newCrudTestHandler(repo)
+
+	w := serveCrud(handler.GetAll, http.MethodGet, "/?limit=2", "", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var page Page[crudTestDTO]
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&page))
+	// репозиторий в памяти не ограничивает выборку, лишние записи отбрасывает страница
+	assert.Equal(t, []crudTestDTO{{ID: 1, Name: "first"}, {ID: 2, Name: "second"}}, page.Items)
+	assert.Equal(t, int64(3), page.Total)
+	assert.Equal(t, 2, page.Limit)
+	assert.True(t, page.HasMore)
+
+	w = serveCrud(handler.GetAll, http.MethodGet, "/?limit=0", "", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, QueryLimitInvalidCode, decodeErrorCode(t, w))
+	assert.Equal(t, 1, repo.searchCalls)
+}
+
+func TestCrudHandlerGetWithSearchCriteria(t *testing.T) {
+	repo := newMemoryRepository(newTestEntity(1, "first"))
+	handler := newCrudTestHandler(repo)
+
+	w := serveCrud(handler.GetWithSearchCriteria, http.MethodPost, "/search", "", []byte(`{"limit":10}`))
+	require.Equal(t, http.StatusOK, w.Code)
+	var page Page[crudTestDTO]
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&page))
+	assert.Equal(t, []crudTestDTO{{ID: 1, Name: "first"}}, page.Items)
+
+	w = serveCrud(handler.GetWithSearchCriteria, http.MethodPost, "/search", "", []byte(`{"limit":`))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, BadRequestCode, decodeErrorCode(t, w))
+
+	w = serveCrud(handler.GetWithSearchCriteria, http.MethodPost, "/search", "", []byte(`{"limit":10,"after":"a","before":"b"}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var response ValidationErrorResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, ValidationErrorCode, response.Code)
+	assert.Contains(t, response.Details, "After")
+	assert.Equal(t, 1, repo.searchCalls)
+}
+
+func TestCrudHandlerDelete(t *testing.T) {
+	repo := newMemoryRepository(newTestEntity(1, "first"))
+	handler := newCrudTestHandler(repo)
+
+	w := serveCrud(handler.Delete, http.MethodDelete, "/1", "1", nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, repo.rows)
+
+	w = serveCrud(handler.Delete, http.MethodDelete, "/1", "1", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, RecordNotFoundCode, decodeErrorCode(t, w))
+}
+
+func TestCrudHandlerRestore(t *testing.T) {
+	repo := newMemoryRepository(newTestEntity(2, "active"))
+	handler := newCrudTestHandler(repo, newTestEntity(1, "deleted"))
+
+	w := serveCrud(handler.Restore, http.MethodPost, "/1/restore", "1", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"2"`, w.Header().Get(HeaderETag))
+	var dto crudTestDTO
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&dto))
+	assert.Equal(t, crudTestDTO{ID: 1, Name: "deleted"}, dto)
+	assert.Contains(t, repo.rows, uint(1))
+
+	// восстанавливаются только удаленные записи
+	w = serveCrud(handler.Restore, http.MethodPost, "/2/restore", "2", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, RecordNotFoundCode, decodeErrorCode(t, w))
+}
+
+func TestCrudHandlerPurge(t *testing.T) {
+	repo := newMemoryRepository(newTestEntity(2, "active"))
+	handler := newCrudTestHandler(repo, newTestEntity(1, "deleted"))
+
+	w := serveCrud(handler.Purge, http.MethodDelete, "/1/purge", "1", nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = serveCrud(handler.Purge, http.MethodDelete, "/2/purge", "2", nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, repo.rows)
+
+	w = serveCrud(handler.Purge, http.MethodDelete, "/1/purge", "1", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCrudHandlerUsesListMapper(t *testing.T) {
+	repo := newMemoryRepository(newTestEntity(1, "first"), newTestEntity(2, "second"))
+	calls := 0
+	handler := newCrudTestHandler(repo).WithListMapper(func(_ context.Context, entities []testEntity) ([]crudTestDTO, error) {
+		calls++
+		dtos := make([]crudTestDTO, 0, len(entities))
+		for _, entity := range entities {
+			dtos = append(dtos, crudTestDTO{ID: entity.ID})
+		}
+		return dtos, nil
+	})
+
+	w := serveCrud(handler.GetAll, http.MethodGet, "/", "", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, calls)
+}
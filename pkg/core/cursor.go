@@ -0,0 +1,227 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	idColumn = "ID"
+)
+
+var cursorSchemaCache sync.Map
+
+// cursor позиция записи в упорядоченной выборке: значения всех ключей сортировки, последний - ID
+type cursor struct {
+	Columns []string `json:"c"`
+	Values  []any    `json:"v"`
+}
+
+func encodeCursor(c cursor) (string, error) {
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeCursor(token string) (cursor, error) {
+	var c cursor
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, NewValidationError(err, CursorInvalidCode, "Некорректный курсор пагинации")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&c); err != nil {
+		return c, NewValidationError(err, CursorInvalidCode, "Некорректный курсор пагинации")
+	}
+	if len(c.Columns) == 0 || len(c.Columns) != len(c.Values) {
+		return c, NewValidationError(nil, CursorInvalidCode, "Некорректный курсор пагинации")
+	}
+	return c, nil
+}
+
+// cursorValues проверяет, что курсор построен для тех же ключей сортировки, и приводит значения к типам полей
+func cursorValues(c cursor, keys []sortKey) ([]any, error) {
+	if len(c.Columns) != len(keys) {
+		return nil, NewValidationError(nil, CursorSortMismatchCode, "Курсор не соответствует сортировке запроса")
+	}
+	values := make([]any, len(keys))
+	for i, key := range keys {
+		if c.Columns[i] != key.field.Column {
+			return nil, NewValidationError(nil, CursorSortMismatchCode, "Курсор не соответствует сортировке запроса")
+		}
+		if c.Values[i] == nil {
+			continue
+		}
+		value, err := key.field.Coerce(c.Values[i])
+		if err != nil {
+			return nil, NewValidationError(err, CursorInvalidCode, "Некорректный курсор пагинации")
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// cursorFor строит курсор, указывающий на переданную сущность
+func cursorFor[T BaseEntity](entity T, keys []sortKey) (*string, error) {
+	c := cursor{
+		Columns: make([]string, 0, len(keys)),
+		Values:  make([]any, 0, len(keys)),
+	}
+
+	var entitySchema *schema.Schema
+	for _, key := range keys {
+		c.Columns = append(c.Columns, key.field.Column)
+		if key.field.Column == idColumn {
+			c.Values = append(c.Values, entity.GetID())
+			continue
+		}
+
+		if entitySchema == nil {
+			var err error
+			if entitySchema, err = schema.Parse(&entity, &cursorSchemaCache, schema.NamingStrategy{}); err != nil {
+				return nil, err
+			}
+		}
+		field := entitySchema.LookUpField(key.field.Column)
+		if field == nil {
+			return nil, NewValidationError(nil, SearchSortFieldUnsupportedCode, "Сортировка по полю '%s' недоступна", key.field.Name)
+		}
+		value, _ := field.ValueOf(context.Background(), reflect.ValueOf(&entity))
+		if valuer, ok := value.(driver.Valuer); ok {
+			var err error
+			if value, err = valuer.Value(); err != nil {
+				return nil, err
+			}
+		}
+		c.Values = append(c.Values, value)
+	}
+
+	token, err := encodeCursor(c)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// keysetExpr условие "после курсора" для упорядочивания keys:
+// (k1 после v1) OR (k1 = v1 AND k2 после v2) OR ...
+func keysetExpr(keys []sortKey, values []any) clause.Expression {
+	branches := make([]clause.Expression, 0, len(keys))
+	for i, key := range keys {
+		after := afterExpr(key, values[i])
+		if after == nil {
+			continue
+		}
+		exprs := make([]clause.Expression, 0, i+1)
+		for j := 0; j < i; j++ {
+			exprs = append(exprs, equalExpr(keys[j], values[j]))
+		}
+		branches = append(branches, clause.And(append(exprs, after)...))
+	}
+	if len(branches) == 0 {
+		return clause.Expr{SQL: "1 = 0"}
+	}
+	return clause.Or(branches...)
+}
+
+// afterExpr условие "строго после значения" по одному ключу с учетом положения NULL
+func afterExpr(key sortKey, value any) clause.Expression {
+	column := key.field.Column
+	if value == nil {
+		if key.nullsFirst {
+			return clause.Expr{SQL: column + " IS NOT NULL"}
+		}
+		return nil
+	}
+
+	comparison := " > ?"
+	if key.desc {
+		comparison = " < ?"
+	}
+	after := clause.Expr{SQL: column + comparison, Vars: []any{value}}
+	if !key.field.Nullable || key.nullsFirst {
+		return after
+	}
+	return clause.Or(after, clause.Expr{SQL: column + " IS NULL"})
+}
+
+func equalExpr(key sortKey, value any) clause.Expression {
+	if value == nil {
+		return clause.Expr{SQL: key.field.Column + " IS NULL"}
+	}
+	return clause.Expr{SQL: key.field.Column + " = ?", Vars: []any{value}}
+}
+
+// NewPage собирает страницу из выборки, полученной с лимитом Limit+1:
+// лишняя запись сигнализирует о наличии следующей (или предыдущей) страницы
+func NewPage[T BaseEntity](entities []T, total int64, criteria SearchCriteria) (Page[T], error) {
+	hasMore := len(entities) > criteria.Limit
+	if hasMore {
+		if criteria.Before != nil {
+			entities = entities[len(entities)-criteria.Limit:]
+		} else {
+			entities = entities[:criteria.Limit]
+		}
+	}
+
+	var hasNext, hasPrev bool
+	offset := 0
+	switch {
+	case criteria.Before != nil:
+		hasNext = true
+		hasPrev = hasMore
+	case criteria.After != nil:
+		hasNext = hasMore
+		hasPrev = true
+	default:
+		if criteria.Offset != nil {
+			offset = *criteria.Offset
+		}
+		hasNext = hasMore
+		hasPrev = offset > 0
+	}
+
+	page := Page[T]{
+		Items:   entities,
+		Total:   total,
+		Limit:   criteria.Limit,
+		Offset:  offset,
+		HasMore: hasNext && len(entities) > 0,
+	}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+
+	if len(entities) == 0 {
+		return page, nil
+	}
+	var zero T
+	keys, err := parseSortKeys(zero.SearchFields(), criteria)
+	if err != nil {
+		return page, err
+	}
+
+	if hasNext {
+		if page.NextCursor, err = cursorFor(entities[len(entities)-1], keys); err != nil {
+			return page, err
+		}
+	}
+	if hasPrev {
+		if page.PrevCursor, err = cursorFor(entities[0], keys); err != nil {
+			return page, err
+		}
+	}
+	return page, nil
+}
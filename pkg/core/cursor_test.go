@@ -0,0 +1,356 @@
+package core
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type searchTestEntity struct {
+	Base
+
+	Label    string          `gorm:"column:LABEL"`
+	Price    decimal.Decimal `gorm:"column:PRICE"`
+	Note     *string         `gorm:"column:NOTE"`
+	StatusID uint            `gorm:"column:STATUSID"`
+}
+
+func (searchTestEntity) TableName() string {
+	return "SEARCHTEST"
+}
+
+func (searchTestEntity) LocalTableName() string {
+	return "Тестовая запись"
+}
+
+func (searchTestEntity) SearchFields() *SearchFields {
+	return NewSearchFields(
+		SearchField{Name: "label", Column: "LABEL", Type: FieldString, Sortable: true},
+		SearchField{Name: "price", Column: "PRICE", Type: FieldDecimal, Sortable: true},
+		SearchField{Name: "note", Column: "NOTE", Type: FieldString, Sortable: true, Nullable: true},
+		SearchField{Name: "status", Column: "STATUSID", Type: FieldEnum, EnumCode: "STATUS"},
+		SearchField{Name: "active", Column: "ACTIVE", Type: FieldBool},
+	)
+}
+
+// newDryRunDB соединение MySQL, которое только строит SQL и не обращается к серверу
+func newDryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       "test:test@tcp(127.0.0.1:3306)/test",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+	return db
+}
+
+// searchSQL строит SELECT по критериям и возвращает текст запроса и его параметры
+func searchSQL(t *testing.T, criteria SearchCriteria) (string, []any, error) {
+	t.Helper()
+	query, err := BuildQuery(newDryRunDB(t).Model(&searchTestEntity{}), searchTestEntity{}.SearchFields(), criteria)
+	if err != nil {
+		return "", nil, err
+	}
+	var entities []searchTestEntity
+	stmt := query.Find(&entities).Statement
+	return stmt.SQL.String(), stmt.Vars, nil
+}
+
+func assertValidationCode(t *testing.T, err error, code string) {
+	t.Helper()
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, code, validationErr.Code)
+}
+
+func testSortKeys(t *testing.T, criteria SearchCriteria) []sortKey {
+	t.Helper()
+	keys, err := parseSortKeys(searchTestEntity{}.SearchFields(), criteria)
+	require.NoError(t, err)
+	return keys
+}
+
+func TestCursorEncodeDecodeRoundTrip(t *testing.T) {
+	token, err := encodeCursor(cursor{Columns: []string{"PRICE", idColumn}, Values: []any{"10.50", 7}})
+	require.NoError(t, err)
+
+	decoded, err := decodeCursor(token)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"PRICE", idColumn}, decoded.Columns)
+	// числа декодируются без потери точности
+	assert.Equal(t, []any{"10.50", json.Number("7")}, decoded.Values)
+
+	keys := testSortKeys(t, SearchCriteria{Sort: []SortOrder{{Field: "price"}}})
+	values, err := cursorValues(decoded, keys)
+	require.NoError(t, err)
+	assert.True(t, decimal.RequireFromString("10.5").Equal(values[0].(decimal.Decimal)))
+	assert.Equal(t, uint64(7), values[1])
+}
+
+func TestDecodeCursorRejectsInvalidTokens(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+	tests := map[string]string{
+		"not base64":       "!!!",
+		"not json":         encode("cursor"),
+		"no columns":       encode(`{"c":[],"v":[]}`),
+		"values mismatch":  encode(`{"c":["ID","PRICE"],"v":[1]}`),
+		"wrong field type": encode(`{"c":"ID","v":1}`),
+	}
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := decodeCursor(token)
+			assertValidationCode(t, err, CursorInvalidCode)
+		})
+	}
+}
+
+func TestCursorValuesChecksSort(t *testing.T) {
+	keys := testSortKeys(t, SearchCriteria{Sort: []SortOrder{{Field: "price"}}})
+
+	tests := []struct {
+		name   string
+		cursor cursor
+		code   string
+	}{
+		{
+			name:   "other sort column",
+			cursor: cursor{Columns: []string{"LABEL", idColumn}, Values: []any{"a", json.Number("1")}},
+			code:   CursorSortMismatchCode,
+		},
+		{
+			name:   "other number of keys",
+			cursor: cursor{Columns: []string{idColumn}, Values: []any{json.Number("1")}},
+			code:   CursorSortMismatchCode,
+		},
+		{
+			name:   "value of wrong type",
+			cursor: cursor{Columns: []string{"PRICE", idColumn}, Values: []any{"cheap", json.Number("1")}},
+			code:   CursorInvalidCode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := cursorValues(tt.cursor, keys)
+			assertValidationCode(t, err, tt.code)
+		})
+	}
+}
+
+func TestCursorValuesKeepsNull(t *testing.T) {
+	keys := testSortKeys(t, SearchCriteria{Sort: []SortOrder{{Field: "note"}}})
+	values, err := cursorValues(cursor{Columns: []string{"NOTE", idColumn}, Values: []any{nil, json.Number("3")}}, keys)
+	require.NoError(t, err)
+	assert.Equal(t, []any{nil, uint64(3)}, values)
+}
+
+func TestCursorForEntity(t *testing.T) {
+	note := "note"
+	entity := searchTestEntity{Base: Base{ID: 4}, Price: decimal.RequireFromString("12.30"), Note: &note}
+
+	tests := []struct {
+		name   string
+		sort   []SortOrder
+		values []any
+	}{
+		{name: "id only", values: []any{json.Number("4")}},
+		{name: "valuer field", sort: []SortOrder{{Field: "price"}}, values: []any{"12.3", json.Number("4")}},
+		{name: "pointer field", sort: []SortOrder{{Field: "note"}}, values: []any{"note", json.Number("4")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := cursorFor(entity, testSortKeys(t, SearchCriteria{Sort: tt.sort}))
+			require.NoError(t, err)
+			decoded, err := decodeCursor(*token)
+			require.NoError(t, err)
+			assert.Equal(t, tt.values, decoded.Values)
+		})
+	}
+
+	t.Run("null field", func(t *testing.T) {
+		token, err := cursorFor(searchTestEntity{Base: Base{ID: 5}}, testSortKeys(t, SearchCriteria{Sort: []SortOrder{{Field: "note"}}}))
+		require.NoError(t, err)
+		decoded, err := decodeCursor(*token)
+		require.NoError(t, err)
+		assert.Equal(t, []any{nil, json.Number("5")}, decoded.Values)
+	})
+}
+
+func TestKeysetExpr(t *testing.T) {
+	tests := []struct {
+		name   string
+		sort   SortOrder
+		values []any
+		sql    string
+		vars   []any
+	}{
+		{
+			name:   "asc",
+			sort:   SortOrder{Field: "price"},
+			values: []any{10, 2},
+			sql:    "(PRICE > ? OR (PRICE = ? AND ID > ?))",
+			vars:   []any{10, 10, 2},
+		},
+		{
+			name:   "desc",
+			sort:   SortOrder{Field: "price", Direction: SortDesc},
+			values: []any{10, 2},
+			sql:    "(PRICE < ? OR (PRICE = ? AND ID > ?))",
+			vars:   []any{10, 10, 2},
+		},
+		{
+			name:   "nulls first after value",
+			sort:   SortOrder{Field: "note", Nulls: NullsFirst},
+			values: []any{"a", 2},
+			sql:    "(NOTE > ? OR (NOTE = ? AND ID > ?))",
+			vars:   []any{"a", "a", 2},
+		},
+		{
+			name:   "nulls first after null",
+			sort:   SortOrder{Field: "note", Nulls: NullsFirst},
+			values: []any{nil, 2},
+			sql:    "(NOTE IS NOT NULL OR (NOTE IS NULL AND ID > ?))",
+			vars:   []any{2},
+		},
+		{
+			name:   "nulls last after value",
+			sort:   SortOrder{Field: "note", Nulls: NullsLast},
+			values: []any{"a", 2},
+			sql:    "((NOTE > ? OR NOTE IS NULL) OR (NOTE = ? AND ID > ?))",
+			vars:   []any{"a", "a", 2},
+		},
+		{
+			name:   "nulls last after null",
+			sort:   SortOrder{Field: "note", Nulls: NullsLast},
+			values: []any{nil, 2},
+			sql:    "(NOTE IS NULL AND ID > ?)",
+			vars:   []any{2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := testSortKeys(t, SearchCriteria{Sort: []SortOrder{tt.sort}})
+
+			var entities []searchTestEntity
+			stmt := newDryRunDB(t).Where(keysetExpr(keys, tt.values)).Find(&entities).Statement
+			assert.Equal(t, "SELECT * FROM `SEARCHTEST` WHERE "+tt.sql, stmt.SQL.String())
+			assert.Equal(t, tt.vars, stmt.Vars)
+		})
+	}
+}
+
+func TestBuildQueryWithCursor(t *testing.T) {
+	token, err := encodeCursor(cursor{Columns: []string{"PRICE", idColumn}, Values: []any{"10", 5}})
+	require.NoError(t, err)
+	sort := []SortOrder{{Field: "price", Direction: SortDesc}}
+
+	t.Run("after", func(t *testing.T) {
+		sql, vars, err := searchSQL(t, SearchCriteria{Limit: 3, Sort: sort, After: &token})
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM `SEARCHTEST` WHERE (PRICE < ? OR (PRICE = ? AND ID > ?)) ORDER BY PRICE DESC,ID ASC LIMIT ?", sql)
+		assert.Len(t, vars, 4)
+		assert.Equal(t, 3, vars[3])
+	})
+
+	t.Run("before reverses order", func(t *testing.T) {
+		sql, _, err := searchSQL(t, SearchCriteria{Limit: 3, Sort: sort, Before: &token})
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM `SEARCHTEST` WHERE (PRICE > ? OR (PRICE = ? AND ID < ?)) ORDER BY PRICE ASC,ID DESC LIMIT ?", sql)
+	})
+
+	t.Run("cursor for other sort", func(t *testing.T) {
+		_, _, err := searchSQL(t, SearchCriteria{Limit: 3, After: &token})
+		assertValidationCode(t, err, CursorSortMismatchCode)
+	})
+}
+
+func TestNewPage(t *testing.T) {
+	entities := func(ids ...uint) []searchTestEntity {
+		result := make([]searchTestEntity, 0, len(ids))
+		for _, id := range ids {
+			result = append(result, searchTestEntity{Base: Base{ID: id}})
+		}
+		return result
+	}
+	pageIDs := func(page Page[searchTestEntity]) []uint {
+		ids := make([]uint, 0, len(page.Items))
+		for _, item := range page.Items {
+			ids = append(ids, item.ID)
+		}
+		return ids
+	}
+	cursorID := func(t *testing.T, token *string) uint {
+		t.Helper()
+		require.NotNil(t, token)
+		decoded, err := decodeCursor(*token)
+		require.NoError(t, err)
+		id, err := coerceUint(decoded.Values[0])
+		require.NoError(t, err)
+		return uint(id)
+	}
+	token := "cursor"
+	offset := 4
+
+	t.Run("first page with more", func(t *testing.T) {
+		page, err := NewPage(entities(1, 2, 3), 10, SearchCriteria{Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, []uint{1, 2}, pageIDs(page))
+		assert.True(t, page.HasMore)
+		assert.Equal(t, uint(2), cursorID(t, page.NextCursor))
+		assert.Nil(t, page.PrevCursor)
+		assert.Equal(t, int64(10), page.Total)
+	})
+
+	t.Run("last page by offset", func(t *testing.T) {
+		page, err := NewPage(entities(5, 6), 6, SearchCriteria{Limit: 2, Offset: &offset})
+		require.NoError(t, err)
+		assert.Equal(t, []uint{5, 6}, pageIDs(page))
+		assert.False(t, page.HasMore)
+		assert.Nil(t, page.NextCursor)
+		assert.Equal(t, uint(5), cursorID(t, page.PrevCursor))
+		assert.Equal(t, 4, page.Offset)
+	})
+
+	t.Run("after cursor", func(t *testing.T) {
+		page, err := NewPage(entities(3, 4, 5), 10, SearchCriteria{Limit: 2, After: &token})
+		require.NoError(t, err)
+		assert.Equal(t, []uint{3, 4}, pageIDs(page))
+		assert.True(t, page.HasMore)
+		assert.Equal(t, uint(4), cursorID(t, page.NextCursor))
+		assert.Equal(t, uint(3), cursorID(t, page.PrevCursor))
+	})
+
+	t.Run("before cursor keeps records nearest to it", func(t *testing.T) {
+		// репозиторий уже развернул выборку, лишняя запись - первая
+		page, err := NewPage(entities(1, 2, 3), 10, SearchCriteria{Limit: 2, Before: &token})
+		require.NoError(t, err)
+		assert.Equal(t, []uint{2, 3}, pageIDs(page))
+		assert.True(t, page.HasMore)
+		assert.Equal(t, uint(3), cursorID(t, page.NextCursor))
+		assert.Equal(t, uint(2), cursorID(t, page.PrevCursor))
+	})
+
+	t.Run("before cursor at start", func(t *testing.T) {
+		page, err := NewPage(entities(1, 2), 10, SearchCriteria{Limit: 2, Before: &token})
+		require.NoError(t, err)
+		assert.Equal(t, []uint{1, 2}, pageIDs(page))
+		assert.Nil(t, page.PrevCursor)
+		assert.NotNil(t, page.NextCursor)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		page, err := NewPage[searchTestEntity](nil, 0, SearchCriteria{Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, []searchTestEntity{}, page.Items)
+		assert.False(t, page.HasMore)
+		assert.Nil(t, page.NextCursor)
+		assert.Nil(t, page.PrevCursor)
+	})
+}
@@ -12,7 +12,17 @@ const (
 	OpLess      Operator = "<"
 	OpLessEq    Operator = "<="
 	OpIn        Operator = "in"
+	OpNotIn     Operator = "not_in"
 	OpLike      Operator = "like"
+	OpBetween   Operator = "between"
+	OpIsNull    Operator = "is_null"
+	OpNotNull   Operator = "not_null"
+	// OpStartsWith, OpEndsWith, OpContains и OpIContains принимают строку без шаблона,
+	// спецсимволы like экранируются на сервере
+	OpStartsWith Operator = "starts_with"
+	OpEndsWith   Operator = "ends_with"
+	OpContains   Operator = "contains"
+	OpIContains  Operator = "icontains"
 )
 
 // SearchCriteria represents search criteria with pagination and filtering
@@ -20,8 +30,12 @@ const (
 type SearchCriteria struct {
 	Limit            int               `json:"limit" validate:"required,gte=0"`
 	Offset           *int              `json:"offset" validate:"omitempty,gte=0"`
-	OrderBy          *string           `json:"order_by" validate:"omitempty,min=1,max=50"`
+	OrderBy          *string           `json:"order_by" validate:"omitempty,min=1,max=50"` // устаревший формат "<поле> [asc|desc]", используйте Sort
+	Sort             []SortOrder       `json:"sort" validate:"omitempty,excluded_with=OrderBy,max=5,dive"`
+	After            *string           `json:"after" validate:"omitempty,min=1,excluded_with=Before"`
+	Before           *string           `json:"before" validate:"omitempty,min=1,excluded_with=After"`
 	SearchConditions []SearchCondition `json:"search_conditions" validate:"omitempty,dive"`
+	Filter           *ConditionGroup   `json:"filter" validate:"omitempty"`
 }
 
 // SearchCondition represents a single search condition
@@ -29,5 +43,58 @@ type SearchCriteria struct {
 type SearchCondition struct {
 	Field     string   `json:"field" validate:"required"`
 	Operation Operator `json:"operation" validate:"required"`
-	Value     any      `json:"value" validate:"required"`
+	Value     any      `json:"value"`
+}
+
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+
+	NullsFirst = "first"
+	NullsLast  = "last"
+)
+
+// SortOrder represents a single sort field with direction and NULL placement
+// @Name SortOrder
+type SortOrder struct {
+	Field     string `json:"field" validate:"required"`
+	Direction string `json:"direction" validate:"omitempty,oneof=asc desc"`
+	Nulls     string `json:"nulls" validate:"omitempty,oneof=first last"`
+}
+
+// ConditionGroup represents a node of the condition tree: exactly one of and, or, not
+// or a single condition (field, operation, value)
+// @Name ConditionGroup
+type ConditionGroup struct {
+	And       []ConditionGroup `json:"and,omitempty" validate:"omitempty,dive"`
+	Or        []ConditionGroup `json:"or,omitempty" validate:"omitempty,dive"`
+	Not       *ConditionGroup  `json:"not,omitempty" validate:"omitempty"`
+	Field     string           `json:"field,omitempty"`
+	Operation Operator         `json:"operation,omitempty"`
+	Value     any              `json:"value,omitempty"`
+}
+
+// Page represents a page of search results with total count and keyset cursors
+// @Name Page
+type Page[T any] struct {
+	Items      []T     `json:"items"`
+	Total      int64   `json:"total"`
+	Limit      int     `json:"limit"`
+	Offset     int     `json:"offset"`
+	HasMore    bool    `json:"has_more"`
+	NextCursor *string `json:"next_cursor"`
+	PrevCursor *string `json:"prev_cursor"`
+}
+
+// MapPage переносит метаданные страницы на список DTO
+func MapPage[T any, D any](page Page[T], items []D) Page[D] {
+	return Page[D]{
+		Items:      items,
+		Total:      page.Total,
+		Limit:      page.Limit,
+		Offset:     page.Offset,
+		HasMore:    page.HasMore,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	}
 }
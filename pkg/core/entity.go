@@ -6,14 +6,32 @@ type BaseEntity interface {
 	TableName() string
 	LocalTableName() string
 	GetID() uint
+	GetVersion() uint
+	// SearchFields реестр полей, доступных для фильтрации и сортировки
+	SearchFields() *SearchFields
+}
+
+// Versioned сущность, версию которой можно выставить перед обновлением
+type Versioned interface {
+	SetVersion(version uint)
 }
 
 type Base struct {
 	ID        uint      `gorm:"primaryKey;column:ID"`
 	CreatedAt time.Time `gorm:"column:CREATEDAT"`
 	UpdatedAt time.Time `gorm:"column:UPDATEDAT"`
+	// Version версия записи для оптимистичной блокировки, увеличивается при каждом обновлении
+	Version uint `gorm:"column:VERSION"`
 }
 
 func (b Base) GetID() uint {
 	return b.ID
 }
+
+func (b Base) GetVersion() uint {
+	return b.Version
+}
+
+func (b *Base) SetVersion(version uint) {
+	b.Version = version
+}
@@ -2,7 +2,9 @@ package core
 
 import (
 	"fmt"
+	"net/http"
 	"runtime"
+	"strings"
 	"time"
 )
 
@@ -66,8 +68,24 @@ func WrapStack(err error, msg string) error {
 	}
 }
 
+// DecodeError тело запроса не удалось разобрать, причина в данных клиента
+type DecodeError struct {
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
 type NotFoundError struct {
+	// Code код из реестра кодов ошибок, если пусто - используется NOT_FOUND
+	Code    string
 	Message string
+	Args    []any
 }
 
 func (e *NotFoundError) Error() string {
@@ -79,14 +97,20 @@ func (e *NotFoundError) Is(target error) bool {
 	return ok
 }
 
-func NewNotFoundError(msg string) *NotFoundError {
-	return &NotFoundError{Message: msg}
+func NewNotFoundError(code, message string, args ...any) *NotFoundError {
+	return &NotFoundError{
+		Code:    code,
+		Message: formatMessage(message, args),
+		Args:    args,
+	}
 }
 
 type ErrorInfo struct {
 	Code    string
 	Message string
 	Err     error
+	// Args аргументы сообщения, подставляются и в перевод из каталога сообщений по Code
+	Args []any
 }
 
 func (e *ErrorInfo) Unwrap() error {
@@ -101,11 +125,13 @@ func (e *LogicalError) Error() string {
 	return errorString(e.Err, e.Code, e.Message)
 }
 
-func NewLogicalError(err error, code, message string) *LogicalError {
+func NewLogicalError(err error, code, message string, args ...any) *LogicalError {
+	message = formatMessage(message, args)
 	return &LogicalError{
 		ErrorInfo: ErrorInfo{
 			Code:    code,
 			Message: message,
+			Args:    args,
 			Err:     WrapStack(err, message),
 		},
 	}
@@ -119,11 +145,13 @@ func (e *TechnicalError) Error() string {
 	return errorString(e.Err, e.Code, e.Message)
 }
 
-func NewTechnicalError(err error, code, message string) *TechnicalError {
+func NewTechnicalError(err error, code, message string, args ...any) *TechnicalError {
+	message = formatMessage(message, args)
 	return &TechnicalError{
 		ErrorInfo: ErrorInfo{
 			Code:    code,
 			Message: message,
+			Args:    args,
 			Err:     WrapStack(err, message),
 		},
 	}
@@ -137,16 +165,41 @@ func (e *ValidationError) Error() string {
 	return errorString(e.Err, e.Code, e.Message)
 }
 
-func NewValidationError(err error, code, message string) *ValidationError {
+func NewValidationError(err error, code, message string, args ...any) *ValidationError {
+	message = formatMessage(message, args)
 	return &ValidationError{
 		ErrorInfo: ErrorInfo{
 			Code:    code,
 			Message: message,
+			Args:    args,
 			Err:     err,
 		},
 	}
 }
 
+// ConflictError запись изменена параллельно (версия при обновлении не совпала с прочитанной)
+// или уже существует
+type ConflictError struct {
+	ErrorInfo
+}
+
+func (e *ConflictError) Error() string {
+	return errorString(e.Err, e.Code, e.Message)
+}
+
+func NewConflictError(err error, code, message string, args ...any) *ConflictError {
+	message = formatMessage(message, args)
+	return &ConflictError{
+		ErrorInfo: ErrorInfo{
+			Code:    code,
+			Message: message,
+			Args:    args,
+			Err:     err,
+		},
+	}
+}
+
+// AccessError пользователь не аутентифицирован: токен отсутствует, недействителен или истек
 type AccessError struct {
 	ErrorInfo
 }
@@ -155,28 +208,72 @@ func (e *AccessError) Error() string {
 	return errorString(e.Err, e.Code, e.Message)
 }
 
-func NewAccessError(err error, code, message string) *AccessError {
+func NewAccessError(err error, code, message string, args ...any) *AccessError {
+	message = formatMessage(message, args)
 	return &AccessError{
 		ErrorInfo: ErrorInfo{
 			Code:    code,
 			Message: message,
+			Args:    args,
 			Err:     err,
 		},
 	}
 }
 
+// ForbiddenError пользователь аутентифицирован, но не имеет прав на операцию
+type ForbiddenError struct {
+	ErrorInfo
+}
+
+func (e *ForbiddenError) Error() string {
+	return errorString(e.Err, e.Code, e.Message)
+}
+
+func NewForbiddenError(err error, code, message string, args ...any) *ForbiddenError {
+	message = formatMessage(message, args)
+	return &ForbiddenError{
+		ErrorInfo: ErrorInfo{
+			Code:    code,
+			Message: message,
+			Args:    args,
+			Err:     err,
+		},
+	}
+}
+
+// formatMessage подставляет аргументы в сообщение. Без аргументов сообщение не форматируется,
+// поэтому в нем допустим текст ошибок с символом %. Сообщение без глаголов fmt возвращается как есть:
+// перевод может не использовать аргументы, например названия сущностей на русском
+func formatMessage(message string, args []any) string {
+	if len(args) == 0 || !strings.Contains(message, "%") {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
 func errorString(err error, code, message string) string {
+	if err == nil {
+		return fmt.Sprintf("[%s] %s", code, message)
+	}
 	msg := fmt.Sprintf("[%s] %s", code, err.Error())
 	return msg
 }
 
-// ErrorResponse represents standard error response
+// ErrorResponse represents error response in RFC 7807 problem details format
 // @Name ErrorResponse
 type ErrorResponse struct {
-	Status    int       `json:"status"`
+	// Type URI вида ошибки
+	Type string `json:"type"`
+	// Title краткое описание вида ошибки, одинаковое для всех ошибок одного вида
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	// Detail описание конкретной ошибки. Для технических ошибок подробности не раскрываются
+	Detail string `json:"detail"`
+	// Instance путь запроса, в котором возникла ошибка
+	Instance string `json:"instance"`
+	// Code стабильный код конкретной ошибки, список кодов - GET /error-codes
 	Code      string    `json:"code"`
-	Message   string    `json:"message"`
-	Path      string    `json:"path"`
+	RequestID string    `json:"request_id,omitempty"`
 	Method    string    `json:"method"`
 	Timestamp time.Time `json:"timestamp"`
 }
@@ -188,25 +285,26 @@ type ValidationErrorResponse struct {
 	Details map[string]string `json:"details"`
 }
 
-func NewErrorResponse(status int, code, message, path, method string) *ErrorResponse {
+// NewErrorResponse формирует ответ с заголовком и описанием ошибки на языке запроса
+func NewErrorResponse(problem ProblemDetail, r *http.Request) *ErrorResponse {
+	title, detail := problem.Localize(r.Context())
 	return &ErrorResponse{
-		Status:    status,
-		Code:      code,
-		Message:   message,
-		Path:      path,
-		Method:    method,
+		Type:      problem.Problem.Type,
+		Title:     title,
+		Status:    problem.Problem.Status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		Code:      problem.Code,
+		RequestID: RequestIDFromContext(r.Context()),
+		Method:    r.Method,
 		Timestamp: time.Now(),
 	}
 }
 
-func NewValidationErrorResponse(status int, message, path string, details map[string]string) *ValidationErrorResponse {
+func NewValidationErrorResponse(r *http.Request, details map[string]string) *ValidationErrorResponse {
+	problem := newProblemDetail(ProblemUnprocessable, ValidationErrorCode, "Данные запроса не прошли проверку, подробности в details", nil)
 	return &ValidationErrorResponse{
-		ErrorResponse: ErrorResponse{
-			Status:    status,
-			Message:   message,
-			Path:      path,
-			Timestamp: time.Now(),
-		},
-		Details: details,
+		ErrorResponse: *NewErrorResponse(problem, r),
+		Details:       details,
 	}
 }
@@ -0,0 +1,232 @@
+package core
+
+import (
+	"cmp"
+	"context"
+	"net/http"
+	"slices"
+)
+
+// Реестр кодов ошибок. Код - стабильный идентификатор конкретной ошибки для клиента
+// и ключ сообщения в каталоге messages/<язык>.json. Коды не переименовываются и не переиспользуются
+// для других ошибок: клиенты обрабатывают ошибки по коду, а не по тексту
+const (
+	InternalErrorCode   = "INTERNAL_ERROR"
+	BadRequestCode      = "BAD_REQUEST"
+	ValidationErrorCode = "VALIDATION_ERROR"
+	NotFoundCode        = "NOT_FOUND"
+
+	SearchFieldUnknownCode         = "SEARCH_FIELD_UNKNOWN"
+	SearchSortFieldUnsupportedCode = "SEARCH_SORT_FIELD_UNSUPPORTED"
+	SearchValueInvalidCode         = "SEARCH_VALUE_INVALID"
+	SearchValueListExpectedCode    = "SEARCH_VALUE_LIST_EXPECTED"
+
+	QueryLimitInvalidCode    = "QUERY_LIMIT_INVALID"
+	QueryOffsetInvalidCode   = "QUERY_OFFSET_INVALID"
+	QuerySortEmptyFieldCode  = "QUERY_SORT_EMPTY_FIELD"
+	QueryInvalidCode         = "QUERY_INVALID"
+	QueryFilterInvalidCode   = "QUERY_FILTER_INVALID"
+	DeletedScopeInvalidCode  = "DELETED_SCOPE_INVALID"
+	CursorInvalidCode        = "CURSOR_INVALID"
+	CursorSortMismatchCode   = "CURSOR_SORT_MISMATCH"
+	SortTooManyFieldsCode    = "SORT_TOO_MANY_FIELDS"
+	SortDuplicateFieldCode   = "SORT_DUPLICATE_FIELD"
+	SortDirectionInvalidCode = "SORT_DIRECTION_INVALID"
+	SortNullsInvalidCode     = "SORT_NULLS_INVALID"
+	SortFormatInvalidCode    = "SORT_FORMAT_INVALID"
+
+	FilterDepthExceededCode        = "FILTER_DEPTH_EXCEEDED"
+	FilterNodeInvalidCode          = "FILTER_NODE_INVALID"
+	FilterGroupEmptyCode           = "FILTER_GROUP_EMPTY"
+	FilterBetweenValuesCode        = "FILTER_BETWEEN_VALUES"
+	FilterOperationUnsupportedCode = "FILTER_OPERATION_UNSUPPORTED"
+	FilterOperationNotAllowedCode  = "FILTER_OPERATION_NOT_ALLOWED"
+
+	BatchModeInvalidCode       = "BATCH_MODE_INVALID"
+	BatchEmptyCode             = "BATCH_EMPTY"
+	BatchTooLargeCode          = "BATCH_TOO_LARGE"
+	BatchDuplicateItemCode     = "BATCH_DUPLICATE_ITEM"
+	BatchAbortedCode           = "BATCH_ABORTED"
+	BatchDeleteItemInvalidCode = "BATCH_DELETE_ITEM_INVALID"
+
+	PathParamMissingCode = "PATH_PARAM_MISSING"
+	PathParamInvalidCode = "PATH_PARAM_INVALID"
+	FormInvalidCode      = "FORM_INVALID"
+	FormFieldMissingCode = "FORM_FIELD_MISSING"
+	FormFieldInvalidCode = "FORM_FIELD_INVALID"
+	ETagInvalidCode      = "ETAG_INVALID"
+
+	RecordNotFoundCode           = "RECORD_NOT_FOUND"
+	RecordVersionConflictCode    = "RECORD_VERSION_CONFLICT"
+	RecordsVersionConflictCode   = "RECORDS_VERSION_CONFLICT"
+	RecordRestoreUnsupportedCode = "RECORD_RESTORE_UNSUPPORTED"
+
+	AuthTokenMissingCode            = "AUTH_TOKEN_MISSING"
+	AuthTokenInvalidCode            = "AUTH_TOKEN_INVALID"
+	AuthTokenClaimsInvalidCode      = "AUTH_TOKEN_CLAIMS_INVALID"
+	AuthRoleForbiddenCode           = "AUTH_ROLE_FORBIDDEN"
+	AuthCredentialsInvalidCode      = "AUTH_CREDENTIALS_INVALID"
+	AuthRefreshTokenInvalidCode     = "AUTH_REFRESH_TOKEN_INVALID"
+	AuthContextMissingCode          = "AUTH_CONTEXT_MISSING"
+	AuthServiceUnavailableCode      = "AUTH_SERVICE_UNAVAILABLE"
+	UserAlreadyExistsCode           = "USER_ALREADY_EXISTS"
+	UserNotFoundCode                = "USER_NOT_FOUND"
+	FileTooLargeCode                = "FILE_TOO_LARGE"
+	FileTypeUnsupportedCode         = "FILE_TYPE_UNSUPPORTED"
+	WebhookSubscriptionDeletedCode  = "WEBHOOK_SUBSCRIPTION_DELETED"
+	WebhookSubscriptionInactiveCode = "WEBHOOK_SUBSCRIPTION_INACTIVE"
+
+	PersonAlreadyExistsCode    = "PERSON_ALREADY_EXISTS"
+	PersonNotFoundCode         = "PERSON_NOT_FOUND"
+	CategoryAlreadyExistsCode  = "CATEGORY_ALREADY_EXISTS"
+	CategoryNotFoundCode       = "CATEGORY_NOT_FOUND"
+	EnumAlreadyExistsCode      = "ENUM_ALREADY_EXISTS"
+	EnumNotFoundCode           = "ENUM_NOT_FOUND"
+	EnumValueAlreadyExistsCode = "ENUM_VALUE_ALREADY_EXISTS"
+	EnumValueNotFoundCode      = "ENUM_VALUE_NOT_FOUND"
+
+	ProductAlreadyExistsCode = "PRODUCT_ALREADY_EXISTS"
+	ProductNotFoundCode      = "PRODUCT_NOT_FOUND"
+	ProductOutOfStockCode    = "PRODUCT_OUT_OF_STOCK"
+	ProductUnavailableCode   = "PRODUCT_UNAVAILABLE"
+	ProductStatusInvalidCode = "PRODUCT_STATUS_INVALID"
+	ProductPriceInvalidCode  = "PRODUCT_PRICE_INVALID"
+
+	CartNotFoundCode      = "CART_NOT_FOUND"
+	CartItemDuplicateCode = "CART_ITEM_DUPLICATE"
+	CartItemNotFoundCode  = "CART_ITEM_NOT_FOUND"
+
+	OrderNotFoundCode             = "ORDER_NOT_FOUND"
+	OrderStatusUnknownCode        = "ORDER_STATUS_UNKNOWN"
+	OrderAlreadyCancelledCode     = "ORDER_ALREADY_CANCELLED"
+	OrderItemNotFoundCode         = "ORDER_ITEM_NOT_FOUND"
+	OrderItemStatusUnknownCode    = "ORDER_ITEM_STATUS_UNKNOWN"
+	OrderItemAlreadyCancelledCode = "ORDER_ITEM_ALREADY_CANCELLED"
+)
+
+// ErrorCodeInfo описание кода ошибки для клиентов API
+// @Name ErrorCodeInfo
+type ErrorCodeInfo struct {
+	Code string `json:"code"`
+	// Status HTTP статус ответа с этим кодом
+	Status      int    `json:"status"`
+	Description string `json:"description"`
+}
+
+var errorCodeRegistry = []ErrorCodeInfo{
+	{InternalErrorCode, http.StatusInternalServerError, "Внутренняя ошибка сервера. Подробности не раскрываются, искать в логах по request_id"},
+	{BadRequestCode, http.StatusBadRequest, "Тело запроса не является корректным JSON"},
+	{ValidationErrorCode, http.StatusUnprocessableEntity, "Поля тела запроса не прошли проверку, ошибки по полям в details"},
+	{NotFoundCode, http.StatusNotFound, "Запись не найдена"},
+
+	{SearchFieldUnknownCode, http.StatusBadRequest, "Поле недоступно для поиска"},
+	{SearchSortFieldUnsupportedCode, http.StatusBadRequest, "Сортировка по полю недоступна"},
+	{SearchValueInvalidCode, http.StatusBadRequest, "Значение условия не соответствует типу поля"},
+	{SearchValueListExpectedCode, http.StatusBadRequest, "Операция над полем ожидает список значений"},
+
+	{QueryLimitInvalidCode, http.StatusBadRequest, "Параметр limit вне допустимого диапазона"},
+	{QueryOffsetInvalidCode, http.StatusBadRequest, "Параметр offset отрицательный или не число"},
+	{QuerySortEmptyFieldCode, http.StatusBadRequest, "Пустое поле в параметре sort"},
+	{QueryInvalidCode, http.StatusBadRequest, "Некорректная строка запроса"},
+	{QueryFilterInvalidCode, http.StatusBadRequest, "Некорректное условие фильтра в строке запроса"},
+	{DeletedScopeInvalidCode, http.StatusBadRequest, "Некорректное значение параметра deleted"},
+	{CursorInvalidCode, http.StatusBadRequest, "Некорректный курсор пагинации"},
+	{CursorSortMismatchCode, http.StatusBadRequest, "Курсор получен для другой сортировки"},
+	{SortTooManyFieldsCode, http.StatusBadRequest, "Превышено количество полей сортировки"},
+	{SortDuplicateFieldCode, http.StatusBadRequest, "Поле указано в сортировке несколько раз"},
+	{SortDirectionInvalidCode, http.StatusBadRequest, "Некорректное направление сортировки"},
+	{SortNullsInvalidCode, http.StatusBadRequest, "Некорректное положение NULL в сортировке"},
+	{SortFormatInvalidCode, http.StatusBadRequest, "Некорректный формат сортировки"},
+
+	{FilterDepthExceededCode, http.StatusBadRequest, "Превышена вложенность условий фильтра"},
+	{FilterNodeInvalidCode, http.StatusBadRequest, "Узел условий фильтра заполнен некорректно"},
+	{FilterGroupEmptyCode, http.StatusBadRequest, "Группа условий фильтра пуста"},
+	{FilterBetweenValuesCode, http.StatusBadRequest, "Операция between ожидает два значения"},
+	{FilterOperationUnsupportedCode, http.StatusBadRequest, "Операция фильтра не поддерживается"},
+	{FilterOperationNotAllowedCode, http.StatusBadRequest, "Операция фильтра недоступна для типа поля"},
+
+	{BatchModeInvalidCode, http.StatusBadRequest, "Некорректный режим пакета"},
+	{BatchEmptyCode, http.StatusBadRequest, "Пакет не содержит элементов"},
+	{BatchTooLargeCode, http.StatusBadRequest, "Превышен размер пакета"},
+	{BatchDuplicateItemCode, http.StatusBadRequest, "Запись встречается в пакете несколько раз"},
+	{BatchAbortedCode, http.StatusBadRequest, "Элемент не применен, потому что пакет отменен из-за ошибок в других элементах"},
+	{BatchDeleteItemInvalidCode, http.StatusBadRequest, "Элемент пакета на удаление не содержит ИД или версию записи"},
+
+	{PathParamMissingCode, http.StatusBadRequest, "Отсутствует параметр пути"},
+	{PathParamInvalidCode, http.StatusBadRequest, "Параметр пути должен быть числовым"},
+	{FormInvalidCode, http.StatusBadRequest, "Некорректная multipart форма"},
+	{FormFieldMissingCode, http.StatusBadRequest, "Отсутствует поле формы"},
+	{FormFieldInvalidCode, http.StatusBadRequest, "Некорректное значение поля формы"},
+	{ETagInvalidCode, http.StatusBadRequest, "Некорректный заголовок If-Match"},
+
+	{RecordNotFoundCode, http.StatusNotFound, "Запись с указанным ИД не существует"},
+	{RecordVersionConflictCode, http.StatusConflict, "Запись изменена или удалена другим пользователем после чтения"},
+	{RecordsVersionConflictCode, http.StatusConflict, "Часть записей изменена или удалена другим пользователем после чтения"},
+	{RecordRestoreUnsupportedCode, http.StatusBadRequest, "Записи не поддерживают восстановление"},
+
+	{AuthTokenMissingCode, http.StatusUnauthorized, "Не указан токен авторизации"},
+	{AuthTokenInvalidCode, http.StatusUnauthorized, "Токен авторизации недействителен или истек"},
+	{AuthTokenClaimsInvalidCode, http.StatusBadRequest, "В токене авторизации отсутствуют или некорректны обязательные данные"},
+	{AuthRoleForbiddenCode, http.StatusForbidden, "Роль пользователя не дает доступа к операции"},
+	{AuthCredentialsInvalidCode, http.StatusUnauthorized, "Неверный логин или пароль"},
+	{AuthRefreshTokenInvalidCode, http.StatusUnauthorized, "Токен обновления недействителен или истек"},
+	{AuthContextMissingCode, http.StatusBadRequest, "Операция вызвана без данных авторизации"},
+	{AuthServiceUnavailableCode, http.StatusServiceUnavailable, "Сервис авторизации временно недоступен"},
+	{UserAlreadyExistsCode, http.StatusConflict, "Пользователь с таким email уже зарегистрирован"},
+	{UserNotFoundCode, http.StatusNotFound, "Пользователь не найден"},
+	{FileTooLargeCode, http.StatusBadRequest, "Превышен максимальный размер файла"},
+	{FileTypeUnsupportedCode, http.StatusBadRequest, "Неподдерживаемый тип файла"},
+	{WebhookSubscriptionDeletedCode, http.StatusBadRequest, "Подписка доставки удалена"},
+	{WebhookSubscriptionInactiveCode, http.StatusBadRequest, "Подписка доставки отключена"},
+
+	{PersonAlreadyExistsCode, http.StatusConflict, "Клиент с таким логином уже существует"},
+	{PersonNotFoundCode, http.StatusNotFound, "Клиент не найден"},
+	{CategoryAlreadyExistsCode, http.StatusConflict, "Категория с таким кодом уже существует"},
+	{CategoryNotFoundCode, http.StatusNotFound, "Категория не найдена"},
+	{EnumAlreadyExistsCode, http.StatusConflict, "Перечисление с таким кодом уже существует"},
+	{EnumNotFoundCode, http.StatusNotFound, "Перечисление не найдено"},
+	{EnumValueAlreadyExistsCode, http.StatusConflict, "Значение с таким кодом уже существует в перечислении"},
+	{EnumValueNotFoundCode, http.StatusNotFound, "Значение перечисления не найдено"},
+
+	{ProductAlreadyExistsCode, http.StatusConflict, "Продукт с таким кодом или артикулом уже существует"},
+	{ProductNotFoundCode, http.StatusNotFound, "Продукт не найден"},
+	{ProductOutOfStockCode, http.StatusBadRequest, "Недостаточно товара на складе"},
+	{ProductUnavailableCode, http.StatusBadRequest, "Товар недоступен для заказа"},
+	{ProductStatusInvalidCode, http.StatusBadRequest, "Статус не относится к статусам продукта"},
+	{ProductPriceInvalidCode, http.StatusBadRequest, "Некорректная цена продукта"},
+
+	{CartNotFoundCode, http.StatusNotFound, "Корзина не найдена"},
+	{CartItemDuplicateCode, http.StatusConflict, "Товар уже есть в корзине"},
+	{CartItemNotFoundCode, http.StatusNotFound, "Элемент корзины не найден"},
+
+	{OrderNotFoundCode, http.StatusNotFound, "Заказ не найден"},
+	{OrderStatusUnknownCode, http.StatusBadRequest, "Неизвестный статус заказа"},
+	{OrderAlreadyCancelledCode, http.StatusBadRequest, "Заказ уже отменен"},
+	{OrderItemNotFoundCode, http.StatusNotFound, "Элемент заказа не найден"},
+	{OrderItemStatusUnknownCode, http.StatusBadRequest, "Неизвестный статус элемента заказа"},
+	{OrderItemAlreadyCancelledCode, http.StatusBadRequest, "Элемент заказа уже отменен"},
+}
+
+// errorCodeStatuses HTTP статус ответа по коду ошибки из реестра
+var errorCodeStatuses = func() map[string]int {
+	statuses := make(map[string]int, len(errorCodeRegistry))
+	for _, info := range errorCodeRegistry {
+		statuses[info.Code] = info.Status
+	}
+	return statuses
+}()
+
+// ErrorCodes возвращает реестр кодов ошибок, отсортированный по коду.
+// Описание возвращается на языке запроса, если в каталоге есть перевод description.<код>
+func ErrorCodes(ctx context.Context) []ErrorCodeInfo {
+	codes := slices.Clone(errorCodeRegistry)
+	for i := range codes {
+		if description, ok := Localize(ctx, "description."+codes[i].Code); ok {
+			codes[i].Description = description
+		}
+	}
+	slices.SortFunc(codes, func(a, b ErrorCodeInfo) int {
+		return cmp.Compare(a.Code, b.Code)
+	})
+	return codes
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorCodeRegistryCodesUnique(t *testing.T) {
+	seen := make(map[string]bool, len(errorCodeRegistry))
+	for _, info := range errorCodeRegistry {
+		assert.False(t, seen[info.Code], "code %s registered twice", info.Code)
+		seen[info.Code] = true
+	}
+}
+
+func TestErrorCodeRegistryCatalogs(t *testing.T) {
+	ru := messageCatalog.bundles[LangRU]
+	en := messageCatalog.bundles[LangEN]
+	require.NotEmpty(t, ru)
+	require.NotEmpty(t, en)
+
+	for _, info := range errorCodeRegistry {
+		t.Run(info.Code, func(t *testing.T) {
+			assert.NotEmpty(t, info.Description)
+			assert.Contains(t, ru, info.Code)
+			assert.Contains(t, en, info.Code)
+			// описание на русском хранится в реестре, в каталоге - только переводы
+			assert.Contains(t, en, "description."+info.Code)
+		})
+	}
+}
+
+func TestErrorCodesLocalized(t *testing.T) {
+	codes := ErrorCodes(context.Background())
+	require.Len(t, codes, len(errorCodeRegistry))
+	assert.True(t, slices.IsSortedFunc(codes, func(a, b ErrorCodeInfo) int {
+		return cmp.Compare(a.Code, b.Code)
+	}))
+
+	registered := make(map[string]ErrorCodeInfo, len(errorCodeRegistry))
+	for _, info := range errorCodeRegistry {
+		registered[info.Code] = info
+	}
+	for _, info := range codes {
+		assert.Equal(t, registered[info.Code], info)
+	}
+
+	for _, info := range ErrorCodes(WithLanguage(context.Background(), LangEN)) {
+		assert.Equal(t, messageCatalog.bundles[LangEN]["description."+info.Code], info.Description, info.Code)
+		assert.Equal(t, registered[info.Code].Status, info.Status, info.Code)
+	}
+}
+
+func TestResolveProblemStatusMatchesRegistry(t *testing.T) {
+	constructors := map[string]func(code string) error{
+		"logical":    func(code string) error { return NewLogicalError(nil, code, "test") },
+		"validation": func(code string) error { return NewValidationError(nil, code, "test") },
+		"conflict":   func(code string) error { return NewConflictError(nil, code, "test") },
+		"not found":  func(code string) error { return NewNotFoundError(code, "test") },
+		"access":     func(code string) error { return NewAccessError(nil, code, "test") },
+		"forbidden":  func(code string) error { return NewForbiddenError(nil, code, "test") },
+	}
+
+	for _, info := range errorCodeRegistry {
+		for name, newError := range constructors {
+			t.Run(info.Code+"/"+name, func(t *testing.T) {
+				problem := ResolveProblem(newError(info.Code))
+				assert.Equal(t, info.Code, problem.Code)
+				assert.Equal(t, info.Status, problem.Problem.Status)
+			})
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package core
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	HeaderETag    = "ETag"
+	HeaderIfMatch = "If-Match"
+)
+
+// SetETag передает версию сущности в заголовке ETag
+func SetETag(w http.ResponseWriter, entity BaseEntity) {
+	w.Header().Set(HeaderETag, strconv.Quote(strconv.FormatUint(uint64(entity.GetVersion()), 10)))
+}
+
+// ApplyIfMatch выставляет сущности версию из заголовка If-Match,
+// чтобы обновление прошло только поверх той версии, которую видел клиент.
+// Без заголовка (или с "*") версия остается прочитанной из БД
+func ApplyIfMatch(r *http.Request, entity Versioned) error {
+	header := strings.TrimSpace(r.Header.Get(HeaderIfMatch))
+	if header == "" || header == "*" {
+		return nil
+	}
+
+	tag := strings.TrimPrefix(header, "W/")
+	unquoted, err := strconv.Unquote(tag)
+	if err != nil {
+		return NewValidationError(err, ETagInvalidCode, "Некорректный заголовок If-Match")
+	}
+	version, err := strconv.ParseUint(unquoted, 10, 0)
+	if err != nil {
+		return NewValidationError(err, ETagInvalidCode, "Некорректный заголовок If-Match")
+	}
+
+	entity.SetVersion(uint(version))
+	return nil
+}
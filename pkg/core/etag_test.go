@@ -0,0 +1,73 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetETag(t *testing.T) {
+	w := httptest.NewRecorder()
+	entity := newTestEntity(1, "first")
+	entity.Version = 42
+
+	SetETag(w, entity)
+	assert.Equal(t, `"42"`, w.Header().Get(HeaderETag))
+}
+
+func TestApplyIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		version uint
+	}{
+		{name: "no header", header: "", version: 3},
+		{name: "any version", header: "*", version: 3},
+		{name: "strong tag", header: `"7"`, version: 7},
+		{name: "weak tag", header: `W/"8"`, version: 8},
+		{name: "spaces", header: ` "9" `, version: 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/", nil)
+			if tt.header != "" {
+				r.Header.Set(HeaderIfMatch, tt.header)
+			}
+			// версия, прочитанная из БД
+			entity := newTestEntity(1, "first")
+			entity.Version = 3
+
+			require.NoError(t, ApplyIfMatch(r, &entity))
+			assert.Equal(t, tt.version, entity.Version)
+		})
+	}
+}
+
+func TestApplyIfMatchRejectsInvalidHeader(t *testing.T) {
+	for _, header := range []string{`7`, `"seven"`, `"-1"`, `"7`, `"7", "8"`} {
+		t.Run(header, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/", nil)
+			r.Header.Set(HeaderIfMatch, header)
+			entity := newTestEntity(1, "first")
+
+			assertValidationCode(t, ApplyIfMatch(r, &entity), ETagInvalidCode)
+			assert.Equal(t, uint(1), entity.Version)
+		})
+	}
+}
+
+func TestETagRoundTrip(t *testing.T) {
+	w := httptest.NewRecorder()
+	entity := newTestEntity(1, "first")
+	entity.Version = 5
+	SetETag(w, entity)
+
+	r := httptest.NewRequest(http.MethodPut, "/", nil)
+	r.Header.Set(HeaderIfMatch, w.Header().Get(HeaderETag))
+	updated := newTestEntity(1, "second")
+	require.NoError(t, ApplyIfMatch(r, &updated))
+	assert.Equal(t, uint(5), updated.Version)
+}
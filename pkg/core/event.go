@@ -0,0 +1,17 @@
+package core
+
+import "context"
+
+// Event доменное событие. Публикуется в транзакции изменения, которое его порождает,
+// и доставляется внешним получателям только после фиксации этой транзакции
+type Event interface {
+	EventType() string
+	AggregateType() string
+	AggregateID() uint
+}
+
+// EventPublisher сохраняет события для последующей доставки. Вызывается внутри
+// TxManager.Do, чтобы события и изменение сохранялись или откатывались вместе
+type EventPublisher interface {
+	Publish(ctx context.Context, events ...Event) error
+}
@@ -8,23 +8,8 @@ import (
 	"log/slog"
 	"net/http"
 	"runtime"
-
-	"github.com/go-playground/validator/v10"
 )
 
-func CollectValidationDetails(err error) map[string]string {
-	validationErrors := err.(validator.ValidationErrors)
-
-	details := make(map[string]string)
-	if len(validationErrors) > 0 {
-		for _, validationError := range validationErrors {
-			details[validationError.Field()] = validationError.Tag()
-		}
-	}
-
-	return details
-}
-
 func ErrorHandler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
@@ -32,7 +17,7 @@ func ErrorHandler(next http.Handler) http.Handler {
 				slog.Error("Panic recovered", "error", err, "URL", r.URL.Path)
 				HandleError(w, r, &TechnicalError{
 					ErrorInfo: ErrorInfo{
-						Code:    "INTERNAL_ERROR",
+						Code:    InternalErrorCode,
 						Message: "Internal server error",
 					},
 				})
@@ -54,71 +39,45 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
-func HandleError(w http.ResponseWriter, r *http.Request, err error) {
-	logError(r.Context(), err)
-
-	var logicErr *LogicalError
-	var techErr *TechnicalError
-	var accessErr *AccessError
-
-	var response ErrorResponse
-	switch {
-	case errors.As(err, &logicErr):
-		response = *NewErrorResponse(
-			http.StatusInternalServerError,
-			logicErr.Code,
-			logicErr.Error(),
-			r.URL.Path,
-			r.Method,
-		)
-	case errors.As(err, &techErr):
-		response = *NewErrorResponse(
-			http.StatusInternalServerError,
-			techErr.Code,
-			techErr.Error(),
-			r.URL.Path,
-			r.Method,
-		)
-	case errors.As(err, &accessErr):
-		response = *NewErrorResponse(
-			http.StatusUnauthorized,
-			accessErr.Code,
-			accessErr.Error(),
-			r.URL.Path,
-			r.Method,
-		)
-	default:
-		response = *NewErrorResponse(
-			http.StatusInternalServerError,
-			"ERROR",
-			err.Error(),
-			r.URL.Path,
-			r.Method,
-		)
+// DecodeJSON разбирает JSON тело запроса в v. Ошибка разбора возвращается как DecodeError,
+// по которой HandleError отвечает клиенту 400
+func DecodeJSON(r *http.Request, v any) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return &DecodeError{Err: err}
 	}
+	return nil
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(response.Status)
+// HandleError записывает ошибку в ответ в формате application/problem+json
+func HandleError(w http.ResponseWriter, r *http.Request, err error) {
+	problem := ResolveProblem(err)
+	logError(r.Context(), err, problem.Problem.Status)
 
-	json.NewEncoder(w).Encode(response)
+	writeProblem(w, problem.Problem.Status, NewErrorResponse(problem, r))
 }
 
+// HandleValidationError записывает ошибку валидации тела запроса с ошибками по полям
 func HandleValidationError(w http.ResponseWriter, r *http.Request, err error, details map[string]string) {
-	response := *NewValidationErrorResponse(
-		http.StatusBadRequest,
-		err.Error(),
-		r.URL.Path,
-		details,
-	)
+	logError(r.Context(), err, http.StatusUnprocessableEntity)
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusBadRequest)
+	response := NewValidationErrorResponse(r, details)
+	writeProblem(w, http.StatusUnprocessableEntity, response)
+}
+
+func writeProblem(w http.ResponseWriter, status int, response any) {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(status)
 
 	json.NewEncoder(w).Encode(response)
 }
 
-func logError(ctx context.Context, err error) {
+// logError логирует ошибку запроса: ошибки клиента с уровнем warn, ошибки сервера со стеком
+func logError(ctx context.Context, err error, status int) {
 	log := LoggerFromContext(ctx)
+	if status < http.StatusInternalServerError {
+		log.Warn("request failed", "status", status, "error", err.Error())
+		return
+	}
 
 	attrs := []slog.Attr{
 		slog.String("error", err.Error()),
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+const (
+	LangRU = "ru"
+	LangEN = "en"
+	// DefaultLang язык сообщений в коде и язык ответа, если Accept-Language не задан
+	DefaultLang = LangRU
+)
+
+//go:embed messages/*.json
+var messageFiles embed.FS
+
+type langKeyType struct{}
+
+var langKey = langKeyType{}
+
+// supportedLanguages порядок важен: первый язык выбирается, если ни один из запрошенных не поддерживается
+var supportedLanguages = []language.Tag{language.Russian, language.English}
+
+var languageMatcher = language.NewMatcher(supportedLanguages)
+
+// MessageCatalog сообщения по языкам, ключ сообщения - стабильный код ошибки
+// или ключ вида problem.<вид ошибки>. Сообщение может содержать глаголы fmt для аргументов ошибки
+type MessageCatalog struct {
+	bundles map[string]map[string]string
+}
+
+var messageCatalog = mustLoadMessageCatalog()
+
+// mustLoadMessageCatalog загружает встроенные файлы messages/<язык>.json.
+// Некорректный файл - ошибка сборки, поэтому приводит к панике при старте
+func mustLoadMessageCatalog() *MessageCatalog {
+	catalog := &MessageCatalog{bundles: make(map[string]map[string]string)}
+
+	entries, err := messageFiles.ReadDir("messages")
+	if err != nil {
+		panic(fmt.Sprintf("read message catalog: %v", err))
+	}
+	for _, entry := range entries {
+		data, err := messageFiles.ReadFile(path.Join("messages", entry.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("read message bundle %s: %v", entry.Name(), err))
+		}
+		var bundle map[string]string
+		if err := json.Unmarshal(data, &bundle); err != nil {
+			panic(fmt.Sprintf("decode message bundle %s: %v", entry.Name(), err))
+		}
+		catalog.bundles[strings.TrimSuffix(entry.Name(), path.Ext(entry.Name()))] = bundle
+	}
+	return catalog
+}
+
+// Message возвращает шаблон сообщения на языке lang, при его отсутствии - на языке по умолчанию
+func (c *MessageCatalog) Message(lang, key string) (string, bool) {
+	if message, ok := c.bundles[lang][key]; ok {
+		return message, true
+	}
+	message, ok := c.bundles[DefaultLang][key]
+	return message, ok
+}
+
+// Localize возвращает сообщение каталога на языке запроса с подставленными аргументами
+func Localize(ctx context.Context, key string, args ...any) (string, bool) {
+	template, ok := messageCatalog.Message(LanguageFromContext(ctx), key)
+	if !ok {
+		return "", false
+	}
+	return formatMessage(template, args), true
+}
+
+// LanguageMiddleware выбирает язык ответа по заголовку Accept-Language
+func LanguageMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang := ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+		w.Header().Set("Content-Language", lang)
+
+		next.ServeHTTP(w, r.WithContext(WithLanguage(r.Context(), lang)))
+	})
+}
+
+// ParseAcceptLanguage возвращает поддерживаемый язык, наиболее подходящий заголовку Accept-Language
+func ParseAcceptLanguage(header string) string {
+	if header == "" {
+		return DefaultLang
+	}
+	tag, _ := language.MatchStrings(languageMatcher, header)
+	base, _ := tag.Base()
+	return base.String()
+}
+
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, langKey, lang)
+}
+
+// LanguageFromContext язык запроса, вне запроса - язык по умолчанию
+func LanguageFromContext(ctx context.Context) string {
+	if lang, ok := ctx.Value(langKey).(string); ok {
+		return lang
+	}
+	return DefaultLang
+}
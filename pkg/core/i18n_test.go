@@ -0,0 +1,120 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "", want: LangRU},
+		{header: "ru-RU", want: LangRU},
+		{header: "en", want: LangEN},
+		{header: "en-US,en;q=0.9", want: LangEN},
+		{header: "de-DE, en;q=0.5", want: LangEN},
+		{header: "de", want: DefaultLang},
+		{header: "not a language", want: DefaultLang},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseAcceptLanguage(tt.header))
+		})
+	}
+}
+
+func TestLanguageMiddleware(t *testing.T) {
+	var lang string
+	handler := LanguageMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		lang = LanguageFromContext(r.Context())
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Language", "en-GB")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	assert.Equal(t, LangEN, lang)
+	assert.Equal(t, LangEN, w.Header().Get("Content-Language"))
+	assert.Equal(t, DefaultLang, LanguageFromContext(context.Background()))
+}
+
+func TestMessageCatalogLanguagesHaveSameKeys(t *testing.T) {
+	ru := messageCatalog.bundles[LangRU]
+	en := messageCatalog.bundles[LangEN]
+
+	for key := range ru {
+		assert.Contains(t, en, key)
+	}
+	for key := range en {
+		// описания кодов на русском хранятся в реестре кодов ошибок
+		if !strings.HasPrefix(key, "description.") {
+			assert.Contains(t, ru, key)
+		}
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	en := WithLanguage(context.Background(), LangEN)
+
+	message, ok := Localize(en, RecordNotFoundCode, "Продукт", 5)
+	require.True(t, ok)
+	// перевод может использовать не все аргументы сообщения
+	assert.Equal(t, "Record with ID 5 does not exist", message)
+
+	message, ok = Localize(context.Background(), RecordNotFoundCode, "Продукт", 5)
+	require.True(t, ok)
+	assert.Equal(t, "Продукт с ИД 5 не существует", message)
+
+	_, ok = Localize(en, "UNKNOWN_CODE")
+	assert.False(t, ok)
+}
+
+func TestMessageCatalogFallsBackToDefaultLanguage(t *testing.T) {
+	message, ok := messageCatalog.Message("de", ValidationErrorCode)
+	require.True(t, ok)
+	assert.Equal(t, messageCatalog.bundles[DefaultLang][ValidationErrorCode], message)
+}
+
+func TestProblemDetailLocalize(t *testing.T) {
+	en := WithLanguage(context.Background(), LangEN)
+
+	problem := ResolveProblem(NewNotFoundError(RecordNotFoundCode, "%s с ИД %d не существует", "Продукт", 5))
+	title, detail := problem.Localize(en)
+	assert.Equal(t, "Record not found", title)
+	assert.Equal(t, "Record with ID 5 does not exist", detail)
+
+	// для кода без перевода описание на другом языке заменяется заголовком
+	problem = ResolveProblem(NewLogicalError(nil, "TEST_SERVICE", "Сообщение без перевода"))
+	title, detail = problem.Localize(en)
+	assert.Equal(t, "Bad request", title)
+	assert.Equal(t, title, detail)
+
+	_, detail = problem.Localize(context.Background())
+	assert.Equal(t, "Сообщение без перевода", detail)
+}
+
+func TestHandleErrorUsesRequestLanguage(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/products/5", nil)
+	r = r.WithContext(WithLanguage(r.Context(), LangEN))
+	w := httptest.NewRecorder()
+
+	HandleError(w, r, NewNotFoundError(RecordNotFoundCode, "%s с ИД %d не существует", "Продукт", 5))
+
+	var response ErrorResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, http.StatusNotFound, response.Status)
+	assert.Equal(t, RecordNotFoundCode, response.Code)
+	assert.Equal(t, "Record not found", response.Title)
+	assert.Equal(t, "Record with ID 5 does not exist", response.Detail)
+	assert.Equal(t, "/products/5", response.Instance)
+}
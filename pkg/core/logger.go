@@ -56,3 +56,8 @@ func LoggerContextMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// RequestIDFromContext возвращает идентификатор запроса, который проставляет middleware.RequestID
+func RequestIDFromContext(ctx context.Context) string {
+	return middleware.GetReqID(ctx)
+}
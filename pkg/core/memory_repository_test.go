@@ -0,0 +1,102 @@
+package core
+
+import (
+	"context"
+)
+
+type testEntity struct {
+	Base
+
+	Name string
+}
+
+func (testEntity) TableName() string {
+	return "TESTENTITY"
+}
+
+func (testEntity) LocalTableName() string {
+	return "Тестовая запись"
+}
+
+func (testEntity) SearchFields() *SearchFields {
+	return NewSearchFields()
+}
+
+// memoryRepository репозиторий в памяти, считающий обращения к "базе"
+type memoryRepository struct {
+	BaseRepository[testEntity]
+
+	rows   map[uint]testEntity
+	nextID uint
+
+	findByIDCalls int
+	searchCalls   int
+	countCalls    int
+}
+
+func newMemoryRepository(rows ...testEntity) *memoryRepository {
+	repo := &memoryRepository{rows: make(map[uint]testEntity)}
+	for _, row := range rows {
+		repo.rows[row.ID] = row
+		repo.nextID = max(repo.nextID, row.ID)
+	}
+	return repo
+}
+
+func (r *memoryRepository) Create(_ context.Context, entity testEntity) (testEntity, error) {
+	r.nextID++
+	entity.ID = r.nextID
+	r.rows[entity.ID] = entity
+	return entity, nil
+}
+
+func (r *memoryRepository) Update(_ context.Context, entity testEntity) (testEntity, error) {
+	entity.Version++
+	r.rows[entity.ID] = entity
+	return entity, nil
+}
+
+func (r *memoryRepository) Delete(_ context.Context, entity testEntity) error {
+	delete(r.rows, entity.ID)
+	return nil
+}
+
+func (r *memoryRepository) FindByID(_ context.Context, id uint) (testEntity, error) {
+	r.findByIDCalls++
+	entity, ok := r.rows[id]
+	if !ok {
+		return entity, NewNotFoundError(RecordNotFoundCode, "%s с ИД %d не существует", entity.LocalTableName(), id)
+	}
+	return entity, nil
+}
+
+func (r *memoryRepository) FindByIDs(_ context.Context, ids []uint) ([]testEntity, error) {
+	r.findByIDCalls++
+	entities := make([]testEntity, 0, len(ids))
+	for _, id := range ids {
+		if entity, ok := r.rows[id]; ok {
+			entities = append(entities, entity)
+		}
+	}
+	return entities, nil
+}
+
+func (r *memoryRepository) FindWithSearchCriteria(_ context.Context, _ SearchCriteria) ([]testEntity, error) {
+	r.searchCalls++
+	entities := make([]testEntity, 0, len(r.rows))
+	for id := uint(1); id <= r.nextID; id++ {
+		if entity, ok := r.rows[id]; ok {
+			entities = append(entities, entity)
+		}
+	}
+	return entities, nil
+}
+
+func (r *memoryRepository) Count(_ context.Context, _ SearchCriteria) (int64, error) {
+	r.countCalls++
+	return int64(len(r.rows)), nil
+}
+
+func newTestEntity(id uint, name string) testEntity {
+	return testEntity{Base: Base{ID: id, Version: 1}, Name: name}
+}
@@ -0,0 +1,149 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+const (
+	ProblemContentType = "application/problem+json"
+
+	problemTypePrefix = "urn:backendstory:problem:"
+)
+
+// Problem вид ошибки в формате RFC 7807: HTTP статус, URI типа и заголовок
+type Problem struct {
+	Type   string
+	Title  string
+	Status int
+	// titleKey ключ заголовка в каталоге сообщений
+	titleKey string
+}
+
+func newProblem(slug, title string, status int) Problem {
+	return Problem{
+		Type:     problemTypePrefix + slug,
+		Title:    title,
+		Status:   status,
+		titleKey: "problem." + slug,
+	}
+}
+
+var (
+	ProblemBadRequest    = newProblem("bad-request", "Некорректный запрос", http.StatusBadRequest)
+	ProblemUnprocessable = newProblem("validation", "Данные запроса не прошли проверку", http.StatusUnprocessableEntity)
+	ProblemUnauthorized  = newProblem("unauthorized", "Требуется аутентификация", http.StatusUnauthorized)
+	ProblemForbidden     = newProblem("forbidden", "Доступ запрещен", http.StatusForbidden)
+	ProblemNotFound      = newProblem("not-found", "Запись не найдена", http.StatusNotFound)
+	ProblemConflict      = newProblem("conflict", "Конфликт данных", http.StatusConflict)
+	ProblemUnavailable   = newProblem("unavailable", "Сервис временно недоступен", http.StatusServiceUnavailable)
+	ProblemInternal      = newProblem("internal", "Внутренняя ошибка сервера", http.StatusInternalServerError)
+)
+
+// problemsByStatus виды ошибок по HTTP статусу, по нему выбирается вид для кода из реестра
+var problemsByStatus = map[int]Problem{
+	ProblemBadRequest.Status:    ProblemBadRequest,
+	ProblemUnprocessable.Status: ProblemUnprocessable,
+	ProblemUnauthorized.Status:  ProblemUnauthorized,
+	ProblemForbidden.Status:     ProblemForbidden,
+	ProblemNotFound.Status:      ProblemNotFound,
+	ProblemConflict.Status:      ProblemConflict,
+	ProblemUnavailable.Status:   ProblemUnavailable,
+	ProblemInternal.Status:      ProblemInternal,
+}
+
+// ProblemDetail вид ошибки, её код и описание для клиента
+type ProblemDetail struct {
+	Problem Problem
+	// Code код из реестра кодов ошибок, он же ключ описания в каталоге сообщений
+	Code   string
+	Detail string
+	Args   []any
+}
+
+// ResolveProblem определяет вид ошибки, её код из реестра и описание для клиента. Ошибки проверяются
+// по всей цепочке обертывания в порядке от наиболее конкретной к общей. Код и описание технических
+// ошибок не раскрываются, кроме недоступности внешнего сервиса: код компонента остается в логах
+func ResolveProblem(err error) ProblemDetail {
+	var (
+		notFoundErr   *NotFoundError
+		conflictErr   *ConflictError
+		forbiddenErr  *ForbiddenError
+		accessErr     *AccessError
+		validationErr *ValidationError
+		logicErr      *LogicalError
+		techErr       *TechnicalError
+	)
+
+	switch {
+	case errors.As(err, &notFoundErr):
+		code := notFoundErr.Code
+		if code == "" {
+			code = NotFoundCode
+		}
+		return newProblemDetail(ProblemNotFound, code, notFoundErr.Message, notFoundErr.Args)
+	case errors.As(err, &conflictErr):
+		return newProblemDetail(ProblemConflict, conflictErr.Code, conflictErr.Message, conflictErr.Args)
+	case errors.As(err, &forbiddenErr):
+		return newProblemDetail(ProblemForbidden, forbiddenErr.Code, forbiddenErr.Message, forbiddenErr.Args)
+	case errors.As(err, &accessErr):
+		return newProblemDetail(ProblemUnauthorized, accessErr.Code, accessErr.Message, accessErr.Args)
+	case isDecodeError(err):
+		return newProblemDetail(ProblemBadRequest, BadRequestCode, badRequestDetail, nil)
+	case errors.As(err, &validationErr):
+		return newProblemDetail(ProblemBadRequest, validationErr.Code, validationErr.Message, validationErr.Args)
+	case errors.As(err, &logicErr):
+		return newProblemDetail(ProblemBadRequest, logicErr.Code, logicErr.Message, logicErr.Args)
+	case errors.Is(err, ErrCircuitOpen) && errors.As(err, &techErr):
+		return newProblemDetail(ProblemUnavailable, techErr.Code, techErr.Message, techErr.Args)
+	default:
+		return newProblemDetail(ProblemInternal, InternalErrorCode, internalErrorDetail, nil)
+	}
+}
+
+// newProblemDetail формирует описание ошибки. Для кода из реестра статус берется из реестра,
+// чтобы ответ совпадал с описанием кода в GET /error-codes независимо от типа ошибки
+func newProblemDetail(problem Problem, code, detail string, args []any) ProblemDetail {
+	if status, ok := errorCodeStatuses[code]; ok && status != problem.Status {
+		if registered, ok := problemsByStatus[status]; ok {
+			problem = registered
+		}
+	}
+	return ProblemDetail{
+		Problem: problem,
+		Code:    code,
+		Detail:  detail,
+		Args:    args,
+	}
+}
+
+// Localize возвращает заголовок и описание на языке запроса. Если в каталоге нет сообщения
+// для кода ошибки, а язык запроса отличается от языка сообщений в коде, описанием служит заголовок
+func (d ProblemDetail) Localize(ctx context.Context) (string, string) {
+	title, ok := Localize(ctx, d.Problem.titleKey)
+	if !ok {
+		title = d.Problem.Title
+	}
+
+	if detail, ok := Localize(ctx, d.Code, d.Args...); ok {
+		return title, detail
+	}
+	if LanguageFromContext(ctx) != DefaultLang {
+		return title, title
+	}
+	return title, d.Detail
+}
+
+const (
+	internalErrorDetail = "Внутренняя ошибка сервера, повторите запрос позже"
+	badRequestDetail    = "Тело запроса не является корректным JSON"
+)
+
+// isDecodeError ошибка разбора тела запроса. Обработчики оборачивают её в TechnicalError,
+// но причина в данных клиента. Учитываются только ошибки, помеченные DecodeJSON: io.EOF
+// и ошибки JSON из других источников (кэш, внешние сервисы) остаются ошибками сервера
+func isDecodeError(err error) bool {
+	var decodeErr *DecodeError
+	return errors.As(err, &decodeErr)
+}
@@ -0,0 +1,49 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveProblemDecodeErrors(t *testing.T) {
+	decode := func(body string) error {
+		var req struct {
+			ID uint `json:"id"`
+		}
+		err := DecodeJSON(httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)), &req)
+		require.Error(t, err)
+		return NewTechnicalError(err, "TEST_HANDLER", err.Error())
+	}
+	var cached map[string]any
+	otherJSONErr := json.Unmarshal([]byte("{"), &cached)
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{name: "empty body", err: decode(""), status: http.StatusBadRequest, code: BadRequestCode},
+		{name: "truncated body", err: decode(`{"id":`), status: http.StatusBadRequest, code: BadRequestCode},
+		{name: "syntax error", err: decode(`{id}`), status: http.StatusBadRequest, code: BadRequestCode},
+		{name: "wrong type", err: decode(`{"id":"one"}`), status: http.StatusBadRequest, code: BadRequestCode},
+		{name: "eof from storage", err: NewTechnicalError(fmt.Errorf("read cache: %w", io.EOF), "TEST_SERVICE", "cache"), status: http.StatusInternalServerError, code: InternalErrorCode},
+		{name: "unexpected eof from storage", err: NewTechnicalError(io.ErrUnexpectedEOF, "TEST_SERVICE", "db"), status: http.StatusInternalServerError, code: InternalErrorCode},
+		{name: "json error from storage", err: NewTechnicalError(otherJSONErr, "TEST_SERVICE", "cache"), status: http.StatusInternalServerError, code: InternalErrorCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := ResolveProblem(tt.err)
+			assert.Equal(t, tt.status, problem.Problem.Status)
+			assert.Equal(t, tt.code, problem.Code)
+		})
+	}
+}
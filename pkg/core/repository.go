@@ -3,19 +3,35 @@ package core
 import (
 	"context"
 	"errors"
+	"slices"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+const (
+	versionColumn = "VERSION"
+
+	// createBatchSize количество строк в одном INSERT при пакетном создании
+	createBatchSize = 100
+)
+
 type BaseRepository[T BaseEntity] interface {
 	GetDB(ctx context.Context) *gorm.DB
 
 	Create(ctx context.Context, entity T) (T, error)
 	Update(ctx context.Context, entity T) (T, error)
 	Delete(ctx context.Context, entity T) error
+	Restore(ctx context.Context, entity T) (T, error)
+	Purge(ctx context.Context, entity T) error
+
+	CreateMany(ctx context.Context, entities []T) ([]T, error)
+	UpdateMany(ctx context.Context, entities []T) ([]T, error)
+	DeleteMany(ctx context.Context, entities []T) error
 
 	FindAll(ctx context.Context) ([]T, error)
 	FindByID(ctx context.Context, id uint) (T, error)
+	FindByIDs(ctx context.Context, ids []uint) ([]T, error)
 	FindWithSearchCriteria(ctx context.Context, criteria SearchCriteria) ([]T, error)
 
 	Count(ctx context.Context, criteria SearchCriteria) (int64, error)
@@ -33,49 +49,251 @@ func NewBaseRepositoryImpl[T BaseEntity](db *gorm.DB) *BaseRepositoryImpl[T] {
 
 func (r *BaseRepositoryImpl[T]) GetDB(ctx context.Context) *gorm.DB {
 	// Пробуем получить транзакцию из контекста
-	if tx, ok := ctx.Value(TxCtxKeyCode).(*gorm.DB); ok {
-		return tx
+	db, ok := ctx.Value(TxCtxKeyCode).(*gorm.DB)
+	if !ok {
+		// Если транзакции нет, используем обычное соединение
+		db = r.db.WithContext(ctx)
+	}
+	// Мягко удаленные записи скрываются, если контекст не требует иного
+	if isSoftDeletable[T]() {
+		return applyDeletedScope(ctx, db)
 	}
-	// Если транзакции нет, используем обычное соединение
-	return r.db.WithContext(ctx)
+	return db
 }
 
 // Create создает новую запись
 func (r *BaseRepositoryImpl[T]) Create(ctx context.Context, entity T) (T, error) {
+	if entity.GetVersion() == 0 {
+		any(&entity).(Versioned).SetVersion(1)
+	}
 	if err := r.GetDB(ctx).Create(&entity).Error; err != nil {
 		return entity, err
 	}
+	if err := r.audit(ctx, AuditCreate, entity.GetID(), nil, &entity); err != nil {
+		return entity, err
+	}
 	return entity, nil
 }
 
-// Update обновляет существующую запись
+// Update обновляет существующую запись, если её версия не изменилась с момента чтения,
+// и увеличивает версию. Иначе возвращает ConflictError. При ошибке возвращает запись
+// с исходной версией, чтобы повтор транзакции не получил ложный конфликт
 func (r *BaseRepositoryImpl[T]) Update(ctx context.Context, entity T) (T, error) {
-	if err := r.GetDB(ctx).Save(&entity).Error; err != nil {
+	before := r.auditBefore(ctx, entity.GetID())
+	updated, err := r.update(ctx, entity)
+	if err != nil {
+		return updated, err
+	}
+	if err := r.audit(ctx, AuditUpdate, updated.GetID(), before, &updated); err != nil {
 		return entity, err
 	}
-	return entity, nil
+	return updated, nil
 }
 
-// Delete выполняет мягкое удаление
+func (r *BaseRepositoryImpl[T]) update(ctx context.Context, entity T) (T, error) {
+	version := entity.GetVersion()
+	updated := entity
+	any(&updated).(Versioned).SetVersion(version + 1)
+
+	result := r.GetDB(ctx).
+		Model(&updated).
+		Where(versionColumn+" = ?", version).
+		Select("*").
+		Updates(&updated)
+	if result.Error != nil {
+		return entity, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entity, NewConflictError(nil, RecordVersionConflictCode, "Запись %s с ИД %d была изменена или удалена другим пользователем, обновите данные", entity.LocalTableName(), entity.GetID())
+	}
+	return updated, nil
+}
+
+// Delete удаляет запись, если её версия не изменилась с момента чтения, иначе возвращает ConflictError.
+// Для SoftDeletable сущностей проставляет дату удаления
 func (r *BaseRepositoryImpl[T]) Delete(ctx context.Context, entity T) error {
-	if err := r.GetDB(ctx).Delete(&entity).Error; err != nil {
+	before := entity
+	if softDeletable, ok := any(&entity).(SoftDeletable); ok {
+		softDeletable.MarkDeleted(time.Now())
+		deleted, err := r.update(ctx, entity)
+		if err != nil {
+			return err
+		}
+		return r.audit(ctx, AuditDelete, entity.GetID(), &before, &deleted)
+	}
+	result := r.GetDB(ctx).Where(versionColumn+" = ?", entity.GetVersion()).Delete(&entity)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return NewConflictError(nil, RecordVersionConflictCode, "Запись %s с ИД %d была изменена или удалена другим пользователем, обновите данные", entity.LocalTableName(), entity.GetID())
+	}
+	return r.audit(ctx, AuditDelete, entity.GetID(), &before, nil)
+}
+
+// Restore восстанавливает мягко удаленную запись
+func (r *BaseRepositoryImpl[T]) Restore(ctx context.Context, entity T) (T, error) {
+	softDeletable, ok := any(&entity).(SoftDeletable)
+	if !ok {
+		return entity, NewLogicalError(nil, RecordRestoreUnsupportedCode, "Запись %s не поддерживает восстановление", entity.LocalTableName())
+	}
+	before := entity
+	softDeletable.ClearDeleted()
+	restored, err := r.update(OnlyDeleted(ctx), entity)
+	if err != nil {
+		return restored, err
+	}
+	if err := r.audit(ctx, AuditRestore, restored.GetID(), &before, &restored); err != nil {
+		return before, err
+	}
+	return restored, nil
+}
+
+// Purge безвозвратно удаляет запись, в том числе мягко удаленную
+func (r *BaseRepositoryImpl[T]) Purge(ctx context.Context, entity T) error {
+	if err := r.GetDB(WithDeleted(ctx)).Delete(&entity).Error; err != nil {
 		return err
 	}
+	return r.audit(ctx, AuditPurge, entity.GetID(), &entity, nil)
+}
+
+// CreateMany создает записи пакетными INSERT по createBatchSize строк
+func (r *BaseRepositoryImpl[T]) CreateMany(ctx context.Context, entities []T) ([]T, error) {
+	if len(entities) == 0 {
+		return entities, nil
+	}
+	for i := range entities {
+		if entities[i].GetVersion() == 0 {
+			any(&entities[i]).(Versioned).SetVersion(1)
+		}
+	}
+	if err := r.GetDB(ctx).CreateInBatches(&entities, createBatchSize).Error; err != nil {
+		return entities, err
+	}
+	for i := range entities {
+		if err := r.audit(ctx, AuditCreate, entities[i].GetID(), nil, &entities[i]); err != nil {
+			return entities, err
+		}
+	}
+	return entities, nil
+}
+
+// UpdateMany обновляет записи с проверкой версии каждой из них.
+// Вызывается внутри транзакции, чтобы конфликт по одной записи откатывал весь пакет
+func (r *BaseRepositoryImpl[T]) UpdateMany(ctx context.Context, entities []T) ([]T, error) {
+	// состояние до изменения для журнала аудита читаем одним запросом
+	before := make(map[uint]T)
+	if _, ok := r.auditRecorder(); ok && len(entities) > 0 {
+		existing, err := r.FindByIDs(WithDeleted(ctx), entityIDs(entities))
+		if err != nil {
+			return nil, err
+		}
+		for _, entity := range existing {
+			before[entity.GetID()] = entity
+		}
+	}
+
+	updated := make([]T, 0, len(entities))
+	for _, entity := range entities {
+		entity, err := r.update(ctx, entity)
+		if err != nil {
+			return updated, err
+		}
+		var previous *T
+		if existing, ok := before[entity.GetID()]; ok {
+			previous = &existing
+		}
+		if err := r.audit(ctx, AuditUpdate, entity.GetID(), previous, &entity); err != nil {
+			return updated, err
+		}
+		updated = append(updated, entity)
+	}
+	return updated, nil
+}
+
+// DeleteMany удаляет записи одним запросом по парам (ID, версия), как Update проверяя,
+// что ни одна запись не изменилась после версии, которую видел клиент. Для SoftDeletable сущностей проставляет
+// дату удаления и увеличивает версию. Вызывается внутри транзакции, чтобы конфликт
+// по одной записи откатывал весь пакет
+func (r *BaseRepositoryImpl[T]) DeleteMany(ctx context.Context, entities []T) error {
+	if len(entities) == 0 {
+		return nil
+	}
+	versions := entityVersions(entities)
+
+	deletedAt := time.Now()
+	var result *gorm.DB
+	if isSoftDeletable[T]() {
+		result = r.GetDB(ctx).
+			Model(new(T)).
+			Where("("+idColumn+", "+versionColumn+") IN ?", versions).
+			Updates(map[string]any{
+				deletedAtColumn: deletedAt,
+				versionColumn:   gorm.Expr(versionColumn + " + 1"),
+			})
+	} else {
+		result = r.GetDB(ctx).
+			Where("("+idColumn+", "+versionColumn+") IN ?", versions).
+			Delete(new(T))
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != int64(len(entities)) {
+		var entity T
+		return NewConflictError(nil, RecordsVersionConflictCode, "Часть записей %s была изменена или удалена другим пользователем, обновите данные", entity.LocalTableName())
+	}
+
+	for _, entity := range entities {
+		before := entity
+		if softDeletable, ok := any(&entity).(SoftDeletable); ok {
+			softDeletable.MarkDeleted(deletedAt)
+			any(&entity).(Versioned).SetVersion(entity.GetVersion() + 1)
+			if err := r.audit(ctx, AuditDelete, entity.GetID(), &before, &entity); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := r.audit(ctx, AuditDelete, entity.GetID(), &before, nil); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// entityVersions собирает пары (ID, версия) для условия "(ID, VERSION) IN ((?, ?), ...)"
+func entityVersions[T BaseEntity](entities []T) []any {
+	versions := make([]any, 0, len(entities))
+	for _, entity := range entities {
+		versions = append(versions, []any{entity.GetID(), entity.GetVersion()})
+	}
+	return versions
+}
+
 // FindByID ищет запись по ID
 func (r *BaseRepositoryImpl[T]) FindByID(ctx context.Context, id uint) (T, error) {
 	var entity T
 	if err := r.GetDB(ctx).First(&entity, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return entity, NewNotFoundError(entity.LocalTableName() + " с переданным ID не существует")
+			return entity, NewNotFoundError(RecordNotFoundCode, "%s с ИД %d не существует", entity.LocalTableName(), id)
 		}
 		return entity, err
 	}
 	return entity, nil
 }
 
+// FindByIDs ищет записи по списку ID одним запросом. Отсутствующие ID пропускаются
+func (r *BaseRepositoryImpl[T]) FindByIDs(ctx context.Context, ids []uint) ([]T, error) {
+	if len(ids) == 0 {
+		return []T{}, nil
+	}
+	var entities []T
+	if err := r.GetDB(ctx).Where(idColumn+" IN ?", ids).Find(&entities).Error; err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
 // FindAll ищет все записи
 func (r *BaseRepositoryImpl[T]) FindAll(ctx context.Context) ([]T, error) {
 	var entities []T
@@ -88,21 +306,33 @@ func (r *BaseRepositoryImpl[T]) FindAll(ctx context.Context) ([]T, error) {
 // FindWithSearchCriteria ищет записи по критериям поиска
 func (r *BaseRepositoryImpl[T]) FindWithSearchCriteria(ctx context.Context, criteria SearchCriteria) ([]T, error) {
 	var entities []T
+	var entity T
 	q := r.GetDB(ctx)
-	queryCtx := BuildQuery(q, criteria)
+	queryCtx, err := BuildQuery(q, entity.SearchFields(), criteria)
+	if err != nil {
+		return nil, err
+	}
 
-	if err := queryCtx.Debug().Find(&entities).Error; err != nil {
+	if err := queryCtx.Find(&entities).Error; err != nil {
 		return nil, err
 	}
+	// страница перед курсором выбирается в обратном порядке
+	if criteria.Before != nil {
+		slices.Reverse(entities)
+	}
 	return entities, nil
 }
 
-// Count возвращает количество записей по критериям
+// Count возвращает количество записей по условиям критериев без учёта пагинации
 func (r *BaseRepositoryImpl[T]) Count(ctx context.Context, criteria SearchCriteria) (int64, error) {
 	var count int64
+	var entity T
 	query := r.GetDB(ctx).Model(new(T))
 
-	query = BuildQuery(query, criteria)
+	query, err := BuildCountQuery(query, entity.SearchFields(), criteria)
+	if err != nil {
+		return 0, err
+	}
 
 	if err := query.Count(&count).Error; err != nil {
 		return 0, err
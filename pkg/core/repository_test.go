@@ -0,0 +1,83 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingAuditRecorder журнал аудита, который не может сохранить запись
+type failingAuditRecorder struct {
+	err     error
+	entries []AuditEntry
+}
+
+func (r *failingAuditRecorder) Record(_ context.Context, entry AuditEntry) error {
+	r.entries = append(r.entries, entry)
+	return r.err
+}
+
+func TestRepositoryAuditFailureRollsBackWrite(t *testing.T) {
+	txm, recorder := newTestTxManager(t)
+	audit := &failingAuditRecorder{err: errors.New("audit log unavailable")}
+	require.NoError(t, RegisterAuditRecorder(txm.db, audit))
+	repo := NewBaseRepositoryImpl[testEntity](txm.db)
+
+	err := txm.Do(context.Background(), func(ctx context.Context) error {
+		return repo.Delete(ctx, newTestEntity(1, "first"))
+	})
+
+	assert.ErrorIs(t, err, audit.err)
+	require.Len(t, audit.entries, 1)
+	assert.Equal(t, AuditDelete, audit.entries[0].Operation)
+	assert.Equal(t, uint(1), audit.entries[0].EntityID)
+	// удаление выполнено, но без записи в журнале транзакция откатывается
+	entries := recorder.entries()
+	require.NotEmpty(t, entries)
+	assert.Contains(t, entries[len(entries)-2], "DELETE")
+	assert.Equal(t, "c1 ROLLBACK", entries[len(entries)-1])
+}
+
+func TestRepositoryUpdateKeepsVersionOnError(t *testing.T) {
+	db, recorder := newRecordingDB(t)
+	repo := NewBaseRepositoryImpl[testEntity](db)
+	recorder.fail("UPDATE", errDeadlock)
+
+	entity, err := repo.Update(context.Background(), newTestEntity(1, "first"))
+	assert.ErrorIs(t, err, errDeadlock)
+	assert.Equal(t, uint(1), entity.Version)
+
+	recorder.rowsAffected = 0
+	entity, err = repo.Update(context.Background(), entity)
+	var conflictErr *ConflictError
+	assert.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, uint(1), entity.Version)
+}
+
+func TestRepositoryUpdateRetriedAfterDeadlock(t *testing.T) {
+	txm, recorder := newTestTxManager(t)
+	repo := NewBaseRepositoryImpl[testEntity](txm.db)
+	recorder.fail("UPDATE", errDeadlock)
+
+	// сервисы сохраняют результат в переменную, захваченную функцией транзакции
+	entity := newTestEntity(1, "first")
+	err := txm.Do(context.Background(), func(ctx context.Context) error {
+		var err error
+		entity, err = repo.Update(ctx, entity)
+		return err
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), entity.Version)
+
+	// обе попытки проверяют версию, прочитанную клиентом, и записывают следующую
+	updates := recorder.execArgs("UPDATE")
+	require.Len(t, updates, 2)
+	for _, args := range updates {
+		assert.Equal(t, int64(1), args[len(args)-1], "WHERE VERSION = ?")
+		assert.Contains(t, args, int64(2), "SET VERSION = ?")
+	}
+}
@@ -1,57 +1,176 @@
 package core
 
 import (
+	"context"
+	"errors"
 	"log/slog"
+	"math/rand/v2"
 	"time"
 )
 
-func Retry(operation string, fn func() (any, error), options ...RetryOption) (any, error) {
+// RetryAttempt результат одной попытки, передается в хук SetOnAttemptOpt
+type RetryAttempt struct {
+	Operation string
+	// Attempt номер попытки, начиная с 1
+	Attempt int
+	Err     error
+	// Duration длительность попытки
+	Duration time.Duration
+	// Delay задержка перед следующей попыткой. 0 - попытка последняя
+	Delay time.Duration
+}
+
+// Retry выполняет fn, повторяя её при ошибках, которые классификатор считает временными.
+// Задержка между попытками растет экспоненциально от baseDelay до maxDelay со случайным
+// разбросом jitter. Ожидание прерывается отменой контекста
+func Retry[T any](ctx context.Context, operation string, fn func(ctx context.Context) (T, error), options ...RetryOption) (T, error) {
 	retryOptions := RetryOptions{
 		maxRetries: 10,
-		delay:      1 * time.Second,
+		baseDelay:  100 * time.Millisecond,
+		maxDelay:   5 * time.Second,
+		jitter:     0.2,
+		retryable:  IsRetryable,
 	}
 
 	for _, option := range options {
 		option(&retryOptions)
 	}
 
+	var empty T
 	var err error
-	var res any
-	isSuccess := false
-
-	for i := 1; i <= retryOptions.maxRetries; i++ {
-		res, err = fn()
-		if err != nil {
-			slog.Warn("Retry operation!", "operation", operation, "attempt", i, "err", err)
-			<-time.After(retryOptions.delay)
-			continue
+	for attempt := 1; ; attempt++ {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return empty, errors.Join(err, ctxErr)
+		}
+
+		started := time.Now()
+		var res T
+		res, err = fn(ctx)
+
+		var delay time.Duration
+		last := err == nil || attempt >= retryOptions.maxRetries || !retryOptions.retryable(err)
+		if !last {
+			delay = retryOptions.backoff(attempt)
+		}
+		if retryOptions.onAttempt != nil {
+			retryOptions.onAttempt(RetryAttempt{
+				Operation: operation,
+				Attempt:   attempt,
+				Err:       err,
+				Duration:  time.Since(started),
+				Delay:     delay,
+			})
+		}
+
+		if err == nil {
+			return res, nil
+		}
+		if last {
+			slog.Error("Retry operation failed!", "operation", operation, "attempt", attempt, "err", err)
+			return empty, err
+		}
+
+		slog.Warn("Retry operation!", "operation", operation, "attempt", attempt, "delay", delay, "err", err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return empty, errors.Join(err, ctx.Err())
+		case <-timer.C:
 		}
-		isSuccess = true
-		break
 	}
+}
 
-	if !isSuccess {
-		slog.Error("Retry operation failed!", "operation", operation, "err", err)
-		return nil, err
+// IsRetryable классификатор по умолчанию: повторяются технические ошибки, но не логические,
+// ошибки валидации, доступа, конфликта версий, отсутствия записи и отмены контекста
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
 	}
-	return res, nil
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var (
+		logicalErr    *LogicalError
+		validationErr *ValidationError
+		accessErr     *AccessError
+		conflictErr   *ConflictError
+	)
+	switch {
+	case errors.As(err, &logicalErr),
+		errors.As(err, &validationErr),
+		errors.As(err, &accessErr),
+		errors.As(err, &conflictErr),
+		errors.Is(err, &NotFoundError{}):
+		return false
+	}
+	return true
 }
 
 type RetryOptions struct {
 	maxRetries int
-	delay      time.Duration
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+	jitter     float64
+	retryable  func(err error) bool
+	onAttempt  func(attempt RetryAttempt)
+}
+
+// backoff задержка после попытки attempt: baseDelay * 2^(attempt-1), не больше maxDelay,
+// уменьшенная на случайную долю до jitter, чтобы повторы разных клиентов не совпадали
+func (ro *RetryOptions) backoff(attempt int) time.Duration {
+	delay := ro.baseDelay
+	for i := 1; i < attempt && delay < ro.maxDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, ro.maxDelay)
+	if ro.jitter > 0 && delay > 0 {
+		delay -= time.Duration(rand.Float64() * ro.jitter * float64(delay))
+	}
+	return delay
 }
 
 type RetryOption func(*RetryOptions)
 
+// SetMaxRetriesOpt количество попыток, включая первую
 func SetMaxRetriesOpt(maxRetries int) RetryOption {
 	return func(ro *RetryOptions) {
 		ro.maxRetries = maxRetries
 	}
 }
 
+// SetBaseDelayOpt задержка перед первым повтором
+func SetBaseDelayOpt(delay time.Duration) RetryOption {
+	return func(ro *RetryOptions) {
+		ro.baseDelay = delay
+	}
+}
+
+// SetMaxDelayOpt предел задержки между попытками
 func SetMaxDelayOpt(delay time.Duration) RetryOption {
 	return func(ro *RetryOptions) {
-		ro.delay = delay
+		ro.maxDelay = delay
+	}
+}
+
+// SetJitterOpt доля задержки от 0 до 1, на которую она случайно уменьшается
+func SetJitterOpt(jitter float64) RetryOption {
+	return func(ro *RetryOptions) {
+		ro.jitter = min(max(jitter, 0), 1)
+	}
+}
+
+// SetRetryableOpt классификатор ошибок: false - ошибка постоянная и повтор бесполезен
+func SetRetryableOpt(retryable func(err error) bool) RetryOption {
+	return func(ro *RetryOptions) {
+		ro.retryable = retryable
+	}
+}
+
+// SetOnAttemptOpt хук, вызываемый после каждой попытки, например для метрик
+func SetOnAttemptOpt(onAttempt func(attempt RetryAttempt)) RetryOption {
+	return func(ro *RetryOptions) {
+		ro.onAttempt = onAttempt
 	}
 }
@@ -0,0 +1,176 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errTemporary = errors.New("temporary failure")
+
+func TestRetryBackoffGrowsAndCaps(t *testing.T) {
+	options := RetryOptions{baseDelay: 100 * time.Millisecond, maxDelay: time.Second}
+
+	assert.Equal(t, 100*time.Millisecond, options.backoff(1))
+	assert.Equal(t, 200*time.Millisecond, options.backoff(2))
+	assert.Equal(t, 400*time.Millisecond, options.backoff(3))
+	assert.Equal(t, 800*time.Millisecond, options.backoff(4))
+	assert.Equal(t, time.Second, options.backoff(5))
+	// большой номер попытки не переполняет задержку
+	assert.Equal(t, time.Second, options.backoff(100))
+}
+
+func TestRetryBackoffJitterBounds(t *testing.T) {
+	options := RetryOptions{baseDelay: time.Second, maxDelay: time.Second, jitter: 0.25}
+
+	for range 1000 {
+		delay := options.backoff(3)
+		assert.LessOrEqual(t, delay, time.Second)
+		assert.GreaterOrEqual(t, delay, 750*time.Millisecond)
+	}
+}
+
+func TestSetJitterOptClamps(t *testing.T) {
+	var options RetryOptions
+	SetJitterOpt(-1)(&options)
+	assert.Zero(t, options.jitter)
+	SetJitterOpt(2)(&options)
+	assert.Equal(t, 1.0, options.jitter)
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "plain error", err: errTemporary, want: true},
+		{name: "technical", err: NewTechnicalError(errTemporary, InternalErrorCode, "db down"), want: true},
+		{name: "canceled", err: fmt.Errorf("query: %w", context.Canceled), want: false},
+		{name: "deadline", err: context.DeadlineExceeded, want: false},
+		{name: "logical", err: NewLogicalError(nil, InternalErrorCode, "logic"), want: false},
+		{name: "validation", err: NewValidationError(nil, ValidationErrorCode, "invalid"), want: false},
+		{name: "access", err: NewAccessError(nil, InternalErrorCode, "denied"), want: false},
+		{name: "conflict", err: NewConflictError(nil, RecordVersionConflictCode, "conflict"), want: false},
+		{name: "wrapped not found", err: fmt.Errorf("get: %w", NewNotFoundError(RecordNotFoundCode, "missing")), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsRetryable(tt.err))
+		})
+	}
+}
+
+func TestRetrySucceedsAfterTemporaryFailures(t *testing.T) {
+	var attempts []RetryAttempt
+	calls := 0
+	res, err := Retry(context.Background(), "test", func(context.Context) (string, error) {
+		calls++
+		if calls < 3 {
+			return "", errTemporary
+		}
+		return "ok", nil
+	},
+		SetBaseDelayOpt(time.Millisecond),
+		SetJitterOpt(0),
+		SetOnAttemptOpt(func(attempt RetryAttempt) {
+			attempts = append(attempts, attempt)
+		}),
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, "ok", res)
+	require.Len(t, attempts, 3)
+	for i, attempt := range attempts {
+		assert.Equal(t, "test", attempt.Operation)
+		assert.Equal(t, i+1, attempt.Attempt)
+	}
+	assert.Equal(t, time.Millisecond, attempts[0].Delay)
+	assert.Equal(t, 2*time.Millisecond, attempts[1].Delay)
+	assert.Zero(t, attempts[2].Delay)
+	assert.NoError(t, attempts[2].Err)
+}
+
+func TestRetryStopsAtMaxAttempts(t *testing.T) {
+	var last RetryAttempt
+	calls := 0
+	_, err := Retry(context.Background(), "test", func(context.Context) (int, error) {
+		calls++
+		return 0, errTemporary
+	},
+		SetMaxRetriesOpt(4),
+		SetBaseDelayOpt(time.Millisecond),
+		SetOnAttemptOpt(func(attempt RetryAttempt) {
+			last = attempt
+		}),
+	)
+
+	assert.ErrorIs(t, err, errTemporary)
+	assert.Equal(t, 4, calls)
+	assert.Equal(t, 4, last.Attempt)
+	assert.Zero(t, last.Delay)
+}
+
+func TestRetryDoesNotRepeatPermanentError(t *testing.T) {
+	calls := 0
+	permanent := NewValidationError(nil, ValidationErrorCode, "invalid")
+	_, err := Retry(context.Background(), "test", func(context.Context) (int, error) {
+		calls++
+		return 0, permanent
+	}, SetBaseDelayOpt(time.Millisecond))
+
+	assert.ErrorIs(t, err, permanent)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryUsesCustomClassifier(t *testing.T) {
+	calls := 0
+	_, err := Retry(context.Background(), "test", func(context.Context) (int, error) {
+		calls++
+		return 0, errTemporary
+	},
+		SetBaseDelayOpt(time.Millisecond),
+		SetRetryableOpt(func(err error) bool {
+			return !errors.Is(err, errTemporary)
+		}),
+	)
+
+	assert.ErrorIs(t, err, errTemporary)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryStopsWaitingOnContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	started := time.Now()
+	_, err := Retry(ctx, "test", func(context.Context) (int, error) {
+		calls++
+		// отмена во время ожидания перед вторым повтором
+		time.AfterFunc(10*time.Millisecond, cancel)
+		return 0, errTemporary
+	}, SetBaseDelayOpt(time.Hour), SetMaxDelayOpt(time.Hour))
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, err, errTemporary)
+	assert.Equal(t, 1, calls)
+	assert.Less(t, time.Since(started), time.Second)
+}
+
+func TestRetryDoesNotStartWithCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls := 0
+	_, err := Retry(ctx, "test", func(context.Context) (int, error) {
+		calls++
+		return 0, nil
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, calls)
+}
@@ -1,28 +1,295 @@
 package core
 
 import (
-	"log/slog"
 	"strings"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-func BuildQuery(queryCtx *gorm.DB, criteria SearchCriteria) *gorm.DB {
+const (
+	enumTable      = "ENUMERATION"
+	enumValueTable = "ENUMERATIONVALUE"
+
+	// maxConditionDepth ограничивает вложенность групп условий
+	maxConditionDepth = 8
+
+	// likeEscape символ экранирования в шаблонах like
+	likeEscape = "!"
+)
+
+type groupOperator string
+
+const (
+	groupAnd groupOperator = "and"
+	groupOr  groupOperator = "or"
+	groupNot groupOperator = "not"
+)
+
+// resolvedCondition условие поиска, сопоставленное с колонкой и приведенное к типу поля
+type resolvedCondition struct {
+	field     SearchField
+	operation Operator
+	value     any
+}
+
+// conditionNode узел дерева условий: группа с дочерними узлами или одиночное условие
+type conditionNode struct {
+	operator  groupOperator
+	children  []conditionNode
+	condition *resolvedCondition
+}
+
+// BuildQuery строит запрос по критериям поиска. Поля условий и сортировки проверяются по реестру fields
+func BuildQuery(queryCtx *gorm.DB, fields *SearchFields, criteria SearchCriteria) (*gorm.DB, error) {
+	conditions, err := resolveCriteriaConditions(fields, criteria)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := parseSortKeys(fields, criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	if criteria.After != nil || criteria.Before != nil {
+		return buildKeysetQuery(queryCtx, conditions, keys, criteria)
+	}
 	return queryCtx.
-		Scopes(applySearchConditions(criteria.SearchConditions)).
+		Scopes(applySearchConditions(conditions)).
 		Scopes(applyPagination(criteria.Limit, criteria.Offset)).
-		Scopes(applyOrdering(criteria.OrderBy))
+		Scopes(applySortKeys(keys)), nil
+}
+
+// BuildCountQuery применяет только условия поиска, без пагинации и сортировки
+func BuildCountQuery(queryCtx *gorm.DB, fields *SearchFields, criteria SearchCriteria) (*gorm.DB, error) {
+	conditions, err := resolveCriteriaConditions(fields, criteria)
+	if err != nil {
+		return nil, err
+	}
+	return queryCtx.Scopes(applySearchConditions(conditions)), nil
+}
+
+// buildKeysetQuery строит запрос для постраничного чтения по курсору.
+// Для before выборка идёт в обратном порядке, результат разворачивает репозиторий
+func buildKeysetQuery(queryCtx *gorm.DB, conditions conditionNode, keys []sortKey, criteria SearchCriteria) (*gorm.DB, error) {
+	token := criteria.After
+	backward := false
+	if criteria.Before != nil {
+		token = criteria.Before
+		backward = true
+	}
+	c, err := decodeCursor(*token)
+	if err != nil {
+		return nil, err
+	}
+	values, err := cursorValues(c, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	// страница перед курсором - это страница после него при обратном порядке
+	if backward {
+		reversed := make([]sortKey, 0, len(keys))
+		for _, key := range keys {
+			reversed = append(reversed, key.reversed())
+		}
+		keys = reversed
+	}
+
+	return queryCtx.
+		Scopes(applySearchConditions(conditions)).
+		Where(keysetExpr(keys, values)).
+		Scopes(applyPagination(criteria.Limit, nil)).
+		Scopes(applySortKeys(keys)), nil
+}
+
+// resolveCriteriaConditions объединяет плоский список условий и дерево Filter в одну AND-группу
+func resolveCriteriaConditions(fields *SearchFields, criteria SearchCriteria) (conditionNode, error) {
+	root := conditionNode{operator: groupAnd}
+	for _, condition := range criteria.SearchConditions {
+		resolved, err := resolveCondition(fields, condition)
+		if err != nil {
+			return conditionNode{}, err
+		}
+		root.children = append(root.children, conditionNode{condition: &resolved})
+	}
+	if criteria.Filter != nil {
+		node, err := resolveGroup(fields, *criteria.Filter, 1)
+		if err != nil {
+			return conditionNode{}, err
+		}
+		root.children = append(root.children, node)
+	}
+	return root, nil
+}
+
+// resolveGroup рекурсивно разбирает узел дерева условий
+func resolveGroup(fields *SearchFields, group ConditionGroup, depth int) (conditionNode, error) {
+	if depth > maxConditionDepth {
+		return conditionNode{}, NewValidationError(nil, FilterDepthExceededCode, "Превышена допустимая вложенность условий (%d)", maxConditionDepth)
+	}
+
+	isCondition := group.Field != "" || group.Operation != ""
+	kinds := 0
+	for _, set := range []bool{group.And != nil, group.Or != nil, group.Not != nil, isCondition} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return conditionNode{}, NewValidationError(nil, FilterNodeInvalidCode, "Узел условий должен содержать ровно одно из: and, or, not или условие")
+	}
+
+	switch {
+	case isCondition:
+		resolved, err := resolveCondition(fields, SearchCondition{
+			Field:     group.Field,
+			Operation: group.Operation,
+			Value:     group.Value,
+		})
+		if err != nil {
+			return conditionNode{}, err
+		}
+		return conditionNode{condition: &resolved}, nil
+	case group.Not != nil:
+		child, err := resolveGroup(fields, *group.Not, depth+1)
+		if err != nil {
+			return conditionNode{}, err
+		}
+		return conditionNode{operator: groupNot, children: []conditionNode{child}}, nil
+	}
+
+	node := conditionNode{operator: groupAnd}
+	children := group.And
+	if group.Or != nil {
+		node.operator = groupOr
+		children = group.Or
+	}
+	if len(children) == 0 {
+		return conditionNode{}, NewValidationError(nil, FilterGroupEmptyCode, "Группа '%s' не содержит условий", node.operator)
+	}
+	for _, child := range children {
+		childNode, err := resolveGroup(fields, child, depth+1)
+		if err != nil {
+			return conditionNode{}, err
+		}
+		node.children = append(node.children, childNode)
+	}
+	return node, nil
+}
+
+// resolveCondition сопоставляет условие с реестром полей и приводит значение к типу колонки
+func resolveCondition(fields *SearchFields, condition SearchCondition) (resolvedCondition, error) {
+	field, err := fields.Lookup(condition.Field)
+	if err != nil {
+		return resolvedCondition{}, err
+	}
+
+	var value any
+	switch condition.Operation {
+	case OpEqual, OpNotEqual:
+		value, err = field.Coerce(condition.Value)
+	case OpGreater, OpGreaterEq, OpLess, OpLessEq:
+		if !field.Type.ordered() {
+			return resolvedCondition{}, unsupportedOperationError(field, condition.Operation)
+		}
+		value, err = field.Coerce(condition.Value)
+	case OpBetween:
+		if !field.Type.ordered() {
+			return resolvedCondition{}, unsupportedOperationError(field, condition.Operation)
+		}
+		var bounds []any
+		if bounds, err = field.CoerceList(condition.Value); err == nil && len(bounds) != 2 {
+			err = NewValidationError(nil, FilterBetweenValuesCode, "Для операции '%s' по полю '%s' ожидается два значения", condition.Operation, field.Name)
+		}
+		value = bounds
+	case OpIn, OpNotIn:
+		value, err = field.CoerceList(condition.Value)
+	case OpIsNull, OpNotNull:
+		// значение не используется
+	case OpLike:
+		if field.Type != FieldString {
+			return resolvedCondition{}, unsupportedOperationError(field, condition.Operation)
+		}
+		value, err = field.Coerce(condition.Value)
+	case OpStartsWith, OpEndsWith, OpContains, OpIContains:
+		if field.Type != FieldString {
+			return resolvedCondition{}, unsupportedOperationError(field, condition.Operation)
+		}
+		if value, err = field.Coerce(condition.Value); err == nil {
+			value = likePattern(condition.Operation, value.(string))
+		}
+	default:
+		return resolvedCondition{}, NewValidationError(nil, FilterOperationUnsupportedCode, "Операция '%s' не поддерживается", condition.Operation)
+	}
+	if err != nil {
+		return resolvedCondition{}, err
+	}
+
+	return resolvedCondition{
+		field:     field,
+		operation: condition.Operation,
+		value:     value,
+	}, nil
+}
+
+// likePattern экранирует спецсимволы like и добавляет шаблон по операции
+func likePattern(operation Operator, value string) string {
+	escaped := strings.NewReplacer(
+		likeEscape, likeEscape+likeEscape,
+		"%", likeEscape+"%",
+		"_", likeEscape+"_",
+	).Replace(value)
+
+	switch operation {
+	case OpStartsWith:
+		return escaped + "%"
+	case OpEndsWith:
+		return "%" + escaped
+	default:
+		return "%" + escaped + "%"
+	}
 }
 
-func applySearchConditions(conditions []SearchCondition) func(db *gorm.DB) *gorm.DB {
+func unsupportedOperationError(field SearchField, operation Operator) error {
+	return NewValidationError(nil, FilterOperationNotAllowedCode, "Операция '%s' недоступна для поля '%s'", operation, field.Name)
+}
+
+func applySearchConditions(conditions conditionNode) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
-		for _, condition := range conditions {
-			db = applyCondition(db, condition)
+		if expr := buildConditionExpr(db, conditions); expr != nil {
+			db = db.Where(expr)
 		}
 		return db
 	}
 }
 
+// buildConditionExpr рекурсивно строит выражение WHERE по дереву условий
+func buildConditionExpr(queryCtx *gorm.DB, node conditionNode) clause.Expression {
+	if node.condition != nil {
+		return applyCondition(queryCtx, *node.condition)
+	}
+
+	exprs := make([]clause.Expression, 0, len(node.children))
+	for _, child := range node.children {
+		if expr := buildConditionExpr(queryCtx, child); expr != nil {
+			exprs = append(exprs, expr)
+		}
+	}
+	if len(exprs) == 0 {
+		return nil
+	}
+
+	switch node.operator {
+	case groupOr:
+		return clause.Or(exprs...)
+	case groupNot:
+		return clause.Expr{SQL: "NOT (?)", Vars: []any{exprs[0]}}
+	default:
+		return clause.And(exprs...)
+	}
+}
+
 func applyPagination(limit int, offset *int) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
 		db = db.Limit(limit)
@@ -33,41 +300,70 @@ func applyPagination(limit int, offset *int) func(db *gorm.DB) *gorm.DB {
 	}
 }
 
-func applyOrdering(orderBy *string) func(db *gorm.DB) *gorm.DB {
-	return func(db *gorm.DB) *gorm.DB {
-		if orderBy != nil && *orderBy != "" {
-			db = db.Order(*orderBy)
-		}
-		return db
+func applyCondition(queryCtx *gorm.DB, condition resolvedCondition) clause.Expression {
+	column := condition.field.Column
+	switch condition.operation {
+	case OpIsNull:
+		return clause.Expr{SQL: column + " IS NULL"}
+	case OpNotNull:
+		return clause.Expr{SQL: column + " IS NOT NULL"}
+	}
+	if condition.field.Type == FieldEnum {
+		return applyEnumCondition(queryCtx, condition)
 	}
-}
 
-func applyCondition(queryCtx *gorm.DB, condition SearchCondition) *gorm.DB {
-	field := convertToDBField(condition.Field)
-	switch condition.Operation {
+	switch condition.operation {
 	case OpEqual:
-		return queryCtx.Where(field+" = ?", condition.Value)
+		return clause.Expr{SQL: column + " = ?", Vars: []any{condition.value}}
 	case OpNotEqual:
-		return queryCtx.Where(field+" != ?", condition.Value)
+		return clause.Expr{SQL: column + " != ?", Vars: []any{condition.value}}
 	case OpGreater:
-		return queryCtx.Where(field+" > ?", condition.Value)
+		return clause.Expr{SQL: column + " > ?", Vars: []any{condition.value}}
 	case OpGreaterEq:
-		return queryCtx.Where(field+" >= ?", condition.Value)
+		return clause.Expr{SQL: column + " >= ?", Vars: []any{condition.value}}
 	case OpLess:
-		return queryCtx.Where(field+" < ?", condition.Value)
+		return clause.Expr{SQL: column + " < ?", Vars: []any{condition.value}}
 	case OpLessEq:
-		return queryCtx.Where(field+" <= ?", condition.Value)
+		return clause.Expr{SQL: column + " <= ?", Vars: []any{condition.value}}
+	case OpBetween:
+		bounds := condition.value.([]any)
+		return clause.Expr{SQL: column + " BETWEEN ? AND ?", Vars: []any{bounds[0], bounds[1]}}
 	case OpIn:
-		return queryCtx.Where(field+" in ?", condition.Value)
+		return clause.Expr{SQL: column + " in ?", Vars: []any{condition.value}}
+	case OpNotIn:
+		return clause.Expr{SQL: column + " not in ?", Vars: []any{condition.value}}
 	case OpLike:
-		return queryCtx.Where(field+" like ?", condition.Value)
+		return clause.Expr{SQL: column + " like ?", Vars: []any{condition.value}}
+	case OpStartsWith, OpEndsWith, OpContains:
+		return clause.Expr{SQL: column + " like ? escape '" + likeEscape + "'", Vars: []any{condition.value}}
+	case OpIContains:
+		return clause.Expr{SQL: "LOWER(" + column + ") like LOWER(?) escape '" + likeEscape + "'", Vars: []any{condition.value}}
 	default:
-		slog.Warn("Undefined operation for search criteria", "operation", condition.Operation)
-		return queryCtx
+		// операции проверяются в resolveCondition
+		return nil
 	}
 }
 
-func convertToDBField(input string) string {
-	input = strings.ReplaceAll(input, "_", "")
-	return strings.ToUpper(input)
+// applyEnumCondition фильтрует колонку-ссылку на значение перечисления по кодам значений
+func applyEnumCondition(queryCtx *gorm.DB, condition resolvedCondition) clause.Expression {
+	var codes []any
+	switch condition.operation {
+	case OpEqual, OpNotEqual:
+		codes = []any{condition.value}
+	case OpIn, OpNotIn:
+		codes = condition.value.([]any)
+	default:
+		return nil
+	}
+
+	valueIDs := queryCtx.Session(&gorm.Session{NewDB: true}).
+		Table(enumValueTable).
+		Select(enumValueTable+".ID").
+		Joins("JOIN "+enumTable+" ON "+enumTable+".ID = "+enumValueTable+".ENUMERATIONID").
+		Where(enumTable+".CODE = ? AND "+enumValueTable+".CODE IN ?", condition.field.EnumCode, codes)
+
+	if condition.operation == OpNotEqual || condition.operation == OpNotIn {
+		return clause.Expr{SQL: condition.field.Column + " NOT IN (?)", Vars: []any{valueIDs}}
+	}
+	return clause.Expr{SQL: condition.field.Column + " IN (?)", Vars: []any{valueIDs}}
 }
@@ -0,0 +1,257 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildQueryConditionGroups(t *testing.T) {
+	label := func(value string) ConditionGroup {
+		return ConditionGroup{Field: "label", Operation: OpEqual, Value: value}
+	}
+
+	tests := []struct {
+		name     string
+		criteria SearchCriteria
+		where    string
+		vars     []any
+	}{
+		{
+			name:     "single condition",
+			criteria: SearchCriteria{Filter: &ConditionGroup{Field: "label", Operation: OpEqual, Value: "a"}},
+			where:    "LABEL = ?",
+			vars:     []any{"a"},
+		},
+		{
+			name:     "and",
+			criteria: SearchCriteria{Filter: &ConditionGroup{And: []ConditionGroup{label("a"), label("b")}}},
+			where:    "LABEL = ? AND LABEL = ?",
+			vars:     []any{"a", "b"},
+		},
+		{
+			name:     "or",
+			criteria: SearchCriteria{Filter: &ConditionGroup{Or: []ConditionGroup{label("a"), label("b")}}},
+			where:    "(LABEL = ? OR LABEL = ?)",
+			vars:     []any{"a", "b"},
+		},
+		{
+			name:     "not",
+			criteria: SearchCriteria{Filter: &ConditionGroup{Not: &ConditionGroup{Or: []ConditionGroup{label("a"), label("b")}}}},
+			where:    "NOT ((LABEL = ? OR LABEL = ?))",
+			vars:     []any{"a", "b"},
+		},
+		{
+			name: "nested groups",
+			criteria: SearchCriteria{Filter: &ConditionGroup{Or: []ConditionGroup{
+				{And: []ConditionGroup{label("a"), {Field: "id", Operation: OpGreater, Value: "1"}}},
+				{Not: &ConditionGroup{Field: "id", Operation: OpEqual, Value: "2"}},
+			}}},
+			where: "((LABEL = ? AND ID > ?) OR NOT (ID = ?))",
+			vars:  []any{"a", uint64(1), uint64(2)},
+		},
+		{
+			name: "flat conditions and filter are combined with and",
+			criteria: SearchCriteria{
+				SearchConditions: []SearchCondition{{Field: "id", Operation: OpLess, Value: "9"}},
+				Filter:           &ConditionGroup{Or: []ConditionGroup{label("a"), label("b")}},
+			},
+			where: "ID < ? AND (LABEL = ? OR LABEL = ?)",
+			vars:  []any{uint64(9), "a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.criteria.Limit = 10
+			sql, vars, err := searchSQL(t, tt.criteria)
+			require.NoError(t, err)
+			assert.Equal(t, "SELECT * FROM `SEARCHTEST` WHERE "+tt.where+" ORDER BY ID ASC LIMIT ?", sql)
+			assert.Equal(t, append(tt.vars, 10), vars)
+		})
+	}
+}
+
+func TestBuildQueryRejectsInvalidConditionGroups(t *testing.T) {
+	condition := ConditionGroup{Field: "label", Operation: OpEqual, Value: "a"}
+	deep := condition
+	for range maxConditionDepth {
+		deep = ConditionGroup{Not: &deep}
+	}
+
+	tests := []struct {
+		name   string
+		filter ConditionGroup
+		code   string
+	}{
+		{
+			name:   "empty node",
+			filter: ConditionGroup{},
+			code:   FilterNodeInvalidCode,
+		},
+		{
+			name:   "group and condition in one node",
+			filter: ConditionGroup{And: []ConditionGroup{condition}, Field: "label", Operation: OpEqual, Value: "b"},
+			code:   FilterNodeInvalidCode,
+		},
+		{
+			name:   "and with or",
+			filter: ConditionGroup{And: []ConditionGroup{condition}, Or: []ConditionGroup{condition}},
+			code:   FilterNodeInvalidCode,
+		},
+		{
+			name:   "empty group",
+			filter: ConditionGroup{Or: []ConditionGroup{}},
+			code:   FilterGroupEmptyCode,
+		},
+		{
+			name:   "too deep",
+			filter: deep,
+			code:   FilterDepthExceededCode,
+		},
+		{
+			name:   "invalid nested condition",
+			filter: ConditionGroup{And: []ConditionGroup{condition, {Field: "secret", Operation: OpEqual, Value: "x"}}},
+			code:   SearchFieldUnknownCode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := searchSQL(t, SearchCriteria{Limit: 10, Filter: &tt.filter})
+			assertValidationCode(t, err, tt.code)
+		})
+	}
+}
+
+func TestBuildQueryOperators(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition SearchCondition
+		where     string
+		vars      []any
+	}{
+		{
+			name:      "between",
+			condition: SearchCondition{Field: "id", Operation: OpBetween, Value: []any{"10", "20"}},
+			where:     "ID BETWEEN ? AND ?",
+			vars:      []any{uint64(10), uint64(20)},
+		},
+		{
+			name:      "is null",
+			condition: SearchCondition{Field: "note", Operation: OpIsNull},
+			where:     "NOTE IS NULL",
+		},
+		{
+			name:      "not null ignores value",
+			condition: SearchCondition{Field: "note", Operation: OpNotNull, Value: "ignored"},
+			where:     "NOTE IS NOT NULL",
+		},
+		{
+			name:      "in",
+			condition: SearchCondition{Field: "id", Operation: OpIn, Value: []any{"1", "2"}},
+			where:     "ID in (?,?)",
+			vars:      []any{uint64(1), uint64(2)},
+		},
+		{
+			name:      "not in",
+			condition: SearchCondition{Field: "id", Operation: OpNotIn, Value: []any{"1", "2"}},
+			where:     "ID not in (?,?)",
+			vars:      []any{uint64(1), uint64(2)},
+		},
+		{
+			name:      "like keeps pattern",
+			condition: SearchCondition{Field: "label", Operation: OpLike, Value: "a%b_"},
+			where:     "LABEL like ?",
+			vars:      []any{"a%b_"},
+		},
+		{
+			name:      "starts with escapes pattern",
+			condition: SearchCondition{Field: "label", Operation: OpStartsWith, Value: "50%_off!"},
+			where:     "LABEL like ? escape '!'",
+			vars:      []any{"50!%!_off!!%"},
+		},
+		{
+			name:      "ends with",
+			condition: SearchCondition{Field: "label", Operation: OpEndsWith, Value: "end"},
+			where:     "LABEL like ? escape '!'",
+			vars:      []any{"%end"},
+		},
+		{
+			name:      "contains",
+			condition: SearchCondition{Field: "label", Operation: OpContains, Value: "a_b"},
+			where:     "LABEL like ? escape '!'",
+			vars:      []any{"%a!_b%"},
+		},
+		{
+			name:      "icontains",
+			condition: SearchCondition{Field: "label", Operation: OpIContains, Value: "Mid"},
+			where:     "LOWER(LABEL) like LOWER(?) escape '!'",
+			vars:      []any{"%Mid%"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, vars, err := searchSQL(t, SearchCriteria{Limit: 10, SearchConditions: []SearchCondition{tt.condition}})
+			require.NoError(t, err)
+			assert.Equal(t, "SELECT * FROM `SEARCHTEST` WHERE "+tt.where+" ORDER BY ID ASC LIMIT ?", sql)
+			assert.Equal(t, append(tt.vars, 10), vars)
+		})
+	}
+}
+
+func TestBuildQueryEnumCondition(t *testing.T) {
+	sql, vars, err := searchSQL(t, SearchCriteria{
+		Limit:            10,
+		SearchConditions: []SearchCondition{{Field: "status", Operation: OpNotIn, Value: []any{"Draft", "Deleted"}}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `SEARCHTEST` WHERE STATUSID NOT IN "+
+		"(SELECT ENUMERATIONVALUE.ID FROM `ENUMERATIONVALUE` JOIN ENUMERATION ON ENUMERATION.ID = ENUMERATIONVALUE.ENUMERATIONID "+
+		"WHERE ENUMERATION.CODE = ? AND ENUMERATIONVALUE.CODE IN (?,?)) ORDER BY ID ASC LIMIT ?", sql)
+	assert.Equal(t, []any{"STATUS", "Draft", "Deleted", 10}, vars)
+}
+
+func TestBuildQueryRejectsInvalidOperatorValues(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition SearchCondition
+		code      string
+	}{
+		{
+			name:      "between with one value",
+			condition: SearchCondition{Field: "id", Operation: OpBetween, Value: []any{"1"}},
+			code:      FilterBetweenValuesCode,
+		},
+		{
+			name:      "between with scalar",
+			condition: SearchCondition{Field: "id", Operation: OpBetween, Value: "1"},
+			code:      SearchValueListExpectedCode,
+		},
+		{
+			name:      "between on bool",
+			condition: SearchCondition{Field: "active", Operation: OpBetween, Value: []any{false, true}},
+			code:      FilterOperationNotAllowedCode,
+		},
+		{
+			name:      "not in with scalar",
+			condition: SearchCondition{Field: "id", Operation: OpNotIn, Value: "1"},
+			code:      SearchValueListExpectedCode,
+		},
+		{
+			name:      "starts with on number",
+			condition: SearchCondition{Field: "price", Operation: OpStartsWith, Value: "1"},
+			code:      FilterOperationNotAllowedCode,
+		},
+		{
+			name:      "icontains with number",
+			condition: SearchCondition{Field: "label", Operation: OpIContains, Value: float64(1)},
+			code:      SearchValueInvalidCode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := searchSQL(t, SearchCriteria{Limit: 10, SearchConditions: []SearchCondition{tt.condition}})
+			assertValidationCode(t, err, tt.code)
+		})
+	}
+}
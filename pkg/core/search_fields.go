@@ -0,0 +1,193 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FieldType тип значения поля поиска
+type FieldType string
+
+const (
+	FieldString  FieldType = "string"
+	FieldUint    FieldType = "uint"
+	FieldDecimal FieldType = "decimal"
+	FieldBool    FieldType = "bool"
+	FieldTime    FieldType = "time"
+	// FieldEnum колонка-ссылка на значение перечисления, фильтруется по коду значения
+	FieldEnum FieldType = "enum"
+)
+
+// ordered сообщает, поддерживает ли тип сравнения больше/меньше
+func (t FieldType) ordered() bool {
+	return t != FieldBool && t != FieldEnum
+}
+
+// SearchField описывает поле сущности, доступное для фильтрации и сортировки
+type SearchField struct {
+	Name     string
+	Column   string
+	Type     FieldType
+	Sortable bool
+	// Nullable колонка допускает NULL, что учитывается при сортировке и пагинации по курсору
+	Nullable bool
+	// EnumCode код перечисления для полей типа FieldEnum
+	EnumCode string
+}
+
+// SearchFields реестр полей поиска сущности, ключ - имя поля в JSON
+type SearchFields struct {
+	byName map[string]SearchField
+}
+
+// NewSearchFields создает реестр полей. Поля core.Base (id, created_at, updated_at) добавляются автоматически
+func NewSearchFields(fields ...SearchField) *SearchFields {
+	registry := &SearchFields{
+		byName: make(map[string]SearchField, len(fields)+3),
+	}
+	for _, field := range baseSearchFields() {
+		registry.byName[field.Name] = field
+	}
+	for _, field := range fields {
+		registry.byName[field.Name] = field
+	}
+	return registry
+}
+
+func baseSearchFields() []SearchField {
+	return []SearchField{
+		{Name: "id", Column: idColumn, Type: FieldUint, Sortable: true},
+		{Name: "created_at", Column: "CREATEDAT", Type: FieldTime, Sortable: true},
+		{Name: "updated_at", Column: "UPDATEDAT", Type: FieldTime, Sortable: true},
+	}
+}
+
+// Lookup ищет поле по имени
+func (f *SearchFields) Lookup(name string) (SearchField, error) {
+	field, ok := f.byName[name]
+	if !ok {
+		return SearchField{}, NewValidationError(nil, SearchFieldUnknownCode, "Поле '%s' недоступно для поиска", name)
+	}
+	return field, nil
+}
+
+// LookupSortable ищет поле, по которому разрешена сортировка
+func (f *SearchFields) LookupSortable(name string) (SearchField, error) {
+	field, err := f.Lookup(name)
+	if err != nil {
+		return SearchField{}, err
+	}
+	if !field.Sortable {
+		return SearchField{}, NewValidationError(nil, SearchSortFieldUnsupportedCode, "Сортировка по полю '%s' недоступна", name)
+	}
+	return field, nil
+}
+
+// Coerce приводит значение из запроса к типу поля
+func (field SearchField) Coerce(value any) (any, error) {
+	coerced, err := field.coerce(value)
+	if err != nil {
+		return nil, NewValidationError(err, SearchValueInvalidCode, "Некорректное значение для поля '%s' (ожидается %s)", field.Name, field.Type)
+	}
+	return coerced, nil
+}
+
+// CoerceList приводит к типу поля каждый элемент списка значений
+func (field SearchField) CoerceList(value any) ([]any, error) {
+	values, ok := value.([]any)
+	if !ok {
+		return nil, NewValidationError(nil, SearchValueListExpectedCode, "Для поля '%s' ожидается список значений", field.Name)
+	}
+	coerced := make([]any, 0, len(values))
+	for _, v := range values {
+		c, err := field.Coerce(v)
+		if err != nil {
+			return nil, err
+		}
+		coerced = append(coerced, c)
+	}
+	return coerced, nil
+}
+
+func (field SearchField) coerce(value any) (any, error) {
+	switch field.Type {
+	case FieldString, FieldEnum:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected value type %T", value)
+		}
+		return s, nil
+	case FieldUint:
+		return coerceUint(value)
+	case FieldDecimal:
+		return coerceDecimal(value)
+	case FieldBool:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			return strconv.ParseBool(v)
+		}
+		return nil, fmt.Errorf("unexpected value type %T", value)
+	case FieldTime:
+		return coerceTime(value)
+	default:
+		return nil, fmt.Errorf("unknown field type %s", field.Type)
+	}
+}
+
+func coerceUint(value any) (uint64, error) {
+	switch v := value.(type) {
+	case float64:
+		if v < 0 || v != float64(uint64(v)) {
+			return 0, fmt.Errorf("value %v is not a non-negative integer", v)
+		}
+		return uint64(v), nil
+	case json.Number:
+		return strconv.ParseUint(v.String(), 10, 64)
+	case string:
+		return strconv.ParseUint(v, 10, 64)
+	case int:
+		if v < 0 {
+			return 0, fmt.Errorf("value %v is negative", v)
+		}
+		return uint64(v), nil
+	case uint:
+		return uint64(v), nil
+	}
+	return 0, fmt.Errorf("unexpected value type %T", value)
+}
+
+func coerceDecimal(value any) (decimal.Decimal, error) {
+	switch v := value.(type) {
+	case float64:
+		return decimal.NewFromFloat(v), nil
+	case json.Number:
+		return decimal.NewFromString(v.String())
+	case string:
+		return decimal.NewFromString(strings.TrimSpace(v))
+	case decimal.Decimal:
+		return v, nil
+	}
+	return decimal.Decimal{}, fmt.Errorf("unexpected value type %T", value)
+}
+
+func coerceTime(value any) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, time.DateTime, time.DateOnly} {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("value %q is not a timestamp", v)
+	}
+	return time.Time{}, fmt.Errorf("unexpected value type %T", value)
+}
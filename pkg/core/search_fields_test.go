@@ -0,0 +1,161 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchFieldsLookup(t *testing.T) {
+	fields := searchTestEntity{}.SearchFields()
+
+	field, err := fields.Lookup("price")
+	require.NoError(t, err)
+	assert.Equal(t, "PRICE", field.Column)
+
+	// поля core.Base доступны без явной регистрации
+	field, err = fields.Lookup("created_at")
+	require.NoError(t, err)
+	assert.Equal(t, "CREATEDAT", field.Column)
+
+	_, err = fields.Lookup("PRICE")
+	assertValidationCode(t, err, SearchFieldUnknownCode)
+
+	_, err = fields.LookupSortable("status")
+	assertValidationCode(t, err, SearchSortFieldUnsupportedCode)
+}
+
+func TestSearchFieldCoerce(t *testing.T) {
+	timestamp := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		field FieldType
+		value any
+		want  any
+	}{
+		{name: "string", field: FieldString, value: "abc", want: "abc"},
+		{name: "enum code", field: FieldEnum, value: "Available", want: "Available"},
+		{name: "uint from json number", field: FieldUint, value: json.Number("12"), want: uint64(12)},
+		{name: "uint from float", field: FieldUint, value: float64(12), want: uint64(12)},
+		{name: "uint from string", field: FieldUint, value: "12", want: uint64(12)},
+		{name: "decimal from string", field: FieldDecimal, value: " 10.25 ", want: decimal.RequireFromString("10.25")},
+		{name: "decimal from json number", field: FieldDecimal, value: json.Number("0.1"), want: decimal.RequireFromString("0.1")},
+		{name: "bool", field: FieldBool, value: true, want: true},
+		{name: "bool from string", field: FieldBool, value: "false", want: false},
+		{name: "time rfc3339", field: FieldTime, value: "2025-03-01T10:30:00Z", want: timestamp},
+		{name: "time datetime", field: FieldTime, value: "2025-03-01 10:30:00", want: timestamp},
+		{name: "date", field: FieldTime, value: "2025-03-01", want: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SearchField{Name: "field", Type: tt.field}.Coerce(tt.value)
+			require.NoError(t, err)
+			if want, ok := tt.want.(decimal.Decimal); ok {
+				assert.True(t, want.Equal(got.(decimal.Decimal)), "got %v", got)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSearchFieldCoerceRejectsInvalidValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		field FieldType
+		value any
+	}{
+		{name: "string from number", field: FieldString, value: float64(1)},
+		{name: "negative uint", field: FieldUint, value: float64(-1)},
+		{name: "fractional uint", field: FieldUint, value: 1.5},
+		{name: "uint from text", field: FieldUint, value: "one"},
+		{name: "decimal from text", field: FieldDecimal, value: "ten"},
+		{name: "bool from text", field: FieldBool, value: "yes please"},
+		{name: "time from text", field: FieldTime, value: "yesterday"},
+		{name: "time from number", field: FieldTime, value: float64(1700000000)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := SearchField{Name: "field", Type: tt.field}.Coerce(tt.value)
+			assertValidationCode(t, err, SearchValueInvalidCode)
+		})
+	}
+}
+
+func TestSearchFieldCoerceList(t *testing.T) {
+	field := SearchField{Name: "id", Type: FieldUint}
+
+	values, err := field.CoerceList([]any{"1", float64(2)})
+	require.NoError(t, err)
+	assert.Equal(t, []any{uint64(1), uint64(2)}, values)
+
+	_, err = field.CoerceList("1,2")
+	assertValidationCode(t, err, SearchValueListExpectedCode)
+
+	_, err = field.CoerceList([]any{"1", "two"})
+	assertValidationCode(t, err, SearchValueInvalidCode)
+}
+
+func TestBuildQueryChecksConditionFields(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition SearchCondition
+		code      string
+	}{
+		{
+			name:      "unknown field",
+			condition: SearchCondition{Field: "secret", Operation: OpEqual, Value: "x"},
+			code:      SearchFieldUnknownCode,
+		},
+		{
+			name:      "column name instead of field",
+			condition: SearchCondition{Field: "PRICE", Operation: OpEqual, Value: "1"},
+			code:      SearchFieldUnknownCode,
+		},
+		{
+			name:      "value of wrong type",
+			condition: SearchCondition{Field: "id", Operation: OpEqual, Value: "abc"},
+			code:      SearchValueInvalidCode,
+		},
+		{
+			name:      "comparison of bool",
+			condition: SearchCondition{Field: "active", Operation: OpGreater, Value: true},
+			code:      FilterOperationNotAllowedCode,
+		},
+		{
+			name:      "like on number",
+			condition: SearchCondition{Field: "price", Operation: OpLike, Value: "1%"},
+			code:      FilterOperationNotAllowedCode,
+		},
+		{
+			name:      "unknown operation",
+			condition: SearchCondition{Field: "label", Operation: "regexp", Value: ".*"},
+			code:      FilterOperationUnsupportedCode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := searchSQL(t, SearchCriteria{Limit: 10, SearchConditions: []SearchCondition{tt.condition}})
+			assertValidationCode(t, err, tt.code)
+		})
+	}
+}
+
+func TestBuildQueryCoercesConditionValues(t *testing.T) {
+	sql, vars, err := searchSQL(t, SearchCriteria{
+		Limit: 10,
+		SearchConditions: []SearchCondition{
+			{Field: "id", Operation: OpEqual, Value: "3"},
+			{Field: "price", Operation: OpGreaterEq, Value: json.Number("9.99")},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `SEARCHTEST` WHERE ID = ? AND PRICE >= ? ORDER BY ID ASC LIMIT ?", sql)
+	assert.Equal(t, uint64(3), vars[0])
+	assert.True(t, decimal.RequireFromString("9.99").Equal(vars[1].(decimal.Decimal)))
+}
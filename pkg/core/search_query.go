@@ -0,0 +1,205 @@
+package core
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultPageLimit размер страницы для списков без явного limit
+	DefaultPageLimit = 20
+	// MaxPageLimit ограничивает размер страницы, запрошенной через строку запроса
+	MaxPageLimit = 100
+)
+
+// queryOperators короткие обозначения операций в строке запроса.
+// Остальные операции записываются как =<операция>=, например price=between=(10,20)
+var queryOperators = []struct {
+	token     string
+	operation Operator
+}{
+	{"==", OpEqual},
+	{"!=", OpNotEqual},
+	{">=", OpGreaterEq},
+	{"<=", OpLessEq},
+	{">", OpGreater},
+	{"<", OpLess},
+}
+
+// queryOperatorAliases синонимы операций в записи =<операция>=
+var queryOperatorAliases = map[string]Operator{
+	"out": OpNotIn,
+	"sw":  OpStartsWith,
+	"ew":  OpEndsWith,
+}
+
+// ParseSearchQuery разбирает параметры строки запроса в критерии поиска:
+//
+//	?filter=price>=100;category_id==3;status=in=(Available,Unavailable)&sort=-price,label&limit=20&offset=0
+//
+// Условия filter объединяются через AND, значения со спецсимволами заключаются в двойные кавычки.
+// sort - список полей через запятую, префикс "-" задает сортировку по убыванию.
+// Принимает сырую строку запроса (r.URL.RawQuery): url.ParseQuery отбрасывает параметры с ';'
+func ParseSearchQuery(rawQuery string) (SearchCriteria, error) {
+	criteria := SearchCriteria{Limit: DefaultPageLimit}
+
+	query, err := parseRawQuery(rawQuery)
+	if err != nil {
+		return criteria, err
+	}
+
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > MaxPageLimit {
+			return criteria, NewValidationError(err, QueryLimitInvalidCode, "Параметр limit должен быть числом от 1 до %d", MaxPageLimit)
+		}
+		criteria.Limit = limit
+	}
+	if raw := query.Get("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return criteria, NewValidationError(err, QueryOffsetInvalidCode, "Параметр offset должен быть неотрицательным числом")
+		}
+		criteria.Offset = &offset
+	}
+	if raw := query.Get("after"); raw != "" {
+		criteria.After = &raw
+	}
+	if raw := query.Get("before"); raw != "" {
+		criteria.Before = &raw
+	}
+	if criteria.After != nil && criteria.Before != nil {
+		return criteria, NewValidationError(nil, CursorInvalidCode, "Параметры after и before не могут быть заданы одновременно")
+	}
+
+	if raw := query.Get("sort"); raw != "" {
+		for _, item := range strings.Split(raw, ",") {
+			item = strings.TrimSpace(item)
+			order := SortOrder{Field: item, Direction: SortAsc}
+			switch {
+			case strings.HasPrefix(item, "-"):
+				order.Field, order.Direction = item[1:], SortDesc
+			case strings.HasPrefix(item, "+"):
+				order.Field = item[1:]
+			}
+			if order.Field == "" {
+				return criteria, NewValidationError(nil, QuerySortEmptyFieldCode, "Пустое поле в параметре sort")
+			}
+			criteria.Sort = append(criteria.Sort, order)
+		}
+	}
+
+	if raw := query.Get("filter"); raw != "" {
+		for _, expr := range splitQuoted(raw, ';') {
+			if strings.TrimSpace(expr) == "" {
+				continue
+			}
+			condition, err := parseQueryCondition(expr)
+			if err != nil {
+				return criteria, err
+			}
+			criteria.SearchConditions = append(criteria.SearchConditions, condition)
+		}
+	}
+
+	return criteria, nil
+}
+
+// parseRawQuery разбирает строку запроса, разделяя параметры только по '&'
+func parseRawQuery(rawQuery string) (url.Values, error) {
+	query := url.Values{}
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(pair, "=")
+		key, err := url.QueryUnescape(key)
+		if err != nil {
+			return nil, NewValidationError(err, QueryInvalidCode, "Некорректная строка запроса")
+		}
+		value, err = url.QueryUnescape(value)
+		if err != nil {
+			return nil, NewValidationError(err, QueryInvalidCode, "Некорректная строка запроса")
+		}
+		query.Add(key, value)
+	}
+	return query, nil
+}
+
+// parseQueryCondition разбирает одно условие вида <поле><операция><значение>
+func parseQueryCondition(expr string) (SearchCondition, error) {
+	expr = strings.TrimSpace(expr)
+	end := strings.IndexFunc(expr, func(r rune) bool {
+		return !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+	if end <= 0 {
+		return SearchCondition{}, invalidFilterError(expr)
+	}
+	condition := SearchCondition{Field: expr[:end]}
+	rest := expr[end:]
+
+	var value string
+	for _, op := range queryOperators {
+		if strings.HasPrefix(rest, op.token) {
+			condition.Operation = op.operation
+			value = rest[len(op.token):]
+			break
+		}
+	}
+	if condition.Operation == "" {
+		// запись =<операция>=<значение>
+		closing := strings.Index(rest[min(1, len(rest)):], "=")
+		if !strings.HasPrefix(rest, "=") || closing <= 0 {
+			return SearchCondition{}, invalidFilterError(expr)
+		}
+		name := rest[1 : closing+1]
+		condition.Operation = Operator(name)
+		if alias, ok := queryOperatorAliases[name]; ok {
+			condition.Operation = alias
+		}
+		value = rest[closing+2:]
+	}
+
+	switch {
+	case condition.Operation == OpIsNull || condition.Operation == OpNotNull:
+	case strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")"):
+		items := splitQuoted(value[1:len(value)-1], ',')
+		values := make([]any, 0, len(items))
+		for _, item := range items {
+			values = append(values, unquote(strings.TrimSpace(item)))
+		}
+		condition.Value = values
+	default:
+		condition.Value = unquote(value)
+	}
+	return condition, nil
+}
+
+// splitQuoted делит строку по разделителю, не учитывая разделители внутри двойных кавычек
+func splitQuoted(s string, sep rune) []string {
+	var parts []string
+	quoted := false
+	start := 0
+	for i, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == sep && !quoted:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && strings.HasPrefix(s, `"`) && strings.HasSuffix(s, `"`) {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+func invalidFilterError(expr string) error {
+	return NewValidationError(nil, QueryFilterInvalidCode, "Некорректное условие фильтра '%s'", expr)
+}
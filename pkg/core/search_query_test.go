@@ -0,0 +1,172 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSearchQueryFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		conditions []SearchCondition
+	}{
+		{
+			name:  "short operators",
+			query: "filter=price>=100;category_id==3;label!=x;id<5;id>1;price<=9",
+			conditions: []SearchCondition{
+				{Field: "price", Operation: OpGreaterEq, Value: "100"},
+				{Field: "category_id", Operation: OpEqual, Value: "3"},
+				{Field: "label", Operation: OpNotEqual, Value: "x"},
+				{Field: "id", Operation: OpLess, Value: "5"},
+				{Field: "id", Operation: OpGreater, Value: "1"},
+				{Field: "price", Operation: OpLessEq, Value: "9"},
+			},
+		},
+		{
+			name:  "named operator with list",
+			query: "filter=status=in=(Available,Unavailable)",
+			conditions: []SearchCondition{
+				{Field: "status", Operation: OpIn, Value: []any{"Available", "Unavailable"}},
+			},
+		},
+		{
+			name:  "operator aliases",
+			query: "filter=id=out=(1,2);label=sw=ab;label=ew=yz",
+			conditions: []SearchCondition{
+				{Field: "id", Operation: OpNotIn, Value: []any{"1", "2"}},
+				{Field: "label", Operation: OpStartsWith, Value: "ab"},
+				{Field: "label", Operation: OpEndsWith, Value: "yz"},
+			},
+		},
+		{
+			name:  "between",
+			query: "filter=price=between=(10, 20)",
+			conditions: []SearchCondition{
+				{Field: "price", Operation: OpBetween, Value: []any{"10", "20"}},
+			},
+		},
+		{
+			name:  "null checks without value",
+			query: "filter=note=is_null=;note=not_null=",
+			conditions: []SearchCondition{
+				{Field: "note", Operation: OpIsNull},
+				{Field: "note", Operation: OpNotNull},
+			},
+		},
+		{
+			name:  "quoted values keep separators",
+			query: `filter=label=="a;b,c";status=in=("x,y",z)`,
+			conditions: []SearchCondition{
+				{Field: "label", Operation: OpEqual, Value: "a;b,c"},
+				{Field: "status", Operation: OpIn, Value: []any{"x,y", "z"}},
+			},
+		},
+		{
+			name:  "url encoded",
+			query: "filter=label%3D%3Dhello%20world%3Bid%3E%3D2",
+			conditions: []SearchCondition{
+				{Field: "label", Operation: OpEqual, Value: "hello world"},
+				{Field: "id", Operation: OpGreaterEq, Value: "2"},
+			},
+		},
+		{
+			name:  "empty expressions skipped",
+			query: "filter=;id==1;",
+			conditions: []SearchCondition{
+				{Field: "id", Operation: OpEqual, Value: "1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			criteria, err := ParseSearchQuery(tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.conditions, criteria.SearchConditions)
+		})
+	}
+}
+
+func TestParseSearchQueryPaging(t *testing.T) {
+	criteria, err := ParseSearchQuery("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultPageLimit, criteria.Limit)
+	assert.Nil(t, criteria.Offset)
+	assert.Empty(t, criteria.Sort)
+
+	criteria, err = ParseSearchQuery("limit=50&offset=100&sort=-price,+label,id")
+	require.NoError(t, err)
+	assert.Equal(t, 50, criteria.Limit)
+	require.NotNil(t, criteria.Offset)
+	assert.Equal(t, 100, *criteria.Offset)
+	assert.Equal(t, []SortOrder{
+		{Field: "price", Direction: SortDesc},
+		{Field: "label", Direction: SortAsc},
+		{Field: "id", Direction: SortAsc},
+	}, criteria.Sort)
+
+	criteria, err = ParseSearchQuery("after=abc")
+	require.NoError(t, err)
+	require.NotNil(t, criteria.After)
+	assert.Equal(t, "abc", *criteria.After)
+	assert.Nil(t, criteria.Before)
+
+	criteria, err = ParseSearchQuery("before=def")
+	require.NoError(t, err)
+	require.NotNil(t, criteria.Before)
+	assert.Equal(t, "def", *criteria.Before)
+}
+
+func TestParseSearchQueryRejectsInvalidQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		code  string
+	}{
+		{name: "limit not a number", query: "limit=ten", code: QueryLimitInvalidCode},
+		{name: "zero limit", query: "limit=0", code: QueryLimitInvalidCode},
+		{name: "limit above max", query: "limit=101", code: QueryLimitInvalidCode},
+		{name: "negative offset", query: "offset=-1", code: QueryOffsetInvalidCode},
+		{name: "after and before", query: "after=abc&before=def", code: CursorInvalidCode},
+		{name: "empty sort field", query: "sort=price,-", code: QuerySortEmptyFieldCode},
+		{name: "broken escape", query: "filter=%zz", code: QueryInvalidCode},
+		{name: "no field", query: "filter===1", code: QueryFilterInvalidCode},
+		{name: "no operator", query: "filter=price", code: QueryFilterInvalidCode},
+		{name: "unclosed named operator", query: "filter=price=in", code: QueryFilterInvalidCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSearchQuery(tt.query)
+			assertValidationCode(t, err, tt.code)
+		})
+	}
+}
+
+func TestParseSearchQueryBuildsQuery(t *testing.T) {
+	criteria, err := ParseSearchQuery("filter=label=icontains=a_;id=in=(1,2)&sort=-price&limit=5")
+	require.NoError(t, err)
+
+	sql, vars, err := searchSQL(t, criteria)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `SEARCHTEST` WHERE LOWER(LABEL) like LOWER(?) escape '!' AND ID in (?,?) ORDER BY PRICE DESC,ID ASC LIMIT ?", sql)
+	assert.Equal(t, []any{"%a!_%", uint64(1), uint64(2), 5}, vars)
+}
+
+func TestSplitQuoted(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{input: "a;b", want: []string{"a", "b"}},
+		{input: `a;"b;c";d`, want: []string{"a", `"b;c"`, "d"}},
+		{input: "", want: []string{""}},
+		{input: "a;", want: []string{"a", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.want, splitQuoted(tt.input, ';'))
+		})
+	}
+}
@@ -14,8 +14,13 @@ type BaseService[T BaseEntity] interface {
 	GetRepo() BaseRepository[T]
 
 	GetByID(ctx context.Context, id uint) (T, error)
+	GetByIDs(ctx context.Context, ids []uint) ([]T, error)
 	GetAll(ctx context.Context) ([]T, error)
 	GetWithSearchCriteria(ctx context.Context, criteria SearchCriteria) ([]T, error)
+	GetPageWithSearchCriteria(ctx context.Context, criteria SearchCriteria) (Page[T], error)
+
+	Restore(ctx context.Context, id uint) (T, error)
+	Purge(ctx context.Context, id uint) error
 }
 
 // BaseServiceImpl базовая реализация сервиса
@@ -49,6 +54,16 @@ func (s *BaseServiceImpl[T]) GetByID(ctx context.Context, id uint) (T, error) {
 	return entity, nil
 }
 
+// GetByIDs получает сущности по списку ID одним запросом
+func (s *BaseServiceImpl[T]) GetByIDs(ctx context.Context, ids []uint) ([]T, error) {
+	var entity T
+	entities, err := s.repo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, NewTechnicalError(err, entity.TableName()+serviceCodeSuffix, "Ошибка при получении списка сущностей "+entity.TableName()+" по ID")
+	}
+	return entities, nil
+}
+
 // GetAll получает все сущности
 func (s *BaseServiceImpl[T]) GetAll(ctx context.Context) ([]T, error) {
 	var entity T
@@ -64,7 +79,69 @@ func (s *BaseServiceImpl[T]) GetWithSearchCriteria(ctx context.Context, criteria
 	var entity T
 	entities, err := s.repo.FindWithSearchCriteria(ctx, criteria)
 	if err != nil {
-		return nil, NewTechnicalError(err, entity.TableName()+serviceCodeSuffix, "Ошибка при получении по заданным параметрам сущностей "+entity.LocalTableName())
+		return nil, searchCriteriaError(err, entity)
 	}
 	return entities, nil
 }
+
+// GetPageWithSearchCriteria ищет страницу сущностей по критериям вместе с общим количеством записей
+func (s *BaseServiceImpl[T]) GetPageWithSearchCriteria(ctx context.Context, criteria SearchCriteria) (Page[T], error) {
+	var entity T
+
+	// запрашиваем на одну запись больше, чтобы узнать, есть ли следующая страница
+	probe := criteria
+	probe.Limit = criteria.Limit + 1
+	entities, err := s.repo.FindWithSearchCriteria(ctx, probe)
+	if err != nil {
+		return Page[T]{}, searchCriteriaError(err, entity)
+	}
+
+	total, err := s.repo.Count(ctx, criteria)
+	if err != nil {
+		return Page[T]{}, searchCriteriaError(err, entity)
+	}
+
+	page, err := NewPage(entities, total, criteria)
+	if err != nil {
+		return Page[T]{}, searchCriteriaError(err, entity)
+	}
+	return page, nil
+}
+
+// Restore восстанавливает мягко удаленную сущность по ID
+func (s *BaseServiceImpl[T]) Restore(ctx context.Context, id uint) (T, error) {
+	entity, err := s.GetByID(OnlyDeleted(ctx), id)
+	if err != nil {
+		return entity, err
+	}
+	restored, err := s.repo.Restore(ctx, entity)
+	if err != nil {
+		var logicalErr *LogicalError
+		if errors.As(err, &logicalErr) {
+			return restored, err
+		}
+		return restored, NewTechnicalError(err, entity.TableName()+serviceCodeSuffix, "Ошибка при восстановлении сущности "+entity.LocalTableName())
+	}
+	return restored, nil
+}
+
+// Purge безвозвратно удаляет сущность по ID, в том числе мягко удаленную
+func (s *BaseServiceImpl[T]) Purge(ctx context.Context, id uint) error {
+	entity, err := s.GetByID(WithDeleted(ctx), id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Purge(ctx, entity); err != nil {
+		return NewTechnicalError(err, entity.TableName()+serviceCodeSuffix, "Ошибка при удалении сущности "+entity.LocalTableName())
+	}
+	return nil
+}
+
+// searchCriteriaError пропускает ошибки валидации критериев, остальные считает техническими
+func searchCriteriaError[T BaseEntity](err error, entity T) error {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return err
+	}
+	return NewTechnicalError(err, entity.TableName()+serviceCodeSuffix, "Ошибка при получении по заданным параметрам сущностей "+entity.LocalTableName())
+}
@@ -0,0 +1,140 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pageTestRepository репозиторий в памяти, запоминающий критерии выборки и подсчета
+type pageTestRepository struct {
+	*memoryRepository
+
+	found    []SearchCriteria
+	counted  []SearchCriteria
+	countErr error
+}
+
+func (r *pageTestRepository) FindWithSearchCriteria(ctx context.Context, criteria SearchCriteria) ([]testEntity, error) {
+	r.found = append(r.found, criteria)
+	entities, err := r.memoryRepository.FindWithSearchCriteria(ctx, criteria)
+	if len(entities) > criteria.Limit {
+		entities = entities[:criteria.Limit]
+	}
+	return entities, err
+}
+
+func (r *pageTestRepository) Count(ctx context.Context, criteria SearchCriteria) (int64, error) {
+	r.counted = append(r.counted, criteria)
+	if r.countErr != nil {
+		return 0, r.countErr
+	}
+	return r.memoryRepository.Count(ctx, criteria)
+}
+
+func TestGetPageWithSearchCriteria(t *testing.T) {
+	repo := &pageTestRepository{memoryRepository: newMemoryRepository(
+		newTestEntity(1, "first"), newTestEntity(2, "second"), newTestEntity(3, "third"),
+	)}
+	service := NewBaseServiceImpl[testEntity](repo)
+	offset := 0
+
+	page, err := service.GetPageWithSearchCriteria(context.Background(), SearchCriteria{Limit: 2, Offset: &offset})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, int64(3), page.Total)
+	assert.Equal(t, 2, page.Limit)
+	assert.Zero(t, page.Offset)
+	assert.True(t, page.HasMore)
+
+	// выборка запрашивает одну лишнюю запись, подсчет - с исходными критериями
+	require.Len(t, repo.found, 1)
+	assert.Equal(t, 3, repo.found[0].Limit)
+	require.Len(t, repo.counted, 1)
+	assert.Equal(t, 2, repo.counted[0].Limit)
+}
+
+func TestGetPageWithSearchCriteriaLastPage(t *testing.T) {
+	repo := &pageTestRepository{memoryRepository: newMemoryRepository(newTestEntity(1, "first"))}
+	service := NewBaseServiceImpl[testEntity](repo)
+
+	page, err := service.GetPageWithSearchCriteria(context.Background(), SearchCriteria{Limit: 2})
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, int64(1), page.Total)
+	assert.False(t, page.HasMore)
+	assert.Nil(t, page.NextCursor)
+}
+
+func TestGetPageWithSearchCriteriaEmpty(t *testing.T) {
+	repo := &pageTestRepository{memoryRepository: newMemoryRepository()}
+	service := NewBaseServiceImpl[testEntity](repo)
+
+	page, err := service.GetPageWithSearchCriteria(context.Background(), SearchCriteria{Limit: 2})
+	require.NoError(t, err)
+	// пустая страница отдается клиенту как [], а не null
+	assert.NotNil(t, page.Items)
+	assert.Empty(t, page.Items)
+	assert.Zero(t, page.Total)
+	assert.False(t, page.HasMore)
+}
+
+func TestGetPageWithSearchCriteriaCountError(t *testing.T) {
+	repo := &pageTestRepository{memoryRepository: newMemoryRepository(newTestEntity(1, "first"))}
+	repo.countErr = errors.New("connection refused")
+	service := NewBaseServiceImpl[testEntity](repo)
+
+	_, err := service.GetPageWithSearchCriteria(context.Background(), SearchCriteria{Limit: 2})
+	var technicalErr *TechnicalError
+	require.ErrorAs(t, err, &technicalErr)
+	assert.ErrorIs(t, err, repo.countErr)
+}
+
+func TestMapPageKeepsMetadata(t *testing.T) {
+	next, prev := "next", "prev"
+	page := Page[testEntity]{
+		Items:      []testEntity{newTestEntity(1, "first")},
+		Total:      10,
+		Limit:      1,
+		Offset:     3,
+		HasMore:    true,
+		NextCursor: &next,
+		PrevCursor: &prev,
+	}
+
+	mapped := MapPage(page, []string{"first"})
+	assert.Equal(t, Page[string]{
+		Items:      []string{"first"},
+		Total:      10,
+		Limit:      1,
+		Offset:     3,
+		HasMore:    true,
+		NextCursor: &next,
+		PrevCursor: &prev,
+	}, mapped)
+}
+
+func TestBuildCountQueryIgnoresPaging(t *testing.T) {
+	offset := 20
+	criteria := SearchCriteria{
+		Limit:  10,
+		Offset: &offset,
+		Sort:   []SortOrder{{Field: "price", Direction: SortDesc}},
+		Filter: &ConditionGroup{Field: "label", Operation: OpEqual, Value: "a"},
+	}
+
+	query, err := BuildCountQuery(newDryRunDB(t).Model(&searchTestEntity{}), searchTestEntity{}.SearchFields(), criteria)
+	require.NoError(t, err)
+	var count int64
+	stmt := query.Count(&count).Statement
+
+	sql := stmt.SQL.String()
+	assert.Contains(t, sql, "LABEL = ?")
+	assert.NotContains(t, sql, "LIMIT")
+	assert.NotContains(t, sql, "OFFSET")
+	assert.NotContains(t, sql, "ORDER BY")
+	assert.Equal(t, []any{"a"}, stmt.Vars)
+}
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type DeletedScope string
+
+const (
+	deletedCtxKeyCode DeletedScope = "deletedCtxKey"
+
+	// DeletedExclude мягко удаленные записи не возвращаются (по умолчанию)
+	DeletedExclude DeletedScope = "exclude"
+	// DeletedInclude возвращаются все записи, включая мягко удаленные
+	DeletedInclude DeletedScope = "include"
+	// DeletedOnly возвращаются только мягко удаленные записи
+	DeletedOnly DeletedScope = "only"
+
+	deletedAtColumn = "DELETEDAT"
+)
+
+// SoftDeletable сущность с мягким удалением. Репозиторий скрывает удаленные записи
+// из выборок, а Delete проставляет дату удаления вместо удаления строки
+type SoftDeletable interface {
+	IsDeleted() bool
+	MarkDeleted(at time.Time)
+	ClearDeleted()
+}
+
+// SoftDelete встраивается в сущность, чтобы включить мягкое удаление
+type SoftDelete struct {
+	DeletedAt sql.NullTime `gorm:"column:DELETEDAT"`
+}
+
+func (s SoftDelete) IsDeleted() bool {
+	return s.DeletedAt.Valid
+}
+
+func (s *SoftDelete) MarkDeleted(at time.Time) {
+	s.DeletedAt = sql.NullTime{Time: at, Valid: true}
+}
+
+func (s *SoftDelete) ClearDeleted() {
+	s.DeletedAt = sql.NullTime{}
+}
+
+// WithDeleted включает мягко удаленные записи в выборки репозиториев в рамках контекста
+func WithDeleted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, deletedCtxKeyCode, DeletedInclude)
+}
+
+// OnlyDeleted ограничивает выборки репозиториев мягко удаленными записями в рамках контекста
+func OnlyDeleted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, deletedCtxKeyCode, DeletedOnly)
+}
+
+// WithDeletedScope применяет к контексту режим выборки из параметра запроса (exclude, include, only)
+func WithDeletedScope(ctx context.Context, scope string) (context.Context, error) {
+	switch DeletedScope(scope) {
+	case "", DeletedExclude:
+		return ctx, nil
+	case DeletedInclude:
+		return WithDeleted(ctx), nil
+	case DeletedOnly:
+		return OnlyDeleted(ctx), nil
+	default:
+		return ctx, NewValidationError(nil, DeletedScopeInvalidCode, "Параметр deleted должен быть одним из: %s, %s, %s", DeletedExclude, DeletedInclude, DeletedOnly)
+	}
+}
+
+func deletedScopeFromCtx(ctx context.Context) DeletedScope {
+	if scope, ok := ctx.Value(deletedCtxKeyCode).(DeletedScope); ok {
+		return scope
+	}
+	return DeletedExclude
+}
+
+// isSoftDeletable проверяет, поддерживает ли тип сущности мягкое удаление
+func isSoftDeletable[T BaseEntity]() bool {
+	var entity T
+	_, ok := any(&entity).(SoftDeletable)
+	return ok
+}
+
+// applyDeletedScope добавляет условие по дате удаления согласно режиму из контекста
+func applyDeletedScope(ctx context.Context, db *gorm.DB) *gorm.DB {
+	switch deletedScopeFromCtx(ctx) {
+	case DeletedInclude:
+		return db
+	case DeletedOnly:
+		return db.Where(deletedAtColumn + " IS NOT NULL")
+	default:
+		return db.Where(deletedAtColumn + " IS NULL")
+	}
+}
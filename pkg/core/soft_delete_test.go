@@ -0,0 +1,174 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type softDeleteTestEntity struct {
+	Base
+	SoftDelete
+
+	Name string `gorm:"column:NAME"`
+}
+
+func (softDeleteTestEntity) TableName() string {
+	return "SOFTDELETETEST"
+}
+
+func (softDeleteTestEntity) LocalTableName() string {
+	return "Тестовая запись с мягким удалением"
+}
+
+func (softDeleteTestEntity) SearchFields() *SearchFields {
+	return NewSearchFields(SearchField{Name: "name", Column: "NAME", Type: FieldString})
+}
+
+func newSoftDeleteTestEntity(id uint) softDeleteTestEntity {
+	entity := softDeleteTestEntity{Name: "first"}
+	entity.ID = id
+	entity.Version = 1
+	return entity
+}
+
+// lastQuery последний запрос журнала без номера соединения
+func lastQuery(t *testing.T, recorder *sqlRecorder) string {
+	t.Helper()
+	entries := recorder.entries()
+	require.NotEmpty(t, entries)
+	_, query, _ := strings.Cut(entries[len(entries)-1], " ")
+	return query
+}
+
+func TestWithDeletedScope(t *testing.T) {
+	tests := []struct {
+		scope string
+		want  DeletedScope
+	}{
+		{scope: "", want: DeletedExclude},
+		{scope: "exclude", want: DeletedExclude},
+		{scope: "include", want: DeletedInclude},
+		{scope: "only", want: DeletedOnly},
+	}
+	for _, tt := range tests {
+		t.Run(tt.scope, func(t *testing.T) {
+			ctx, err := WithDeletedScope(context.Background(), tt.scope)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, deletedScopeFromCtx(ctx))
+		})
+	}
+
+	_, err := WithDeletedScope(context.Background(), "all")
+	assertValidationCode(t, err, DeletedScopeInvalidCode)
+}
+
+func TestRepositoryAppliesDeletedScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		where   string
+		without []string
+	}{
+		{name: "exclude", ctx: context.Background(), where: "DELETEDAT IS NULL", without: []string{"IS NOT NULL"}},
+		{name: "include", ctx: WithDeleted(context.Background()), without: []string{"DELETEDAT"}},
+		{name: "only", ctx: OnlyDeleted(context.Background()), where: "DELETEDAT IS NOT NULL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewBaseRepositoryImpl[softDeleteTestEntity](newDryRunDB(t))
+
+			var entities []softDeleteTestEntity
+			query := repo.GetDB(tt.ctx).Find(&entities).Statement.SQL.String()
+			if tt.where != "" {
+				assert.Contains(t, query, tt.where)
+			}
+			for _, fragment := range tt.without {
+				assert.NotContains(t, query, fragment)
+			}
+		})
+	}
+
+	// сущности без мягкого удаления условие по дате удаления не получают
+	repo := NewBaseRepositoryImpl[testEntity](newDryRunDB(t))
+	var entities []testEntity
+	assert.NotContains(t, repo.GetDB(context.Background()).Find(&entities).Statement.SQL.String(), "DELETEDAT")
+}
+
+func TestRepositorySoftDeleteMarksDeleted(t *testing.T) {
+	db, recorder := newRecordingDB(t)
+	repo := NewBaseRepositoryImpl[softDeleteTestEntity](db)
+
+	require.NoError(t, repo.Delete(context.Background(), newSoftDeleteTestEntity(1)))
+
+	query := lastQuery(t, recorder)
+	assert.Contains(t, query, "UPDATE `SOFTDELETETEST`")
+	assert.Contains(t, query, "VERSION = ?")
+	args := recorder.execArgs("UPDATE")
+	require.Len(t, args, 1)
+	assert.Contains(t, args[0], int64(2), "SET VERSION = ?")
+	assert.Empty(t, recorder.execArgs("DELETE"))
+}
+
+func TestRepositoryRestore(t *testing.T) {
+	db, recorder := newRecordingDB(t)
+	repo := NewBaseRepositoryImpl[softDeleteTestEntity](db)
+	entity := newSoftDeleteTestEntity(1)
+	entity.MarkDeleted(time.Now())
+
+	restored, err := repo.Restore(context.Background(), entity)
+	require.NoError(t, err)
+	assert.False(t, restored.IsDeleted())
+	assert.Equal(t, uint(2), restored.Version)
+
+	// восстанавливается только удаленная запись той версии, которую видел клиент
+	query := lastQuery(t, recorder)
+	assert.Contains(t, query, "DELETEDAT IS NOT NULL")
+	assert.Contains(t, query, "VERSION = ?")
+	args := recorder.execArgs("UPDATE")
+	require.Len(t, args, 1)
+	assert.Contains(t, args[0], nil, "SET DELETEDAT = NULL")
+}
+
+func TestRepositoryRestoreConflict(t *testing.T) {
+	db, recorder := newRecordingDB(t)
+	repo := NewBaseRepositoryImpl[softDeleteTestEntity](db)
+	recorder.rowsAffected = 0
+	entity := newSoftDeleteTestEntity(1)
+	entity.MarkDeleted(time.Now())
+
+	restored, err := repo.Restore(context.Background(), entity)
+	var conflictErr *ConflictError
+	require.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, RecordVersionConflictCode, conflictErr.Code)
+	assert.Equal(t, uint(1), restored.Version)
+}
+
+func TestRepositoryRestoreUnsupported(t *testing.T) {
+	db, recorder := newRecordingDB(t)
+	repo := NewBaseRepositoryImpl[testEntity](db)
+
+	_, err := repo.Restore(context.Background(), newTestEntity(1, "first"))
+	var logicalErr *LogicalError
+	require.ErrorAs(t, err, &logicalErr)
+	assert.Equal(t, RecordRestoreUnsupportedCode, logicalErr.Code)
+	assert.Empty(t, recorder.entries())
+}
+
+func TestRepositoryPurgeDeletesRow(t *testing.T) {
+	db, recorder := newRecordingDB(t)
+	repo := NewBaseRepositoryImpl[softDeleteTestEntity](db)
+	entity := newSoftDeleteTestEntity(1)
+	entity.MarkDeleted(time.Now())
+
+	require.NoError(t, repo.Purge(context.Background(), entity))
+
+	// строка удаляется независимо от даты удаления
+	query := lastQuery(t, recorder)
+	assert.Contains(t, query, "DELETE FROM `SOFTDELETETEST`")
+	assert.NotContains(t, query, "DELETEDAT")
+}
@@ -0,0 +1,115 @@
+package core
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// maxSortFields ограничивает количество полей сортировки в запросе
+	maxSortFields = 5
+)
+
+// sortKey поле сортировки с направлением и положением NULL
+type sortKey struct {
+	field      SearchField
+	desc       bool
+	nullsFirst bool
+}
+
+// reversed возвращает ключ с обратным порядком, включая положение NULL
+func (k sortKey) reversed() sortKey {
+	return sortKey{field: k.field, desc: !k.desc, nullsFirst: !k.nullsFirst}
+}
+
+// parseSortKeys собирает ключи сортировки из Sort (или устаревшего OrderBy)
+// и дополняет их ID, чтобы порядок был однозначным
+func parseSortKeys(fields *SearchFields, criteria SearchCriteria) ([]sortKey, error) {
+	orders := criteria.Sort
+	if len(orders) == 0 && criteria.OrderBy != nil && strings.TrimSpace(*criteria.OrderBy) != "" {
+		order, err := parseOrderBy(*criteria.OrderBy)
+		if err != nil {
+			return nil, err
+		}
+		orders = []SortOrder{order}
+	}
+	if len(orders) > maxSortFields {
+		return nil, NewValidationError(nil, SortTooManyFieldsCode, "Допускается не более %d полей сортировки", maxSortFields)
+	}
+
+	keys := make([]sortKey, 0, len(orders)+1)
+	seen := make(map[string]struct{}, len(orders))
+	for _, order := range orders {
+		field, err := fields.LookupSortable(order.Field)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[field.Column]; ok {
+			return nil, NewValidationError(nil, SortDuplicateFieldCode, "Поле '%s' указано в сортировке несколько раз", order.Field)
+		}
+		seen[field.Column] = struct{}{}
+
+		key := sortKey{field: field}
+		switch strings.ToLower(order.Direction) {
+		case "", SortAsc:
+		case SortDesc:
+			key.desc = true
+		default:
+			return nil, NewValidationError(nil, SortDirectionInvalidCode, "Направление сортировки должно быть asc или desc")
+		}
+		// по умолчанию MySQL ставит NULL первыми при asc и последними при desc
+		switch strings.ToLower(order.Nulls) {
+		case "":
+			key.nullsFirst = !key.desc
+		case NullsFirst:
+			key.nullsFirst = true
+		case NullsLast:
+		default:
+			return nil, NewValidationError(nil, SortNullsInvalidCode, "Положение NULL должно быть first или last")
+		}
+		keys = append(keys, key)
+
+		if field.Column == idColumn {
+			// ID уникален, последующие ключи не влияют на порядок
+			return keys, nil
+		}
+	}
+
+	return append(keys, sortKey{field: baseSearchFields()[0]}), nil
+}
+
+// parseOrderBy разбирает OrderBy вида "<поле> [asc|desc]"
+func parseOrderBy(orderBy string) (SortOrder, error) {
+	parts := strings.Fields(orderBy)
+	if len(parts) > 2 {
+		return SortOrder{}, NewValidationError(nil, SortFormatInvalidCode, "Сортировка задается в формате '<поле> [asc|desc]'")
+	}
+	order := SortOrder{Field: parts[0]}
+	if len(parts) == 2 {
+		order.Direction = parts[1]
+	}
+	return order, nil
+}
+
+func applySortKeys(keys []sortKey) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, key := range keys {
+			column := key.field.Column
+			// MySQL не поддерживает NULLS FIRST/LAST, положение задается отдельным выражением
+			if key.field.Nullable && key.nullsFirst == key.desc {
+				if key.nullsFirst {
+					db = db.Order(column + " IS NULL DESC")
+				} else {
+					db = db.Order(column + " IS NULL ASC")
+				}
+			}
+			if key.desc {
+				db = db.Order(column + " DESC")
+			} else {
+				db = db.Order(column + " ASC")
+			}
+		}
+		return db
+	}
+}
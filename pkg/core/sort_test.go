@@ -0,0 +1,132 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildQuerySort(t *testing.T) {
+	orderBy := func(value string) *string {
+		return &value
+	}
+
+	tests := []struct {
+		name     string
+		criteria SearchCriteria
+		order    string
+	}{
+		{
+			name:  "default by id",
+			order: "ID ASC",
+		},
+		{
+			name:     "several fields",
+			criteria: SearchCriteria{Sort: []SortOrder{{Field: "price", Direction: SortDesc}, {Field: "label"}}},
+			order:    "PRICE DESC,LABEL ASC,ID ASC",
+		},
+		{
+			name:     "direction is case insensitive",
+			criteria: SearchCriteria{Sort: []SortOrder{{Field: "label", Direction: "DESC"}}},
+			order:    "LABEL DESC,ID ASC",
+		},
+		{
+			name:     "id ends sort",
+			criteria: SearchCriteria{Sort: []SortOrder{{Field: "id", Direction: SortDesc}, {Field: "label"}}},
+			order:    "ID DESC",
+		},
+		{
+			name:     "nullable asc keeps mysql nulls first",
+			criteria: SearchCriteria{Sort: []SortOrder{{Field: "note"}}},
+			order:    "NOTE ASC,ID ASC",
+		},
+		{
+			name:     "nullable asc nulls last",
+			criteria: SearchCriteria{Sort: []SortOrder{{Field: "note", Nulls: NullsLast}}},
+			order:    "NOTE IS NULL ASC,NOTE ASC,ID ASC",
+		},
+		{
+			name:     "nullable desc nulls first",
+			criteria: SearchCriteria{Sort: []SortOrder{{Field: "note", Direction: SortDesc, Nulls: NullsFirst}}},
+			order:    "NOTE IS NULL DESC,NOTE DESC,ID ASC",
+		},
+		{
+			name:     "legacy order by",
+			criteria: SearchCriteria{OrderBy: orderBy("price desc")},
+			order:    "PRICE DESC,ID ASC",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.criteria.Limit = 10
+			sql, _, err := searchSQL(t, tt.criteria)
+			require.NoError(t, err)
+			assert.Equal(t, "SELECT * FROM `SEARCHTEST` ORDER BY "+tt.order+" LIMIT ?", sql)
+		})
+	}
+}
+
+func TestParseSortKeysRejectsInvalidSort(t *testing.T) {
+	orderBy := "price desc nulls"
+	tooMany := make([]SortOrder, 0, maxSortFields+1)
+	for range maxSortFields + 1 {
+		tooMany = append(tooMany, SortOrder{Field: "label"})
+	}
+
+	tests := []struct {
+		name     string
+		criteria SearchCriteria
+		code     string
+	}{
+		{
+			name:     "unknown field",
+			criteria: SearchCriteria{Sort: []SortOrder{{Field: "secret"}}},
+			code:     SearchFieldUnknownCode,
+		},
+		{
+			name:     "not sortable",
+			criteria: SearchCriteria{Sort: []SortOrder{{Field: "status"}}},
+			code:     SearchSortFieldUnsupportedCode,
+		},
+		{
+			name:     "duplicate field",
+			criteria: SearchCriteria{Sort: []SortOrder{{Field: "label"}, {Field: "label", Direction: SortDesc}}},
+			code:     SortDuplicateFieldCode,
+		},
+		{
+			name:     "invalid direction",
+			criteria: SearchCriteria{Sort: []SortOrder{{Field: "label", Direction: "up"}}},
+			code:     SortDirectionInvalidCode,
+		},
+		{
+			name:     "invalid nulls",
+			criteria: SearchCriteria{Sort: []SortOrder{{Field: "note", Nulls: "middle"}}},
+			code:     SortNullsInvalidCode,
+		},
+		{
+			name:     "too many fields",
+			criteria: SearchCriteria{Sort: tooMany},
+			code:     SortTooManyFieldsCode,
+		},
+		{
+			name:     "invalid legacy order by",
+			criteria: SearchCriteria{OrderBy: &orderBy},
+			code:     SortFormatInvalidCode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseSortKeys(searchTestEntity{}.SearchFields(), tt.criteria)
+			assertValidationCode(t, err, tt.code)
+		})
+	}
+}
+
+func TestSortKeyReversed(t *testing.T) {
+	key := sortKey{field: SearchField{Column: "NOTE", Nullable: true}, nullsFirst: true}
+	reversed := key.reversed()
+	assert.True(t, reversed.desc)
+	assert.False(t, reversed.nullsFirst)
+	assert.Equal(t, key, reversed.reversed())
+}
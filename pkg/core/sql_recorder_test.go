@@ -0,0 +1,166 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	gormmysql "gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// sqlRecorder драйвер database/sql, который не выполняет запросы, а записывает их в журнал
+// вместе с номером соединения. failures задает ошибки, которые вернут выполнения запросов
+// с указанным префиксом, по одной на каждое выполнение
+type sqlRecorder struct {
+	mu    sync.Mutex
+	log   []string
+	conns int
+	// args аргументы выполненных запросов по тексту запроса
+	args         map[string][][]any
+	failures     map[string][]error
+	rowsAffected int64
+}
+
+func newSQLRecorder() *sqlRecorder {
+	return &sqlRecorder{args: make(map[string][][]any), failures: make(map[string][]error), rowsAffected: 1}
+}
+
+func (r *sqlRecorder) add(entry string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.log = append(r.log, entry)
+}
+
+func (r *sqlRecorder) entries() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.log)
+}
+
+// fail добавляет ошибку для следующего выполнения запроса с префиксом prefix
+func (r *sqlRecorder) fail(prefix string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failures[prefix] = append(r.failures[prefix], err)
+}
+
+func (r *sqlRecorder) record(conn int, query string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.log = append(r.log, fmt.Sprintf("c%d %s", conn, query))
+	for prefix, errs := range r.failures {
+		if strings.HasPrefix(query, prefix) && len(errs) > 0 {
+			r.failures[prefix] = errs[1:]
+			return errs[0]
+		}
+	}
+	return nil
+}
+
+// execArgs аргументы всех выполнений запросов с префиксом prefix
+func (r *sqlRecorder) execArgs(prefix string) [][]any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var args [][]any
+	for query, values := range r.args {
+		if strings.HasPrefix(query, prefix) {
+			args = append(args, values...)
+		}
+	}
+	return args
+}
+
+func (r *sqlRecorder) Connect(context.Context) (driver.Conn, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.conns++
+	return &recordingConn{recorder: r, id: r.conns}, nil
+}
+
+func (r *sqlRecorder) Driver() driver.Driver {
+	return recordingDriver{}
+}
+
+type recordingDriver struct{}
+
+func (recordingDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("use connector")
+}
+
+type recordingConn struct {
+	recorder *sqlRecorder
+	id       int
+}
+
+func (c *recordingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *recordingConn) Close() error {
+	return nil
+}
+
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *recordingConn) BeginTx(_ context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	query := fmt.Sprintf("BEGIN %v", sql.IsolationLevel(opts.Isolation))
+	if opts.ReadOnly {
+		query += " READ ONLY"
+	}
+	if err := c.recorder.record(c.id, query); err != nil {
+		return nil, err
+	}
+	return recordingTx{c}, nil
+}
+
+func (c *recordingConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	values := make([]any, 0, len(args))
+	for _, arg := range args {
+		values = append(values, arg.Value)
+	}
+	c.recorder.mu.Lock()
+	c.recorder.args[query] = append(c.recorder.args[query], values)
+	c.recorder.mu.Unlock()
+
+	if err := c.recorder.record(c.id, query); err != nil {
+		return nil, err
+	}
+	c.recorder.mu.Lock()
+	defer c.recorder.mu.Unlock()
+	return driver.RowsAffected(c.recorder.rowsAffected), nil
+}
+
+type recordingTx struct {
+	conn *recordingConn
+}
+
+func (tx recordingTx) Commit() error {
+	return tx.conn.recorder.record(tx.conn.id, "COMMIT")
+}
+
+func (tx recordingTx) Rollback() error {
+	return tx.conn.recorder.record(tx.conn.id, "ROLLBACK")
+}
+
+func newRecordingDB(t *testing.T) (*gorm.DB, *sqlRecorder) {
+	t.Helper()
+	recorder := newSQLRecorder()
+	sqlDB := sql.OpenDB(recorder)
+	t.Cleanup(func() { sqlDB.Close() })
+	db, err := gorm.Open(gormmysql.New(gormmysql.Config{
+		Conn:                      sqlDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	require.NoError(t, err)
+	return db, recorder
+}
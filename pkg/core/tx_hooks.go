@@ -0,0 +1,89 @@
+package core
+
+import (
+	"context"
+	"sync"
+)
+
+// TxHook действие, которое выполняется после завершения транзакции
+type TxHook func(ctx context.Context) error
+
+// txHooks действия, зарегистрированные в транзакции или точке сохранения
+type txHooks struct {
+	mu            sync.Mutex
+	afterCommit   []TxHook
+	afterRollback []TxHook
+}
+
+// AfterCommit регистрирует действие, которое выполнится после фиксации транзакции из контекста,
+// например отправка письма или сброс кэша. При откате транзакции действие не выполняется.
+// Вне транзакции действие выполняется сразу
+func AfterCommit(ctx context.Context, hook TxHook) {
+	state, ok := ctx.Value(txStateCtxKey).(*txState)
+	if !ok || state.hooks == nil {
+		runTxHooks(ctx, "after commit", []TxHook{hook})
+		return
+	}
+
+	state.hooks.mu.Lock()
+	defer state.hooks.mu.Unlock()
+	state.hooks.afterCommit = append(state.hooks.afterCommit, hook)
+}
+
+// AfterRollback регистрирует действие, которое выполнится после отката транзакции из контекста,
+// например удаление загруженного файла. Внутри точки сохранения действие выполняется и при откате
+// к ней. Вне транзакции действие не выполняется
+func AfterRollback(ctx context.Context, hook TxHook) {
+	state, ok := ctx.Value(txStateCtxKey).(*txState)
+	if !ok || state.hooks == nil {
+		return
+	}
+
+	state.hooks.mu.Lock()
+	defer state.hooks.mu.Unlock()
+	state.hooks.afterRollback = append(state.hooks.afterRollback, hook)
+}
+
+// merge переносит действия фиксированной точки сохранения в транзакцию, которая её содержит
+func (h *txHooks) merge(nested *txHooks) {
+	nested.mu.Lock()
+	afterCommit, afterRollback := nested.afterCommit, nested.afterRollback
+	nested.mu.Unlock()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.afterCommit = append(h.afterCommit, afterCommit...)
+	h.afterRollback = append(h.afterRollback, afterRollback...)
+}
+
+func (h *txHooks) runAfterCommit(ctx context.Context) {
+	h.mu.Lock()
+	hooks := h.afterCommit
+	h.mu.Unlock()
+	runTxHooks(ctx, "after commit", hooks)
+}
+
+func (h *txHooks) runAfterRollback(ctx context.Context) {
+	h.mu.Lock()
+	hooks := h.afterRollback
+	h.mu.Unlock()
+	runTxHooks(ctx, "after rollback", hooks)
+}
+
+// runTxHooks выполняет действия по порядку регистрации. Транзакция к этому моменту уже завершена,
+// поэтому ошибки и паники действий не возвращаются вызывающему, а логируются
+func runTxHooks(ctx context.Context, stage string, hooks []TxHook) {
+	ctx = context.WithoutCancel(ctx)
+	for _, hook := range hooks {
+		func() {
+			defer func() {
+				if p := recover(); p != nil {
+					LoggerFromContext(ctx).Error("Transaction hook panicked", "stage", stage, "panic", p)
+				}
+			}()
+			if err := hook(ctx); err != nil {
+				LoggerFromContext(ctx).Error("Transaction hook failed", "stage", stage, "err", err)
+			}
+		}()
+	}
+}
@@ -3,8 +3,13 @@ package core
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"log/slog"
+	"sync/atomic"
+	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
 )
 
@@ -12,6 +17,40 @@ type TxCtxKey string
 
 const (
 	TxCtxKeyCode TxCtxKey = "txCtxKey"
+
+	txStateCtxKey TxCtxKey = "txStateCtxKey"
+
+	txManagerCode = "TX_MANAGER"
+
+	// коды ошибок MySQL, после которых транзакция откачена и может быть выполнена повторно
+	mysqlLockWaitTimeout  = 1205
+	mysqlDeadlock         = 1213
+	sqlStateSerialization = "40001"
+)
+
+// TxPropagation определяет поведение транзакции, если в контексте уже есть открытая транзакция
+type TxPropagation string
+
+const (
+	// TxRequired присоединяется к существующей транзакции или открывает новую
+	TxRequired TxPropagation = "Required"
+	// TxRequiresNew всегда открывает новую транзакцию на отдельном соединении.
+	// Изменения фиксируются независимо от внешней транзакции
+	TxRequiresNew TxPropagation = "RequiresNew"
+	// TxNested внутри существующей транзакции создает точку сохранения (SAVEPOINT)
+	// и при ошибке откатывается только к ней, иначе открывает новую транзакцию
+	TxNested TxPropagation = "Nested"
+	// TxNever выполняется без транзакции, внутри существующей транзакции возвращает ошибку
+	TxNever TxPropagation = "Never"
+)
+
+// Уровни изоляции транзакции
+const (
+	IsolationDefault         = "Default"
+	IsolationReadUncommitted = "ReadUncommitted"
+	IsolationReadCommitted   = "ReadCommitted"
+	IsolationRepeatableRead  = "RepeatableRead"
+	IsolationSerializable    = "Serializable"
 )
 
 type TxManager interface {
@@ -27,16 +66,66 @@ type TxManager interface {
 type TxSettings interface {
 	GetTxCtxKey() TxCtxKey
 	GetIsolationLevel() string
+	GetPropagation() TxPropagation
+	IsReadOnly() bool
+}
+
+// txState параметры открытой транзакции, по которым проверяется присоединение к ней
+type txState struct {
+	isolationLevel string
+	readOnly       bool
+	// savepoints глубина вложенных точек сохранения
+	savepoints int
+	// hooks действия после завершения транзакции или точки сохранения
+	hooks *txHooks
+}
+
+// TxStats счетчики повторов транзакций
+type TxStats struct {
+	// Retries повторы после взаимной блокировки или ожидания блокировки
+	Retries int64 `json:"retries"`
+	// Exhausted транзакции, не выполненные после всех попыток
+	Exhausted int64 `json:"exhausted"`
+}
+
+// TxMetrics накапливает счетчики повторов транзакций
+type TxMetrics struct {
+	retries   atomic.Int64
+	exhausted atomic.Int64
+}
+
+func NewTxMetrics() *TxMetrics {
+	return &TxMetrics{}
+}
+
+func (m *TxMetrics) Stats() TxStats {
+	return TxStats{
+		Retries:   m.retries.Load(),
+		Exhausted: m.exhausted.Load(),
+	}
 }
 
 type gormTxManager struct {
-	db *gorm.DB
+	db           *gorm.DB
+	retryOptions RetryOptions
+	metrics      *TxMetrics
 }
 
-func NewGormTxManager(db *gorm.DB) *gormTxManager {
-	return &gormTxManager{
+func NewGormTxManager(db *gorm.DB, options ...TxManagerOption) *gormTxManager {
+	txm := &gormTxManager{
 		db: db,
+		retryOptions: RetryOptions{
+			maxRetries: 3,
+			baseDelay:  50 * time.Millisecond,
+			maxDelay:   time.Second,
+			jitter:     0.2,
+		},
+		metrics: NewTxMetrics(),
+	}
+	for _, option := range options {
+		option(txm)
 	}
+	return txm
 }
 
 func (txm *gormTxManager) Do(ctx context.Context, f func(context.Context) error) error {
@@ -44,15 +133,98 @@ func (txm *gormTxManager) Do(ctx context.Context, f func(context.Context) error)
 }
 
 func (txm *gormTxManager) DoWithSettings(ctx context.Context, txSettings TxSettings, f func(context.Context) error) error {
-	if existing := txm.getTxFromCtx(ctx); existing != nil {
+	existing := txm.getTxFromCtx(ctx)
+
+	switch txSettings.GetPropagation() {
+	case TxRequiresNew:
+		return txm.beginWithRetry(ctx, txSettings, f)
+	case TxNever:
+		if existing != nil {
+			return NewTechnicalError(nil, txManagerCode, "Операция не может выполняться внутри транзакции")
+		}
 		return f(ctx)
+	case TxNested:
+		if existing == nil {
+			return txm.beginWithRetry(ctx, txSettings, f)
+		}
+		if err := txm.checkJoin(ctx, txSettings); err != nil {
+			return err
+		}
+		return txm.savepoint(ctx, existing, f)
+	default:
+		if existing == nil {
+			return txm.beginWithRetry(ctx, txSettings, f)
+		}
+		if err := txm.checkJoin(ctx, txSettings); err != nil {
+			return err
+		}
+		return f(ctx)
+	}
+}
+
+// beginWithRetry выполняет транзакцию и повторяет её целиком, если MySQL откатил её из-за
+// взаимной блокировки или превышения ожидания блокировки. Во вложенных транзакциях и точках
+// сохранения повтор не выполняется: ошибка поднимается до транзакции верхнего уровня.
+// Функция f должна быть готова к повторному вызову, изменения вне базы данных не откатываются.
+// Действия AfterCommit и AfterRollback повторяемой попытки отбрасываются, повторный вызов f
+// регистрирует их заново
+func (txm *gormTxManager) beginWithRetry(ctx context.Context, txSettings TxSettings, f func(context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		hooks, err := txm.begin(ctx, txSettings, f)
+		if err == nil {
+			hooks.runAfterCommit(ctx)
+			return nil
+		}
+		if !IsTxRetryable(err) {
+			hooks.runAfterRollback(ctx)
+			return err
+		}
+
+		log := LoggerFromContext(ctx)
+		if attempt >= txm.retryOptions.maxRetries {
+			txm.metrics.exhausted.Add(1)
+			log.Error("Transaction retries exhausted", "attempt", attempt, "err", err)
+			hooks.runAfterRollback(ctx)
+			return err
+		}
+
+		delay := txm.retryOptions.backoff(attempt)
+		txm.metrics.retries.Add(1)
+		log.Warn("Retry transaction", "attempt", attempt, "delay", delay, "err", err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			hooks.runAfterRollback(ctx)
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
 	}
+}
 
+// IsTxRetryable сообщает, что транзакция откачена базой из-за конкурентного доступа
+// и может быть выполнена повторно
+func IsTxRetryable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+	return mysqlErr.Number == mysqlDeadlock ||
+		mysqlErr.Number == mysqlLockWaitTimeout ||
+		string(mysqlErr.SQLState[:]) == sqlStateSerialization
+}
+
+// begin открывает новую транзакцию, фиксирует её при успехе f и откатывает при ошибке или панике.
+// Возвращает действия, зарегистрированные в транзакции, их выполняет вызывающий
+func (txm *gormTxManager) begin(ctx context.Context, txSettings TxSettings, f func(context.Context) error) (*txHooks, error) {
+	hooks := &txHooks{}
 	tx := txm.db.Begin(&sql.TxOptions{
 		Isolation: txm.mapIsolationLevel(txSettings.GetIsolationLevel()),
+		ReadOnly:  txSettings.IsReadOnly(),
 	})
 	if err := tx.Error; err != nil {
-		return err
+		return hooks, err
 	}
 
 	defer func() {
@@ -60,21 +232,102 @@ func (txm *gormTxManager) DoWithSettings(ctx context.Context, txSettings TxSetti
 			if rollbackErr := tx.Rollback().Error; rollbackErr != nil {
 				slog.Error("failed to rollback on panic", "err", rollbackErr)
 			}
+			hooks.runAfterRollback(ctx)
 			panic(p)
 		}
 	}()
 
 	txCtx := context.WithValue(ctx, TxCtxKeyCode, tx)
+	txCtx = context.WithValue(txCtx, txStateCtxKey, &txState{
+		isolationLevel: txSettings.GetIsolationLevel(),
+		readOnly:       txSettings.IsReadOnly(),
+		hooks:          hooks,
+	})
 	err := f(txCtx)
 	if err != nil {
 		if rollbackErr := tx.Rollback().Error; rollbackErr != nil {
-			slog.Error("failed to rollback on panic", "err", rollbackErr)
+			slog.Error("failed to rollback", "err", rollbackErr)
 		}
-		return err
+		return hooks, err
 	}
 	if err := tx.Commit().Error; err != nil {
+		return hooks, err
+	}
+	return hooks, nil
+}
+
+// savepoint выполняет f внутри существующей транзакции и при ошибке или панике
+// откатывает только изменения, сделанные после точки сохранения. Действия AfterCommit точки
+// сохранения переносятся во внешнюю транзакцию, AfterRollback выполняются и при откате к точке
+func (txm *gormTxManager) savepoint(ctx context.Context, tx *gorm.DB, f func(context.Context) error) error {
+	state := txm.getTxStateFromCtx(ctx)
+	nested := txState{savepoints: 1}
+	if state != nil {
+		nested = *state
+		nested.savepoints++
+		nested.hooks = &txHooks{}
+	}
+	name := fmt.Sprintf("sp%d", nested.savepoints)
+
+	if err := tx.SavePoint(name).Error; err != nil {
 		return err
 	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			if rollbackErr := tx.RollbackTo(name).Error; rollbackErr != nil {
+				slog.Error("failed to rollback to savepoint on panic", "savepoint", name, "err", rollbackErr)
+			}
+			if nested.hooks != nil {
+				nested.hooks.runAfterRollback(ctx)
+			}
+			panic(p)
+		}
+	}()
+
+	err := f(context.WithValue(ctx, txStateCtxKey, &nested))
+	if err != nil {
+		if rollbackErr := tx.RollbackTo(name).Error; rollbackErr != nil {
+			slog.Error("failed to rollback to savepoint", "savepoint", name, "err", rollbackErr)
+			// откат к точке не выполнен, действия отката выполнятся вместе с внешней транзакцией
+			if nested.hooks != nil {
+				nested.hooks.afterCommit = nil
+				state.hooks.merge(nested.hooks)
+			}
+			return fmt.Errorf("%w (rollback to savepoint: %v)", err, rollbackErr)
+		}
+		if nested.hooks != nil {
+			nested.hooks.runAfterRollback(ctx)
+		}
+		return err
+	}
+	if nested.hooks != nil {
+		state.hooks.merge(nested.hooks)
+	}
+	return nil
+}
+
+// checkJoin проверяет, что существующая транзакция удовлетворяет настройкам: уровень изоляции
+// открытой транзакции изменить нельзя, поэтому он должен быть не слабее запрошенного,
+// а изменяющая операция не может присоединиться к транзакции только для чтения
+func (txm *gormTxManager) checkJoin(ctx context.Context, txSettings TxSettings) error {
+	state := txm.getTxStateFromCtx(ctx)
+	if state == nil {
+		return nil
+	}
+
+	if state.readOnly && !txSettings.IsReadOnly() {
+		return NewTechnicalError(nil, txManagerCode, "Невозможно выполнить изменяющую операцию в транзакции только для чтения")
+	}
+
+	requested := txSettings.GetIsolationLevel()
+	if requested != IsolationDefault && state.isolationLevel != IsolationDefault &&
+		isolationRank(state.isolationLevel) < isolationRank(requested) {
+		return NewTechnicalError(nil, txManagerCode, fmt.Sprintf(
+			"Уровень изоляции %s не может быть применен в открытой транзакции с уровнем %s",
+			requested, state.isolationLevel,
+		))
+	}
 	return nil
 }
 
@@ -86,33 +339,83 @@ func (txm *gormTxManager) getTxFromCtx(ctx context.Context) *gorm.DB {
 	return tx
 }
 
+func (txm *gormTxManager) getTxStateFromCtx(ctx context.Context) *txState {
+	state, ok := ctx.Value(txStateCtxKey).(*txState)
+	if !ok {
+		return nil
+	}
+	return state
+}
+
 func (txm *gormTxManager) mapIsolationLevel(isolationLevel string) sql.IsolationLevel {
 	switch isolationLevel {
-	case "Default":
+	case IsolationDefault:
 		return sql.LevelDefault
-	case "ReadCommitted":
+	case IsolationReadUncommitted:
+		return sql.LevelReadUncommitted
+	case IsolationReadCommitted:
 		return sql.LevelReadCommitted
-	case "Serializable":
+	case IsolationRepeatableRead:
+		return sql.LevelRepeatableRead
+	case IsolationSerializable:
 		return sql.LevelSerializable
 	default:
 		return sql.LevelReadCommitted
 	}
 }
 
+// isolationRank порядок уровней изоляции от слабого к строгому
+func isolationRank(isolationLevel string) int {
+	switch isolationLevel {
+	case IsolationReadUncommitted:
+		return 1
+	case IsolationRepeatableRead:
+		return 3
+	case IsolationSerializable:
+		return 4
+	default:
+		return 2
+	}
+}
+
+type TxManagerOption func(*gormTxManager)
+
+// SetTxRetryOpt настройки повтора транзакций, откаченных из-за взаимной блокировки.
+// SetMaxRetriesOpt задает общее число попыток, 1 - без повторов
+func SetTxRetryOpt(options ...RetryOption) TxManagerOption {
+	return func(txm *gormTxManager) {
+		for _, option := range options {
+			option(&txm.retryOptions)
+		}
+	}
+}
+
+// SetTxMetricsOpt счетчики, в которые записываются повторы транзакций
+func SetTxMetricsOpt(metrics *TxMetrics) TxManagerOption {
+	return func(txm *gormTxManager) {
+		txm.metrics = metrics
+	}
+}
+
 type gormTxSettings struct {
 	isolationLevel string
+	propagation    TxPropagation
+	readOnly       bool
 }
 
-func NewGormTxSettings(isolationLevel string) *gormTxSettings {
-	return &gormTxSettings{
+func NewGormTxSettings(isolationLevel string, options ...TxSettingsOption) *gormTxSettings {
+	txSettings := &gormTxSettings{
 		isolationLevel: isolationLevel,
+		propagation:    TxRequired,
 	}
+	for _, option := range options {
+		option(txSettings)
+	}
+	return txSettings
 }
 
-func DefaultGormTxSettings() *gormTxSettings {
-	return &gormTxSettings{
-		isolationLevel: "ReadCommitted",
-	}
+func DefaultGormTxSettings(options ...TxSettingsOption) *gormTxSettings {
+	return NewGormTxSettings(IsolationReadCommitted, options...)
 }
 
 func (txs *gormTxSettings) GetTxCtxKey() TxCtxKey {
@@ -122,3 +425,27 @@ func (txs *gormTxSettings) GetTxCtxKey() TxCtxKey {
 func (txs *gormTxSettings) GetIsolationLevel() string {
 	return txs.isolationLevel
 }
+
+func (txs *gormTxSettings) GetPropagation() TxPropagation {
+	return txs.propagation
+}
+
+func (txs *gormTxSettings) IsReadOnly() bool {
+	return txs.readOnly
+}
+
+type TxSettingsOption func(*gormTxSettings)
+
+// SetPropagationOpt поведение при наличии открытой транзакции, по умолчанию TxRequired
+func SetPropagationOpt(propagation TxPropagation) TxSettingsOption {
+	return func(txs *gormTxSettings) {
+		txs.propagation = propagation
+	}
+}
+
+// SetReadOnlyOpt открывает транзакцию только для чтения
+func SetReadOnlyOpt() TxSettingsOption {
+	return func(txs *gormTxSettings) {
+		txs.readOnly = true
+	}
+}
@@ -0,0 +1,432 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newTestTxManager(t *testing.T, options ...TxManagerOption) (*gormTxManager, *sqlRecorder) {
+	t.Helper()
+	db, recorder := newRecordingDB(t)
+	options = append([]TxManagerOption{SetTxRetryOpt(SetBaseDelayOpt(time.Millisecond))}, options...)
+	return NewGormTxManager(db, options...), recorder
+}
+
+// execInTx выполняет запрос в транзакции из контекста
+func execInTx(ctx context.Context, query string) error {
+	tx, ok := ctx.Value(TxCtxKeyCode).(*gorm.DB)
+	if !ok {
+		return errors.New("no transaction in context")
+	}
+	return tx.Exec(query).Error
+}
+
+// recordHooks регистрирует действия, которые записывают в журнал стадию и имя
+func recordHooks(ctx context.Context, recorder *sqlRecorder, name string) {
+	AfterCommit(ctx, func(context.Context) error {
+		recorder.add("after commit " + name)
+		return nil
+	})
+	AfterRollback(ctx, func(context.Context) error {
+		recorder.add("after rollback " + name)
+		return nil
+	})
+}
+
+var (
+	errTxTest   = errors.New("business failure")
+	errDeadlock = &mysql.MySQLError{Number: mysqlDeadlock, Message: "Deadlock found when trying to get lock"}
+)
+
+func TestTxManagerRequiredCommitsAndJoins(t *testing.T) {
+	txm, recorder := newTestTxManager(t)
+
+	err := txm.Do(context.Background(), func(ctx context.Context) error {
+		recordHooks(ctx, recorder, "outer")
+		require.NoError(t, execInTx(ctx, "UPDATE A"))
+		return txm.Do(ctx, func(ctx context.Context) error {
+			recordHooks(ctx, recorder, "inner")
+			return execInTx(ctx, "UPDATE B")
+		})
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"c1 BEGIN Read Committed",
+		"c1 UPDATE A",
+		"c1 UPDATE B",
+		"c1 COMMIT",
+		"after commit outer",
+		"after commit inner",
+	}, recorder.entries())
+}
+
+func TestTxManagerRollsBackOnError(t *testing.T) {
+	txm, recorder := newTestTxManager(t)
+
+	err := txm.Do(context.Background(), func(ctx context.Context) error {
+		recordHooks(ctx, recorder, "outer")
+		require.NoError(t, execInTx(ctx, "UPDATE A"))
+		return errTxTest
+	})
+
+	assert.ErrorIs(t, err, errTxTest)
+	assert.Equal(t, []string{
+		"c1 BEGIN Read Committed",
+		"c1 UPDATE A",
+		"c1 ROLLBACK",
+		"after rollback outer",
+	}, recorder.entries())
+}
+
+func TestTxManagerRollsBackOnPanic(t *testing.T) {
+	txm, recorder := newTestTxManager(t)
+
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = txm.Do(context.Background(), func(ctx context.Context) error {
+			recordHooks(ctx, recorder, "outer")
+			panic("boom")
+		})
+	})
+	assert.Equal(t, []string{
+		"c1 BEGIN Read Committed",
+		"c1 ROLLBACK",
+		"after rollback outer",
+	}, recorder.entries())
+}
+
+func TestTxManagerAppliesSettings(t *testing.T) {
+	txm, recorder := newTestTxManager(t)
+
+	err := txm.DoWithSettings(context.Background(), NewGormTxSettings(IsolationSerializable, SetReadOnlyOpt()), func(context.Context) error {
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c1 BEGIN Serializable READ ONLY", "c1 COMMIT"}, recorder.entries())
+}
+
+func TestTxManagerRequiresNewCommitsIndependently(t *testing.T) {
+	txm, recorder := newTestTxManager(t)
+
+	err := txm.Do(context.Background(), func(ctx context.Context) error {
+		recordHooks(ctx, recorder, "outer")
+		require.NoError(t, execInTx(ctx, "UPDATE A"))
+		err := txm.DoWithSettings(ctx, DefaultGormTxSettings(SetPropagationOpt(TxRequiresNew)), func(ctx context.Context) error {
+			recordHooks(ctx, recorder, "audit")
+			return execInTx(ctx, "INSERT AUDIT")
+		})
+		require.NoError(t, err)
+		return errTxTest
+	})
+
+	assert.ErrorIs(t, err, errTxTest)
+	assert.Equal(t, []string{
+		"c1 BEGIN Read Committed",
+		"c1 UPDATE A",
+		"c2 BEGIN Read Committed",
+		"c2 INSERT AUDIT",
+		"c2 COMMIT",
+		"after commit audit",
+		"c1 ROLLBACK",
+		"after rollback outer",
+	}, recorder.entries())
+}
+
+func TestTxManagerNestedRollsBackToSavepoint(t *testing.T) {
+	txm, recorder := newTestTxManager(t)
+	nested := DefaultGormTxSettings(SetPropagationOpt(TxNested))
+
+	err := txm.Do(context.Background(), func(ctx context.Context) error {
+		recordHooks(ctx, recorder, "outer")
+		require.NoError(t, execInTx(ctx, "UPDATE A"))
+		err := txm.DoWithSettings(ctx, nested, func(ctx context.Context) error {
+			recordHooks(ctx, recorder, "item")
+			require.NoError(t, execInTx(ctx, "UPDATE B"))
+			return errTxTest
+		})
+		assert.ErrorIs(t, err, errTxTest)
+		return execInTx(ctx, "UPDATE C")
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"c1 BEGIN Read Committed",
+		"c1 UPDATE A",
+		"c1 SAVEPOINT sp1",
+		"c1 UPDATE B",
+		"c1 ROLLBACK TO SAVEPOINT sp1",
+		"after rollback item",
+		"c1 UPDATE C",
+		"c1 COMMIT",
+		"after commit outer",
+	}, recorder.entries())
+}
+
+func TestTxManagerNestedSavepointDepth(t *testing.T) {
+	txm, recorder := newTestTxManager(t)
+	nested := DefaultGormTxSettings(SetPropagationOpt(TxNested))
+
+	err := txm.Do(context.Background(), func(ctx context.Context) error {
+		for range 2 {
+			err := txm.DoWithSettings(ctx, nested, func(ctx context.Context) error {
+				return txm.DoWithSettings(ctx, nested, func(ctx context.Context) error {
+					return execInTx(ctx, "UPDATE A")
+				})
+			})
+			require.NoError(t, err)
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"c1 BEGIN Read Committed",
+		"c1 SAVEPOINT sp1",
+		"c1 SAVEPOINT sp2",
+		"c1 UPDATE A",
+		"c1 SAVEPOINT sp1",
+		"c1 SAVEPOINT sp2",
+		"c1 UPDATE A",
+		"c1 COMMIT",
+	}, recorder.entries())
+}
+
+func TestTxManagerNestedMergesHooksIntoOuterTransaction(t *testing.T) {
+	nested := DefaultGormTxSettings(SetPropagationOpt(TxNested))
+
+	t.Run("outer commits", func(t *testing.T) {
+		txm, recorder := newTestTxManager(t)
+		err := txm.Do(context.Background(), func(ctx context.Context) error {
+			recordHooks(ctx, recorder, "outer")
+			err := txm.DoWithSettings(ctx, nested, func(ctx context.Context) error {
+				recordHooks(ctx, recorder, "item")
+				return nil
+			})
+			require.NoError(t, err)
+			recorder.add("outer continues")
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"c1 BEGIN Read Committed",
+			"c1 SAVEPOINT sp1",
+			"outer continues",
+			"c1 COMMIT",
+			"after commit outer",
+			"after commit item",
+		}, recorder.entries())
+	})
+
+	t.Run("outer rolls back", func(t *testing.T) {
+		txm, recorder := newTestTxManager(t)
+		err := txm.Do(context.Background(), func(ctx context.Context) error {
+			recordHooks(ctx, recorder, "outer")
+			err := txm.DoWithSettings(ctx, nested, func(ctx context.Context) error {
+				recordHooks(ctx, recorder, "item")
+				return nil
+			})
+			require.NoError(t, err)
+			return errTxTest
+		})
+
+		assert.ErrorIs(t, err, errTxTest)
+		assert.Equal(t, []string{
+			"c1 BEGIN Read Committed",
+			"c1 SAVEPOINT sp1",
+			"c1 ROLLBACK",
+			"after rollback outer",
+			"after rollback item",
+		}, recorder.entries())
+	})
+}
+
+func TestTxManagerNestedWithoutTransactionBegins(t *testing.T) {
+	txm, recorder := newTestTxManager(t)
+
+	err := txm.DoWithSettings(context.Background(), DefaultGormTxSettings(SetPropagationOpt(TxNested)), func(ctx context.Context) error {
+		return execInTx(ctx, "UPDATE A")
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c1 BEGIN Read Committed", "c1 UPDATE A", "c1 COMMIT"}, recorder.entries())
+}
+
+func TestTxManagerNever(t *testing.T) {
+	txm, recorder := newTestTxManager(t)
+	never := DefaultGormTxSettings(SetPropagationOpt(TxNever))
+
+	called := false
+	err := txm.DoWithSettings(context.Background(), never, func(ctx context.Context) error {
+		called = true
+		AfterCommit(ctx, func(context.Context) error {
+			recorder.add("after commit immediately")
+			return nil
+		})
+		return nil
+	})
+	require.NoError(t, err)
+	assert.True(t, called)
+	assert.Equal(t, []string{"after commit immediately"}, recorder.entries())
+
+	err = txm.Do(context.Background(), func(ctx context.Context) error {
+		return txm.DoWithSettings(ctx, never, func(context.Context) error {
+			t.Fatal("must not be called inside transaction")
+			return nil
+		})
+	})
+	var technicalErr *TechnicalError
+	assert.ErrorAs(t, err, &technicalErr)
+}
+
+func TestTxManagerCheckJoin(t *testing.T) {
+	txm, _ := newTestTxManager(t)
+
+	err := txm.DoWithSettings(context.Background(), DefaultGormTxSettings(SetReadOnlyOpt()), func(ctx context.Context) error {
+		return txm.Do(ctx, func(context.Context) error { return nil })
+	})
+	var technicalErr *TechnicalError
+	assert.ErrorAs(t, err, &technicalErr, "write inside read-only transaction")
+
+	err = txm.Do(context.Background(), func(ctx context.Context) error {
+		return txm.DoWithSettings(ctx, NewGormTxSettings(IsolationSerializable), func(context.Context) error { return nil })
+	})
+	assert.ErrorAs(t, err, &technicalErr, "stronger isolation in open transaction")
+
+	err = txm.DoWithSettings(context.Background(), NewGormTxSettings(IsolationSerializable), func(ctx context.Context) error {
+		return txm.DoWithSettings(ctx, DefaultGormTxSettings(SetReadOnlyOpt()), func(context.Context) error { return nil })
+	})
+	assert.NoError(t, err)
+}
+
+func TestTxManagerRetriesDeadlock(t *testing.T) {
+	metrics := NewTxMetrics()
+	txm, recorder := newTestTxManager(t, SetTxMetricsOpt(metrics))
+	recorder.fail("UPDATE", errDeadlock)
+
+	calls := 0
+	err := txm.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		recordHooks(ctx, recorder, fmt.Sprintf("attempt %d", calls))
+		return execInTx(ctx, "UPDATE A")
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, []string{
+		"c1 BEGIN Read Committed",
+		"c1 UPDATE A",
+		"c1 ROLLBACK",
+		"c1 BEGIN Read Committed",
+		"c1 UPDATE A",
+		"c1 COMMIT",
+		"after commit attempt 2",
+	}, recorder.entries())
+	assert.Equal(t, TxStats{Retries: 1}, metrics.Stats())
+}
+
+func TestTxManagerRetriesExhausted(t *testing.T) {
+	metrics := NewTxMetrics()
+	txm, recorder := newTestTxManager(t, SetTxMetricsOpt(metrics), SetTxRetryOpt(SetMaxRetriesOpt(2)))
+	recorder.fail("COMMIT", errDeadlock)
+	recorder.fail("COMMIT", errDeadlock)
+
+	calls := 0
+	err := txm.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		recordHooks(ctx, recorder, fmt.Sprintf("attempt %d", calls))
+		return nil
+	})
+
+	assert.True(t, IsTxRetryable(err))
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, "after rollback attempt 2", recorder.entries()[len(recorder.entries())-1])
+	assert.Equal(t, TxStats{Retries: 1, Exhausted: 1}, metrics.Stats())
+}
+
+func TestTxManagerDoesNotRetryInsideTransaction(t *testing.T) {
+	txm, recorder := newTestTxManager(t)
+	recorder.fail("UPDATE B", errDeadlock)
+
+	calls := 0
+	err := txm.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		return txm.DoWithSettings(ctx, DefaultGormTxSettings(SetPropagationOpt(TxNested)), func(ctx context.Context) error {
+			return execInTx(ctx, "UPDATE B")
+		})
+	})
+
+	// повторяется вся транзакция верхнего уровня, а не точка сохранения
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, []string{
+		"c1 BEGIN Read Committed",
+		"c1 SAVEPOINT sp1",
+		"c1 UPDATE B",
+		"c1 ROLLBACK TO SAVEPOINT sp1",
+		"c1 ROLLBACK",
+		"c1 BEGIN Read Committed",
+		"c1 SAVEPOINT sp1",
+		"c1 UPDATE B",
+		"c1 COMMIT",
+	}, recorder.entries())
+}
+
+func TestIsTxRetryable(t *testing.T) {
+	assert.True(t, IsTxRetryable(fmt.Errorf("update: %w", errDeadlock)))
+	assert.True(t, IsTxRetryable(&mysql.MySQLError{Number: mysqlLockWaitTimeout}))
+	assert.True(t, IsTxRetryable(&mysql.MySQLError{Number: 1, SQLState: [5]byte{'4', '0', '0', '0', '1'}}))
+	assert.False(t, IsTxRetryable(&mysql.MySQLError{Number: 1062}))
+	assert.False(t, IsTxRetryable(errTxTest))
+}
+
+func TestTxHooksIgnoreFailures(t *testing.T) {
+	ctx, commit, _ := testTxContext(context.Background())
+	var ran []string
+	AfterCommit(ctx, func(context.Context) error {
+		ran = append(ran, "first")
+		return errTxTest
+	})
+	AfterCommit(ctx, func(context.Context) error {
+		panic("boom")
+	})
+	AfterCommit(ctx, func(context.Context) error {
+		ran = append(ran, "third")
+		return nil
+	})
+
+	commit()
+	assert.Equal(t, []string{"first", "third"}, ran)
+}
+
+func TestTxHooksRunWithoutCancel(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	ctx, commit, _ := testTxContext(parent)
+	cancel()
+
+	var hookErr error
+	AfterCommit(ctx, func(ctx context.Context) error {
+		hookErr = ctx.Err()
+		return nil
+	})
+	commit()
+	assert.NoError(t, hookErr)
+}
+
+func TestAfterRollbackOutsideTransactionIsIgnored(t *testing.T) {
+	called := false
+	AfterRollback(context.Background(), func(context.Context) error {
+		called = true
+		return nil
+	})
+	assert.False(t, called)
+}
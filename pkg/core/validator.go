@@ -0,0 +1,66 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/ru"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	entranslations "github.com/go-playground/validator/v10/translations/en"
+	rutranslations "github.com/go-playground/validator/v10/translations/ru"
+)
+
+var validationTranslations = map[string]func(*validator.Validate, ut.Translator) error{
+	LangRU: rutranslations.RegisterDefaultTranslations,
+	LangEN: entranslations.RegisterDefaultTranslations,
+}
+
+var universalTranslator = sync.OnceValue(func() *ut.UniversalTranslator {
+	return ut.New(ru.New(), ru.New(), en.New())
+})
+
+// defaultValidator переводы регистрируются один раз: повторная регистрация в общем переводчике
+// завершается ошибкой, а валидатор безопасен для конкурентного использования
+var defaultValidator = sync.OnceValue(func() *validator.Validate {
+	validate := validator.New()
+	for lang, register := range validationTranslations {
+		if err := register(validate, validationTranslator(lang)); err != nil {
+			slog.Error("Failed to register validation translations", "lang", lang, "err", err)
+		}
+	}
+	return validate
+})
+
+// DefaultValidator возвращает общий валидатор с переводами сообщений на поддерживаемые языки.
+// Переводы привязаны к экземпляру валидатора, поэтому обработчики получают валидатор только через DefaultValidator
+func DefaultValidator() *validator.Validate {
+	return defaultValidator()
+}
+
+func validationTranslator(lang string) ut.Translator {
+	translator, ok := universalTranslator().GetTranslator(lang)
+	if !ok {
+		translator, _ = universalTranslator().GetTranslator(DefaultLang)
+	}
+	return translator
+}
+
+// CollectValidationDetails возвращает сообщения об ошибках по полям на языке запроса
+func CollectValidationDetails(ctx context.Context, err error) map[string]string {
+	details := make(map[string]string)
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return details
+	}
+
+	translator := validationTranslator(LanguageFromContext(ctx))
+	for _, validationError := range validationErrors {
+		details[validationError.Field()] = validationError.Translate(translator)
+	}
+	return details
+}
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validatorTestRequest struct {
+	Code  string `json:"code" validate:"required"`
+	Count int    `json:"count" validate:"gte=1"`
+}
+
+func TestDefaultValidatorIsShared(t *testing.T) {
+	assert.Same(t, DefaultValidator(), DefaultValidator())
+}
+
+func TestCollectValidationDetailsTranslates(t *testing.T) {
+	err := DefaultValidator().Struct(validatorTestRequest{})
+	require.Error(t, err)
+
+	tests := []struct {
+		lang  string
+		code  string
+		count string
+	}{
+		{lang: LangEN, code: "Code is a required field", count: "Count must be 1 or greater"},
+		{lang: LangRU, code: "Code обязательное поле", count: "Count должен быть больше или равно 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			details := CollectValidationDetails(WithLanguage(context.Background(), tt.lang), err)
+			assert.Equal(t, map[string]string{"Code": tt.code, "Count": tt.count}, details)
+		})
+	}
+
+	// вне запроса сообщения на языке по умолчанию
+	assert.Equal(t,
+		CollectValidationDetails(WithLanguage(context.Background(), DefaultLang), err),
+		CollectValidationDetails(context.Background(), err),
+	)
+}
+
+func TestCollectValidationDetailsUnwrapsErrors(t *testing.T) {
+	err := DefaultValidator().Struct(validatorTestRequest{Code: "a"})
+	require.Error(t, err)
+
+	details := CollectValidationDetails(context.Background(), NewLogicalError(err, "TEST_HANDLER", err.Error()))
+	assert.Len(t, details, 1)
+	assert.Contains(t, details, "Count")
+}
+
+func TestCollectValidationDetailsIgnoresOtherErrors(t *testing.T) {
+	assert.Empty(t, CollectValidationDetails(context.Background(), errors.New("decode failed")))
+}
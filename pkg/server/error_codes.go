@@ -0,0 +1,21 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ActuallyHello/backendstory/pkg/core"
+)
+
+// ErrorCodesResponse реестр кодов ошибок, которые возвращает API в поле code
+// @Name ErrorCodesResponse
+type ErrorCodesResponse struct {
+	Codes []core.ErrorCodeInfo `json:"codes"`
+}
+
+// errorCodesHandler возвращает все коды ошибок с HTTP статусом и описанием на языке запроса
+func errorCodesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(ErrorCodesResponse{Codes: core.ErrorCodes(r.Context())})
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ActuallyHello/backendstory/pkg/container"
+	"github.com/ActuallyHello/backendstory/pkg/core"
+)
+
+const (
+	healthStatusOK       = "ok"
+	healthStatusDegraded = "degraded"
+	healthStatusDown     = "down"
+)
+
+// HealthResponse состояние приложения и его зависимостей
+// @Name HealthResponse
+type HealthResponse struct {
+	// Status ok - все зависимости доступны, degraded - разомкнут размыкатель внешнего сервиса,
+	// down - недоступна база данных
+	Status          string                     `json:"status"`
+	Database        string                     `json:"database"`
+	CircuitBreakers []core.CircuitBreakerStats `json:"circuit_breakers"`
+}
+
+// MetricsResponse счетчики вызовов внешних сервисов и повторов транзакций
+// @Name MetricsResponse
+type MetricsResponse struct {
+	CircuitBreakers []core.CircuitBreakerStats `json:"circuit_breakers"`
+	Transactions    core.TxStats               `json:"transactions"`
+}
+
+// healthHandler проверяет базу данных и состояние размыкателей.
+// Недоступность базы возвращает 503, разомкнутый размыкатель - 200 со статусом degraded
+func healthHandler(container *container.AppContainer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response := HealthResponse{
+			Status:          healthStatusOK,
+			Database:        healthStatusOK,
+			CircuitBreakers: circuitBreakerStats(container),
+		}
+		for _, stats := range response.CircuitBreakers {
+			if stats.State != core.CircuitClosed {
+				response.Status = healthStatusDegraded
+			}
+		}
+
+		status := http.StatusOK
+		if err := container.HealthCheck(); err != nil {
+			core.LoggerFromContext(r.Context()).Error("Health check failed", "err", err)
+			response.Status = healthStatusDown
+			response.Database = healthStatusDown
+			status = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+func metricsHandler(container *container.AppContainer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(MetricsResponse{
+			CircuitBreakers: circuitBreakerStats(container),
+			Transactions:    container.GetTxStats(),
+		})
+	}
+}
+
+func circuitBreakerStats(container *container.AppContainer) []core.CircuitBreakerStats {
+	breakers := container.GetCircuitBreakers()
+	stats := make([]core.CircuitBreakerStats, 0, len(breakers))
+	for _, breaker := range breakers {
+		stats = append(stats, breaker.Stats())
+	}
+	return stats
+}
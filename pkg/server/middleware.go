@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"slices"
 	"strings"
@@ -11,9 +12,8 @@ import (
 )
 
 const (
-	authMiddleware = "AUTH_MIDDLEWARE_CODE"
-	authorization  = "Authorization"
-	bearer         = "Bearer "
+	authorization = "Authorization"
+	bearer        = "Bearer "
 )
 
 func AuthMiddleware(authService auth.AuthService, requiredRoles ...string) func(http.Handler) http.Handler {
@@ -23,14 +23,20 @@ func AuthMiddleware(authService auth.AuthService, requiredRoles ...string) func(
 
 			authHeader := r.Header.Get(authorization)
 			if authHeader == "" {
-				core.HandleError(w, r, core.NewAccessError(nil, authMiddleware, "Не указан токен авторизации"))
+				core.HandleError(w, r, core.NewAccessError(nil, core.AuthTokenMissingCode, "Не указан токен авторизации"))
 				return
 			}
 
 			token := strings.TrimPrefix(authHeader, bearer)
 			tokenUserInfo, err := authService.GetTokenUserInfo(ctx, token)
 			if err != nil {
-				core.HandleError(w, r, core.NewAccessError(err, authMiddleware, "Ошибка при получении ролей пользователя"))
+				// недоступность сервиса авторизации не означает, что токен недействителен
+				var techErr *core.TechnicalError
+				if errors.As(err, &techErr) {
+					core.HandleError(w, r, err)
+					return
+				}
+				core.HandleError(w, r, core.NewAccessError(err, core.AuthTokenInvalidCode, "Токен авторизации недействителен или истек"))
 				return
 			}
 			roles := tokenUserInfo.Roles
@@ -39,7 +45,7 @@ func AuthMiddleware(authService auth.AuthService, requiredRoles ...string) func(
 			ctx = context.WithValue(ctx, auth.UserInfoCtxKey, tokenUserInfo)
 
 			if !hasRequiredRole(roles, requiredRoles) {
-				core.HandleError(w, r, core.NewAccessError(nil, authMiddleware, "Для данной роли доступ запрещён"))
+				core.HandleError(w, r, core.NewForbiddenError(nil, core.AuthRoleForbiddenCode, "Для данной роли доступ запрещён"))
 				return
 			}
 
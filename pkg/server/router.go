@@ -3,6 +3,7 @@ package server
 import (
 	"net/http"
 
+	"github.com/ActuallyHello/backendstory/pkg/backendstory/audit"
 	"github.com/ActuallyHello/backendstory/pkg/backendstory/auth"
 	"github.com/ActuallyHello/backendstory/pkg/backendstory/cart"
 	cartitem "github.com/ActuallyHello/backendstory/pkg/backendstory/cart_item"
@@ -14,6 +15,7 @@ import (
 	"github.com/ActuallyHello/backendstory/pkg/backendstory/person"
 	"github.com/ActuallyHello/backendstory/pkg/backendstory/product"
 	productmedia "github.com/ActuallyHello/backendstory/pkg/backendstory/product_media"
+	"github.com/ActuallyHello/backendstory/pkg/backendstory/webhook"
 	"github.com/ActuallyHello/backendstory/pkg/container"
 	"github.com/ActuallyHello/backendstory/pkg/core"
 	"github.com/go-chi/chi/v5"
@@ -33,6 +35,7 @@ func SetupRouter(container *container.AppContainer, staticDir string) (http.Hand
 
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
+	r.Use(core.LanguageMiddleware)
 	r.Use(core.LoggerContextMiddleware)
 	r.Use(core.AccessLogMiddleware)
 	r.Use(core.ErrorHandler)
@@ -54,7 +57,6 @@ func SetupRouter(container *container.AppContainer, staticDir string) (http.Hand
 		// TODO: ENUM CONSTAT BY EACH ENTITY.go
 		// TODO: common method with approve/cancel order actions
 
-		// TODO: find enumvalues batch for mapping entities
 		// TODO: convert entity - not dto
 
 		registerAuthRoutes(r, container.GetAuthService(), container.GetAuthHandler())
@@ -69,6 +71,8 @@ func SetupRouter(container *container.AppContainer, staticDir string) (http.Hand
 		registerCartItemRoutes(r, container.GetAuthService(), container.GetCartItemHandler())
 		registerOrderRoutes(r, container.GetAuthService(), container.GetOrderHandler())
 		registerOrderItemRoutes(r, container.GetAuthService(), container.GetOrderItemHandler())
+		registerAuditRoutes(r, container.GetAuthService(), container.GetAuditLogHandler())
+		registerWebhookRoutes(r, container.GetAuthService(), container.GetWebhookHandler())
 	})
 
 	r.Get("/swagger/doc.json", func(w http.ResponseWriter, r *http.Request) {
@@ -81,9 +85,9 @@ func SetupRouter(container *container.AppContainer, staticDir string) (http.Hand
 		httpSwagger.URL("/swagger/doc.json"),
 	))
 
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte("ok"))
-	})
+	r.Get("/health", healthHandler(container))
+	r.Get("/metrics", metricsHandler(container))
+	r.Get("/error-codes", errorCodesHandler)
 
 	return r, nil
 }
@@ -95,6 +99,7 @@ func registerEnumRoutes(r chi.Router, authService auth.AuthService, enumHandler
 		r.Get("/", enumHandler.GetAll)
 		r.Get(byId, enumHandler.GetById)
 		r.Get("/code/{code}", enumHandler.GetByCode)
+		r.Get("/cache-stats", enumHandler.CacheStats)
 		r.Post("/search", enumHandler.GetWithSearchCriteria)
 
 		// Защищенные маршруты (требуют аутентификации)
@@ -114,6 +119,7 @@ func registerEnumValuesRoutes(r chi.Router, authService auth.AuthService, enumVa
 		r.Get("/", enumValueHandler.GetAll)
 		r.Get(byId, enumValueHandler.GetById)
 		r.Get("/enumeration/{enumeration_id}", enumValueHandler.GetByEnumId)
+		r.Get("/cache-stats", enumValueHandler.CacheStats)
 		r.Post("/search", enumValueHandler.GetWithSearchCriteria)
 
 		r.Group(func(r chi.Router) {
@@ -139,6 +145,8 @@ func registerPersonRoutes(r chi.Router, authService auth.AuthService, personHand
 			r.Get("/", personHandler.GetAll)
 			r.Post("/", personHandler.Create)
 			r.Delete(byId, personHandler.Delete)
+			r.Post(byId+"/restore", personHandler.Restore)
+			r.Delete(byId+"/purge", personHandler.Purge)
 		})
 	})
 }
@@ -173,6 +181,7 @@ func registerCategoryRoutes(r chi.Router, authService auth.AuthService, category
 			r.Use(AuthMiddleware(authService, "admin", "guest"))
 
 			r.Post("/", categoryHandler.Create)
+			r.Post("/batch", categoryHandler.Batch)
 			r.Delete("/{id}", categoryHandler.Delete)
 		})
 	})
@@ -192,9 +201,18 @@ func registerProductRoutes(r chi.Router, authService auth.AuthService, productHa
 			r.Use(AuthMiddleware(authService, "admin", "guest"))
 
 			r.Post("/", productHandler.Create)
+			r.Post("/batch", productHandler.Batch)
 			r.Post("/change-status", productHandler.ChangeStatus)
 			r.Post("/change-price", productHandler.ChangePrice)
 			r.Delete("/{id}", productHandler.Delete)
+			r.Post("/{id}/restore", productHandler.Restore)
+
+			r.Group(func(r chi.Router) {
+				r.Use(AuthMiddleware(authService, "admin"))
+
+				r.Get("/deleted", productHandler.GetDeleted)
+				r.Delete("/{id}/purge", productHandler.Purge)
+			})
 		})
 
 	})
@@ -228,6 +246,7 @@ func registerCartRoutes(r chi.Router, authService auth.AuthService, cartHandler
 	r.Route("/carts", func(r chi.Router) {
 		r.Use(AuthMiddleware(authService, "admin", "guest"))
 
+		r.Get("/", cartHandler.GetAll)
 		r.Get("/{id}", cartHandler.GetById)
 		r.Get("/person/{person_id}", cartHandler.GetByPersonID)
 		r.Post("/search", cartHandler.GetWithSearchCriteria)
@@ -240,6 +259,7 @@ func registerCartItemRoutes(r chi.Router, authService auth.AuthService, cartItem
 	r.Route("/cart-items", func(r chi.Router) {
 		r.Use(AuthMiddleware(authService, "admin", "guest"))
 
+		r.Get("/", cartItemHandler.GetAll)
 		r.Get("/{id}", cartItemHandler.GetById)
 		r.Get("/cart/{cart_id}", cartItemHandler.GetByCartID)
 		r.Post("/search", cartItemHandler.GetWithSearchCriteria)
@@ -254,6 +274,7 @@ func registerOrderRoutes(r chi.Router, authService auth.AuthService, orderHandle
 	r.Route("/orders", func(r chi.Router) {
 		r.Use(AuthMiddleware(authService, "admin", "guest"))
 
+		r.Get("/", orderHandler.GetAll)
 		r.Get("/{id}", orderHandler.GetById)
 		r.Get("/client/{client_id}", orderHandler.GetByClientID)
 		r.Get("/manager/{manager_id}", orderHandler.GetByManagerID)
@@ -277,6 +298,7 @@ func registerOrderItemRoutes(r chi.Router, authService auth.AuthService, orderIt
 	r.Route("/order-items", func(r chi.Router) {
 		r.Use(AuthMiddleware(authService, "admin", "guest"))
 
+		r.Get("/", orderItemHandler.GetAll)
 		r.Get("/{id}", orderItemHandler.GetById)
 		r.Get("/order/{order_id}", orderItemHandler.GetByOrderID)
 		r.Post("/search", orderItemHandler.GetWithSearchCriteria)
@@ -292,6 +314,33 @@ func registerOrderItemRoutes(r chi.Router, authService auth.AuthService, orderIt
 	})
 }
 
+func registerAuditRoutes(r chi.Router, authService auth.AuthService, auditLogHandler *audit.AuditLogHandler) {
+	r.Route("/audit", func(r chi.Router) {
+		r.Use(AuthMiddleware(authService, "admin"))
+
+		r.Get("/", auditLogHandler.GetAll)
+		r.Get(byId, auditLogHandler.GetById)
+		r.Post("/search", auditLogHandler.GetWithSearchCriteria)
+	})
+}
+
+func registerWebhookRoutes(r chi.Router, authService auth.AuthService, webhookHandler *webhook.WebhookHandler) {
+	r.Route("/webhooks", func(r chi.Router) {
+		r.Use(AuthMiddleware(authService, "admin"))
+
+		r.Get("/", webhookHandler.GetAll)
+		r.Get(byId, webhookHandler.GetById)
+		r.Post("/search", webhookHandler.GetWithSearchCriteria)
+		r.Post("/", webhookHandler.Create)
+		r.Patch("/", webhookHandler.Update)
+		r.Delete(byId, webhookHandler.Delete)
+
+		r.Get("/deliveries", webhookHandler.GetDeliveries)
+		r.Get("/deliveries"+byId, webhookHandler.GetDeliveryById)
+		r.Post("/deliveries"+byId+"/redeliver", webhookHandler.Redeliver)
+	})
+}
+
 func RegisterSwaggerRoutes(router chi.Router) {
 	// Настройка Swagger
 	swaggerHandler := httpSwagger.Handler(